@@ -7,9 +7,11 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/username/shorturl/internal/auth"
 	"github.com/username/shorturl/internal/config"
 	internal "github.com/username/shorturl/internal/handler"
 	"github.com/username/shorturl/internal/manager"
+	"github.com/username/shorturl/internal/tenant"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -36,6 +38,9 @@ func main() {
 	g.Go(func() error {
 		opts := []grpc.DialOption{
 			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			// 附加网关的服务密钥，并把终端用户的认证身份和选择的工作区转发给后端
+			grpc.WithChainUnaryInterceptor(auth.UnaryClientInterceptor(config.GetConfig().Auth.ServiceKey), tenant.UnaryClientInterceptor()),
+			grpc.WithChainStreamInterceptor(auth.StreamClientInterceptor(config.GetConfig().Auth.ServiceKey), tenant.StreamClientInterceptor()),
 		}
 		log.Println("Initializing services...")
 		err := cliManager.InitServices(gCtx, grpcAddrs, opts...)
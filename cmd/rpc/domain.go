@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/username/shorturl/internal/config"
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+	domain "github.com/username/shorturl/internal/service/domain"
+)
+
+const domainUsage = `usage: rpc domain <add|verify|set-primary|remove|list> [flags]
+
+  add -host H -workspace S [-primary]    把域名登记到工作区，输出验证所需的 TXT 记录
+  verify -host H                         验证域名所有权，通过后域名开始用于跳转
+  set-primary -host H                    设为所在工作区生成短链接地址时使用的域名
+  remove -host H                         删除域名
+  list [-workspace S]                    查看域名
+
+flags:
+`
+
+// runDomain 执行 domain 子命令
+func runDomain(args []string) error {
+	fs := flag.NewFlagSet("domain", flag.ContinueOnError)
+	host := fs.String("host", "", "域名，如 go.brand-a.com")
+	workspaceSlug := fs.String("workspace", "", "工作区 slug")
+	primary := fs.Bool("primary", false, "add 时同时设为工作区的主域名")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), domainUsage)
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		return errors.New("missing domain action")
+	}
+	action := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if action != "list" && *host == "" {
+		return errors.New("-host is required")
+	}
+
+	cfg := config.GetConfig()
+	dataSources := repository.NewDataSources(cfg)
+	domains := repository.NewDomainRepository(dataSources)
+	workspaces := repository.NewWorkspaceRepository(dataSources)
+	verifier, err := domain.NewVerifier(cfg.Domains.Verifier)
+	if err != nil {
+		return err
+	}
+	registry := domain.NewRegistry(domains, workspaces, verifier, cfg.Domains.BaseURL, cfg.Domains.Scheme)
+	ctx := context.Background()
+
+	switch action {
+	case "add":
+		if *workspaceSlug == "" {
+			return errors.New("-workspace is required")
+		}
+		d, err := registry.Add(ctx, *host, *workspaceSlug, *primary)
+		if err != nil {
+			return err
+		}
+		name, value := domain.VerificationRecord(d.Hostname, d.VerificationToken)
+		fmt.Printf("registered %s to workspace %s\n", d.Hostname, *workspaceSlug)
+		fmt.Printf("publish TXT record %s with value %q, then run: rpc domain verify -host %s\n", name, value, d.Hostname)
+	case "verify":
+		if err := registry.Verify(ctx, *host); err != nil {
+			return err
+		}
+		fmt.Printf("%s verified\n", domain.NormalizeHost(*host))
+	case "set-primary":
+		if err := domains.SetPrimary(ctx, domain.NormalizeHost(*host)); err != nil {
+			return err
+		}
+		fmt.Printf("%s is now the primary domain of its workspace\n", domain.NormalizeHost(*host))
+	case "remove":
+		if err := domains.Delete(ctx, domain.NormalizeHost(*host)); err != nil {
+			return err
+		}
+		fmt.Printf("%s removed\n", domain.NormalizeHost(*host))
+	case "list":
+		workspaceID := int64(-1)
+		if *workspaceSlug != "" {
+			ws, err := workspaces.GetBySlug(ctx, *workspaceSlug)
+			if err != nil {
+				return err
+			}
+			workspaceID = ws.ID
+		}
+		list, err := domains.ListByWorkspace(ctx, workspaceID)
+		if err != nil {
+			return err
+		}
+		printDomains(list)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown domain action: %s", action)
+	}
+	return nil
+}
+
+func printDomains(list []model.Domain) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "HOSTNAME\tWORKSPACE ID\tPRIMARY\tVERIFIED AT")
+	for _, d := range list {
+		verifiedAt := "pending"
+		if d.VerifiedAt != nil {
+			verifiedAt = d.VerifiedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%s\t%d\t%t\t%s\n", d.Hostname, d.WorkspaceID, d.Primary, verifiedAt)
+	}
+	w.Flush()
+}
@@ -8,8 +8,13 @@ import (
 	"syscall"
 
 	"github.com/username/shorturl/internal/config"
+	"github.com/username/shorturl/internal/db/migrate"
 	"github.com/username/shorturl/internal/manager"
+	"github.com/username/shorturl/internal/repository"
 	"github.com/username/shorturl/internal/rpc"
+	analytics "github.com/username/shorturl/internal/service/analytics"
+	reconcile "github.com/username/shorturl/internal/service/reconcile"
+	writebehind "github.com/username/shorturl/internal/service/writebehind"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -19,6 +24,38 @@ func main() {
 	config.LoadAll()
 	log.Println("Configuration loaded")
 
+	// migrate 子命令：只执行迁移，不启动服务
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatalf("migrate failed: %v", err)
+		}
+		return
+	}
+	// workspace 子命令：管理工作区与成员
+	if len(os.Args) > 1 && os.Args[1] == "workspace" {
+		if err := runWorkspace(os.Args[2:]); err != nil {
+			log.Fatalf("workspace failed: %v", err)
+		}
+		return
+	}
+	// domain 子命令：管理自定义域名
+	if len(os.Args) > 1 && os.Args[1] == "domain" {
+		if err := runDomain(os.Args[2:]); err != nil {
+			log.Fatalf("domain failed: %v", err)
+		}
+		return
+	}
+
+	if config.GetConfig().AutoMigrate {
+		dataSources, err := repository.GetDataSources()
+		if err != nil {
+			log.Fatalf("获取数据源失败: %v", err)
+		}
+		if err := migrate.UpAll(context.Background(), dataSources.PrimaryDB, dataSources.SQLiteDB); err != nil {
+			log.Fatalf("数据库迁移失败: %v", err)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	// 延迟取消操作保留，作为主函数退出的二次保障，但不是退出流程的触发器。
 
@@ -45,7 +82,31 @@ func main() {
 		return rpc.RunGRPCServer(ctx, cliManager)
 	})
 
-	// 3. 监听关闭信号
+	// 3. 启动 SQLite 回退数据的对账
+	if config.GetConfig().Reconcile.Enabled {
+		g.Go(func() error {
+			reconcile.GetReconciler().Run(gCtx)
+			return nil
+		})
+	}
+
+	// 4. 从数据库构建已存在短码的 Bloom filter，并定期重建
+	if dataSources, err := repository.GetDataSources(); err == nil && dataSources.KnownCodes != nil {
+		g.Go(func() error {
+			dataSources.KnownCodes.Run(gCtx, dataSources, config.GetConfig().BloomFilter.RebuildInterval)
+			return nil
+		})
+	}
+
+	// 5. 订阅其他实例的缓存失效广播
+	if dataSources, err := repository.GetDataSources(); err == nil && dataSources.Invalidation != nil {
+		g.Go(func() error {
+			dataSources.Invalidation.Run(gCtx)
+			return nil
+		})
+	}
+
+	// 6. 监听关闭信号
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
@@ -71,4 +132,8 @@ func main() {
 	} else {
 		log.Println("All services shut down gracefully.")
 	}
+
+	// gRPC Server 停止后不会再有新的点击和短链接，把队列中剩余的数据写入数据库
+	writebehind.GetWriter().Close()
+	analytics.GetRecorder().Close()
 }
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/username/shorturl/internal/config"
+	"github.com/username/shorturl/internal/db"
+	"github.com/username/shorturl/internal/db/migrate"
+	"github.com/username/shorturl/internal/repository"
+)
+
+const migrateUsage = `usage: rpc migrate <up|down|status> [flags]
+
+  up                 执行所有未执行的迁移
+  down [-steps N]    回滚最近 N 个迁移（默认 1）
+  status             查看各版本的执行状态
+
+flags:
+`
+
+// runMigrate 执行 migrate 子命令
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	target := fs.String("db", "all", "目标数据库：mysql | postgres | sqlite | all（mysql 和 postgres 需与 PrimaryDatabase 一致）")
+	steps := fs.Int("steps", 1, "down 回滚的版本数")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), migrateUsage)
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		return errors.New("missing migrate action")
+	}
+	action := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	databases, err := migrateTargets(*target)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for _, database := range databases {
+		m, err := migrate.New(database)
+		if err != nil {
+			return err
+		}
+		switch action {
+		case "up":
+			applied, err := m.Up(ctx)
+			for _, mig := range applied {
+				fmt.Printf("[%s] applied %d_%s\n", database.Dialect(), mig.Version, mig.Name)
+			}
+			if err != nil {
+				return err
+			}
+			if len(applied) == 0 {
+				fmt.Printf("[%s] already up to date\n", database.Dialect())
+			}
+		case "down":
+			rolledBack, err := m.Down(ctx, *steps)
+			for _, mig := range rolledBack {
+				fmt.Printf("[%s] rolled back %d_%s\n", database.Dialect(), mig.Version, mig.Name)
+			}
+			if err != nil {
+				return err
+			}
+		case "status":
+			statuses, err := m.Status(ctx)
+			if err != nil {
+				return err
+			}
+			printStatus(database.Dialect(), statuses)
+		default:
+			fs.Usage()
+			return fmt.Errorf("unknown migrate action: %s", action)
+		}
+	}
+	return nil
+}
+
+// migrateTargets 按 -db 参数选择已配置且可连接的数据库
+func migrateTargets(target string) ([]db.Database, error) {
+	dataSources := repository.NewDataSources(config.GetConfig())
+
+	var databases []db.Database
+	for _, database := range []db.Database{dataSources.PrimaryDB, dataSources.SQLiteDB} {
+		if database != nil && (target == "all" || target == database.Dialect()) {
+			databases = append(databases, database)
+		}
+	}
+	switch target {
+	case "all", db.DialectMySQL, db.DialectPostgres, db.DialectSQLite:
+	default:
+		return nil, fmt.Errorf("unknown database: %s", target)
+	}
+	if len(databases) == 0 {
+		return nil, fmt.Errorf("no available database for %s", target)
+	}
+	return databases, nil
+}
+
+func printStatus(dialect string, statuses []migrate.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "[%s]\nVERSION\tNAME\tAPPLIED AT\n", dialect)
+	for _, st := range statuses {
+		appliedAt := "pending"
+		if st.AppliedAt != nil {
+			appliedAt = st.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", st.Version, st.Name, appliedAt)
+	}
+	w.Flush()
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"text/tabwriter"
+
+	"github.com/username/shorturl/internal/config"
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+)
+
+const workspaceUsage = `usage: rpc workspace <create|set|add-member|list> [flags]
+
+  create -slug S [-name N] [配置与配额]      创建工作区
+  set -slug S [-name N] [配置与配额]         修改工作区，只修改指定的项
+  add-member -slug S -subject U [-role R]    添加成员或修改成员角色，U 为认证身份（API key 的 subject 或 JWT sub）
+  list                                       查看所有工作区
+
+flags:
+`
+
+// workspaceSlugPattern 工作区 slug 只能包含小写字母、数字和 -，用于 X-Workspace 请求头
+var workspaceSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,63}$`)
+
+// runWorkspace 执行 workspace 子命令
+func runWorkspace(args []string) error {
+	fs := flag.NewFlagSet("workspace", flag.ContinueOnError)
+	slug := fs.String("slug", "", "工作区 slug")
+	name := fs.String("name", "", "工作区名称")
+	maxLinks := fs.Int64("max-links", 0, "短链接数量上限，0 表示不限制")
+	maxMonthlyClicks := fs.Int64("max-monthly-clicks", 0, "每月点击数上限，0 表示不限制")
+	redirectCode := fs.Int("redirect-code", 0, "默认重定向状态码，0 表示使用全局默认值")
+	defaultTTL := fs.Duration("default-ttl", 0, "默认有效期，0 表示使用全局配置")
+	maxTTL := fs.Duration("max-ttl", 0, "最长有效期，0 表示使用全局配置")
+	subject := fs.String("subject", "", "add-member 的成员认证身份")
+	role := fs.String("role", model.WorkspaceRoleMember, "add-member 的成员角色：admin | member")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), workspaceUsage)
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		return errors.New("missing workspace action")
+	}
+	action := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if action != "list" && *slug == "" {
+		return errors.New("-slug is required")
+	}
+	if *redirectCode != 0 && !model.IsValidRedirectCode(*redirectCode) {
+		return fmt.Errorf("unsupported redirect code: %d", *redirectCode)
+	}
+
+	dataSources := repository.NewDataSources(config.GetConfig())
+	workspaces := repository.NewWorkspaceRepository(dataSources)
+	ctx := context.Background()
+
+	// apply 把命令行中指定的项写入工作区
+	apply := func(ws *model.Workspace) {
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "name":
+				ws.Name = *name
+			case "max-links":
+				ws.MaxLinks = *maxLinks
+			case "max-monthly-clicks":
+				ws.MaxMonthlyClicks = *maxMonthlyClicks
+			case "redirect-code":
+				ws.Settings.DefaultRedirectCode = *redirectCode
+			case "default-ttl":
+				ws.Settings.LinkDefaultTTL = *defaultTTL
+			case "max-ttl":
+				ws.Settings.LinkMaxTTL = *maxTTL
+			}
+		})
+	}
+
+	switch action {
+	case "create":
+		if !workspaceSlugPattern.MatchString(*slug) {
+			return fmt.Errorf("invalid slug %q: only lowercase letters, digits and -, 2-64 characters", *slug)
+		}
+		ws := &model.Workspace{Slug: *slug, Name: *slug}
+		apply(ws)
+		if err := workspaces.Create(ctx, ws); err != nil {
+			return err
+		}
+		fmt.Printf("created workspace %s (id %d)\n", ws.Slug, ws.ID)
+	case "set":
+		ws, err := workspaces.GetBySlug(ctx, *slug)
+		if err != nil {
+			return err
+		}
+		apply(ws)
+		if err := workspaces.Update(ctx, ws); err != nil {
+			return err
+		}
+		fmt.Printf("updated workspace %s\n", ws.Slug)
+	case "add-member":
+		if *subject == "" {
+			return errors.New("-subject is required")
+		}
+		if *role != model.WorkspaceRoleAdmin && *role != model.WorkspaceRoleMember {
+			return fmt.Errorf("unknown role: %s", *role)
+		}
+		ws, err := workspaces.GetBySlug(ctx, *slug)
+		if err != nil {
+			return err
+		}
+		user, err := repository.NewUserRepository(dataSources).GetOrCreate(ctx, *subject)
+		if err != nil {
+			return err
+		}
+		if err := workspaces.SetMember(ctx, &model.WorkspaceMember{WorkspaceID: ws.ID, UserID: user.ID, Role: *role}); err != nil {
+			return err
+		}
+		fmt.Printf("%s is now %s of workspace %s\n", *subject, *role, ws.Slug)
+	case "list":
+		list, err := workspaces.List(ctx)
+		if err != nil {
+			return err
+		}
+		printWorkspaces(list)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown workspace action: %s", action)
+	}
+	return nil
+}
+
+func printWorkspaces(list []model.Workspace) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSLUG\tNAME\tMAX LINKS\tMAX MONTHLY CLICKS\tREDIRECT\tDEFAULT TTL\tMAX TTL")
+	for _, ws := range list {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", ws.ID, ws.Slug, ws.Name,
+			limitString(ws.MaxLinks), limitString(ws.MaxMonthlyClicks),
+			settingString(int64(ws.Settings.DefaultRedirectCode), fmt.Sprint(ws.Settings.DefaultRedirectCode)),
+			settingString(int64(ws.Settings.LinkDefaultTTL), ws.Settings.LinkDefaultTTL.String()),
+			settingString(int64(ws.Settings.LinkMaxTTL), ws.Settings.LinkMaxTTL.String()))
+	}
+	w.Flush()
+}
+
+// limitString 配额为 0 时显示为不限制
+func limitString(limit int64) string {
+	if limit <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprint(limit)
+}
+
+// settingString 配置为零值时显示为使用全局配置
+func settingString(value int64, s string) string {
+	if value == 0 {
+		return "default"
+	}
+	return s
+}
@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/username/shorturl/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testSecret = "test-secret"
+
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+func newTestAuthenticator(t *testing.T) *Authenticator {
+	t.Helper()
+	a, err := NewAuthenticator(Options{
+		APIKeys:     []APIKey{{Key: "key-1", Subject: "ci-bot", Roles: []string{"admin"}}},
+		ServiceKey:  "service-key",
+		JWTSecret:   testSecret,
+		JWTIssuer:   "shorturl",
+		JWTAudience: "api",
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	return a
+}
+
+// TestAuthenticate API key 与 JWT 的认证结果
+func TestAuthenticate(t *testing.T) {
+	a := newTestAuthenticator(t)
+	exp := time.Now().Add(time.Hour).Unix()
+	valid := jwt.MapClaims{"sub": "alice", "iss": "shorturl", "aud": "api", "exp": exp, "roles": []string{"user"}}
+
+	tests := []struct {
+		name        string
+		creds       Credentials
+		wantSubject string
+		wantRoles   []string
+	}{
+		{name: "api key", creds: Credentials{APIKey: "key-1"}, wantSubject: "ci-bot", wantRoles: []string{"admin"}},
+		{name: "service key", creds: Credentials{APIKey: "service-key"}, wantSubject: serviceSubject, wantRoles: []string{RoleService}},
+		{name: "unknown api key", creds: Credentials{APIKey: "nope"}},
+		{name: "missing credentials", creds: Credentials{}},
+		{name: "jwt", creds: Credentials{Bearer: signToken(t, jwt.SigningMethodHS256, []byte(testSecret), valid)}, wantSubject: "alice", wantRoles: []string{"user"}},
+		{name: "jwt wrong secret", creds: Credentials{Bearer: signToken(t, jwt.SigningMethodHS256, []byte("other"), valid)}},
+		{name: "jwt expired", creds: Credentials{Bearer: signToken(t, jwt.SigningMethodHS256, []byte(testSecret),
+			jwt.MapClaims{"sub": "alice", "iss": "shorturl", "aud": "api", "exp": time.Now().Add(-time.Minute).Unix()})}},
+		{name: "jwt without exp", creds: Credentials{Bearer: signToken(t, jwt.SigningMethodHS256, []byte(testSecret),
+			jwt.MapClaims{"sub": "alice", "iss": "shorturl", "aud": "api"})}},
+		{name: "jwt wrong issuer", creds: Credentials{Bearer: signToken(t, jwt.SigningMethodHS256, []byte(testSecret),
+			jwt.MapClaims{"sub": "alice", "iss": "evil", "aud": "api", "exp": exp})}},
+		{name: "jwt alg none", creds: Credentials{Bearer: signToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, valid)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := a.Authenticate(tt.creds)
+			if tt.wantSubject == "" {
+				if !errors.Is(err, ErrUnauthenticated) {
+					t.Fatalf("err = %v, want ErrUnauthenticated", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate: %v", err)
+			}
+			if id.Subject != tt.wantSubject {
+				t.Errorf("Subject = %q, want %q", id.Subject, tt.wantSubject)
+			}
+			for _, role := range tt.wantRoles {
+				if !id.HasRole(role) {
+					t.Errorf("missing role %q in %v", role, id.Roles)
+				}
+			}
+		})
+	}
+}
+
+// TestForwardedIdentity 网关转发的身份只有在携带服务密钥时才被采用
+func TestForwardedIdentity(t *testing.T) {
+	a := newTestAuthenticator(t)
+	user := &Identity{Subject: "alice", Roles: []string{"user"}, Method: MethodJWT}
+
+	// 经过客户端拦截器得到发往后端的 metadata
+	outgoing := func(serviceKey string, id *Identity) metadata.MD {
+		ctx := context.Background()
+		if id != nil {
+			ctx = WithIdentity(ctx, id)
+		}
+		var md metadata.MD
+		invoker := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			md, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		}
+		_ = UnaryClientInterceptor(serviceKey)(ctx, "/test", nil, nil, nil, invoker)
+		return md
+	}
+
+	tests := []struct {
+		name        string
+		md          metadata.MD
+		wantCode    codes.Code
+		wantSubject string
+	}{
+		{name: "gateway forwards user", md: outgoing("service-key", user), wantCode: codes.OK, wantSubject: "alice"},
+		{name: "gateway without user", md: outgoing("service-key", nil), wantCode: codes.OK, wantSubject: serviceSubject},
+		{name: "no service key", md: outgoing("", user), wantCode: codes.Unauthenticated},
+		{name: "non-service caller cannot forward", md: metadata.Pairs(MetadataAPIKey, "key-1", metadataSubject, "alice"), wantCode: codes.OK, wantSubject: "ci-bot"},
+		{name: "bearer token", md: metadata.Pairs(MetadataAuthorization, "Bearer "+signToken(t, jwt.SigningMethodHS256, []byte(testSecret),
+			jwt.MapClaims{"sub": "bob", "iss": "shorturl", "aud": "api", "exp": time.Now().Add(time.Hour).Unix()})), wantCode: codes.OK, wantSubject: "bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *Identity
+			handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+				got, _ = FromContext(ctx)
+				return nil, nil
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			_, err := a.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v", code, tt.wantCode)
+			}
+			if tt.wantCode == codes.OK && (got == nil || got.Subject != tt.wantSubject) {
+				t.Errorf("identity = %+v, want subject %q", got, tt.wantSubject)
+			}
+		})
+	}
+}
+
+// TestFromConfig 启用认证时必须配置 ServiceKey
+func TestFromConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		serviceKey string
+		wantNil    bool
+		wantErr    bool
+	}{
+		{name: "disabled", wantNil: true},
+		{name: "enabled with service key", enabled: true, serviceKey: "service-key"},
+		{name: "enabled without service key", enabled: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Auth.Enabled = tt.enabled
+			cfg.Auth.ServiceKey = tt.serviceKey
+			cfg.Auth.JWT.Secret = testSecret
+			a, err := FromConfig(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (a == nil) != tt.wantNil {
+				t.Errorf("FromConfig() = %v, want nil %v", a, tt.wantNil)
+			}
+		})
+	}
+}
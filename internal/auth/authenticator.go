@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/username/shorturl/internal/config"
+)
+
+// ErrUnauthenticated 缺少凭证或凭证无效
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// serviceSubject 使用 ServiceKey 认证的调用方
+const serviceSubject = "gateway"
+
+// APIKey 一个静态 API key 及其身份
+type APIKey struct {
+	Key     string
+	Subject string
+	Roles   []string
+}
+
+// Options 认证参数，API key 和 JWT 至少配置一种
+type Options struct {
+	APIKeys []APIKey
+	// ServiceKey 网关调用后端时使用的密钥，认证为拥有 RoleService 的 gateway
+	ServiceKey string
+	// JWTSecret HS256 密钥；JWTPublicKeyFile RS256 / ES256 公钥（PEM），两者都配置时都接受
+	JWTSecret        string
+	JWTPublicKeyFile string
+	// JWTIssuer、JWTAudience 非空时校验 iss / aud
+	JWTIssuer   string
+	JWTAudience string
+}
+
+// Credentials 请求中携带的凭证
+type Credentials struct {
+	APIKey string
+	// Bearer Authorization: Bearer 之后的 token
+	Bearer string
+}
+
+// claims JWT 中读取的字段
+type claims struct {
+	Roles []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+// Authenticator 校验 API key 和 JWT
+// nil 表示未启用认证，HTTP 中间件和 gRPC 拦截器直接放行
+type Authenticator struct {
+	// apiKeys 以 key 的 SHA-256 为索引，避免按原文比较
+	apiKeys map[[sha256.Size]byte]*Identity
+	hmacKey []byte
+	pubKey  interface{}
+	parser  *jwt.Parser
+}
+
+// NewAuthenticator 创建 Authenticator
+func NewAuthenticator(opts Options) (*Authenticator, error) {
+	a := &Authenticator{apiKeys: make(map[[sha256.Size]byte]*Identity)}
+	for _, k := range opts.APIKeys {
+		if k.Key == "" || k.Subject == "" {
+			return nil, errors.New("api key and subject must not be empty")
+		}
+		a.apiKeys[sha256.Sum256([]byte(k.Key))] = &Identity{Subject: k.Subject, Roles: k.Roles, Method: MethodAPIKey}
+	}
+	if opts.ServiceKey != "" {
+		a.apiKeys[sha256.Sum256([]byte(opts.ServiceKey))] = &Identity{Subject: serviceSubject, Roles: []string{RoleService}, Method: MethodAPIKey}
+	}
+
+	var methods []string
+	if opts.JWTSecret != "" {
+		a.hmacKey = []byte(opts.JWTSecret)
+		methods = append(methods, "HS256")
+	}
+	if opts.JWTPublicKeyFile != "" {
+		pem, err := os.ReadFile(opts.JWTPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT public key: %w", err)
+		}
+		if a.pubKey, err = jwt.ParseRSAPublicKeyFromPEM(pem); err == nil {
+			methods = append(methods, "RS256")
+		} else if a.pubKey, err = jwt.ParseECPublicKeyFromPEM(pem); err == nil {
+			methods = append(methods, "ES256")
+		} else {
+			return nil, fmt.Errorf("JWT public key must be RSA or ECDSA: %w", err)
+		}
+	}
+	if len(a.apiKeys) == 0 && len(methods) == 0 {
+		return nil, errors.New("no API keys or JWT keys configured")
+	}
+
+	parserOpts := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if opts.JWTIssuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opts.JWTIssuer))
+	}
+	if opts.JWTAudience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.JWTAudience))
+	}
+	a.parser = jwt.NewParser(parserOpts...)
+	return a, nil
+}
+
+// FromConfig 按配置创建 Authenticator，未启用认证时返回 nil
+// 启用认证时必须配置 ServiceKey：网关用它调用后端，缺少时后端拒绝网关转发的所有请求（包括短链跳转）
+func FromConfig(cfg *config.Config) (*Authenticator, error) {
+	if !cfg.Auth.Enabled {
+		return nil, nil
+	}
+	if cfg.Auth.ServiceKey == "" {
+		return nil, errors.New("service key (Auth.ServiceKey) is required when auth is enabled")
+	}
+	opts := Options{
+		ServiceKey:       cfg.Auth.ServiceKey,
+		JWTSecret:        cfg.Auth.JWT.Secret,
+		JWTPublicKeyFile: cfg.Auth.JWT.PublicKeyFile,
+		JWTIssuer:        cfg.Auth.JWT.Issuer,
+		JWTAudience:      cfg.Auth.JWT.Audience,
+	}
+	for _, k := range cfg.Auth.APIKeys {
+		opts.APIKeys = append(opts.APIKeys, APIKey{Key: k.Key, Subject: k.Subject, Roles: k.Roles})
+	}
+	return NewAuthenticator(opts)
+}
+
+// Authenticate 校验凭证，同时携带两种凭证时优先使用 API key
+func (a *Authenticator) Authenticate(creds Credentials) (*Identity, error) {
+	if creds.APIKey != "" {
+		if id, ok := a.apiKeys[sha256.Sum256([]byte(creds.APIKey))]; ok {
+			return id, nil
+		}
+		return nil, fmt.Errorf("%w: invalid api key", ErrUnauthenticated)
+	}
+	if creds.Bearer != "" {
+		return a.parseJWT(creds.Bearer)
+	}
+	return nil, fmt.Errorf("%w: missing credentials", ErrUnauthenticated)
+}
+
+func (a *Authenticator) parseJWT(token string) (*Identity, error) {
+	var c claims
+	_, err := a.parser.ParseWithClaims(token, &c, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); ok {
+			return a.hmacKey, nil
+		}
+		return a.pubKey, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+	if c.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrUnauthenticated)
+	}
+	return &Identity{Subject: c.Subject, Roles: c.Roles, Method: MethodJWT}, nil
+}
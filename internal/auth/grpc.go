@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// gRPC metadata 中的凭证与转发的身份
+const (
+	MetadataAPIKey        = "x-api-key"
+	MetadataAuthorization = "authorization"
+
+	metadataSubject = "x-auth-subject"
+	metadataRoles   = "x-auth-roles"
+	metadataMethod  = "x-auth-method"
+)
+
+// UnaryClientInterceptor 网关调用后端时使用：附加 serviceKey，并把 ctx 中终端用户的身份写入 metadata
+func UnaryClientInterceptor(serviceKey string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingContext(ctx, serviceKey), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor 同 UnaryClientInterceptor，用于流式调用
+func StreamClientInterceptor(serviceKey string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingContext(ctx, serviceKey), desc, cc, method, opts...)
+	}
+}
+
+func outgoingContext(ctx context.Context, serviceKey string) context.Context {
+	var kv []string
+	if serviceKey != "" {
+		kv = append(kv, MetadataAPIKey, serviceKey)
+	}
+	if id, ok := FromContext(ctx); ok {
+		kv = append(kv,
+			metadataSubject, id.Subject,
+			metadataRoles, strings.Join(id.Roles, ","),
+			metadataMethod, id.Method,
+		)
+	}
+	if len(kv) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// UnaryServerInterceptor 校验调用方凭证，认证身份写入 ctx；未启用认证（nil）时直接放行
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticateIncoming(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 同 UnaryServerInterceptor，用于流式调用
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticateIncoming(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &identityStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// identityStream 替换 ServerStream 的 context
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticateIncoming 从 metadata 中读取凭证并认证
+// 只有拥有 RoleService 的调用方（网关）转发的终端用户身份才会被采用，其他调用方的转发字段被忽略
+func (a *Authenticator) authenticateIncoming(ctx context.Context) (context.Context, error) {
+	if a == nil {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	creds := Credentials{APIKey: first(MetadataAPIKey)}
+	if bearer, ok := strings.CutPrefix(first(MetadataAuthorization), "Bearer "); ok {
+		creds.Bearer = strings.TrimSpace(bearer)
+	}
+	id, err := a.Authenticate(creds)
+	if err != nil {
+		if errors.Is(err, ErrUnauthenticated) {
+			return nil, status.Error(codes.Unauthenticated, "未认证：缺少或无效的 API key / token")
+		}
+		return nil, status.Error(codes.Internal, "认证失败")
+	}
+
+	if subject := first(metadataSubject); subject != "" && id.HasRole(RoleService) {
+		forwarded := &Identity{Subject: subject, Method: first(metadataMethod)}
+		if roles := first(metadataRoles); roles != "" {
+			forwarded.Roles = strings.Split(roles, ",")
+		}
+		id = forwarded
+	}
+	return WithIdentity(ctx, id), nil
+}
@@ -0,0 +1,46 @@
+// Package auth 认证：API key 与 JWT bearer token，以及认证身份在 HTTP 网关与 gRPC 后端之间的传递
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// 认证方式
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+)
+
+// RoleAdmin 管理员角色，可以查看和修改所有用户的短链接
+const RoleAdmin = "admin"
+
+// RoleService 网关等内部服务的角色，持有该角色的调用方可以在 gRPC metadata 中转发终端用户的身份
+const RoleService = "service"
+
+// Identity 认证通过的调用方
+type Identity struct {
+	// Subject API key 配置的名称或 JWT 的 sub
+	Subject string
+	Roles   []string
+	// Method 认证方式：api_key | jwt
+	Method string
+}
+
+// HasRole 是否拥有指定角色
+func (id *Identity) HasRole(role string) bool {
+	return id != nil && slices.Contains(id.Roles, role)
+}
+
+type identityKey struct{}
+
+// WithIdentity 返回携带认证身份的 context
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext 取出认证身份，未认证（或未启用认证）时返回 false
+func FromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(*Identity)
+	return id, ok && id != nil
+}
@@ -0,0 +1,68 @@
+// Package bloom 提供只支持添加的并发安全 Bloom filter
+package bloom
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// Filter Bloom filter，MayContain 返回 false 时元素一定不存在
+// 位图使用原子操作读写，Add 和 MayContain 可以并发调用
+type Filter struct {
+	bits []atomic.Uint64
+	m    uint64 // 位数
+	k    uint64 // 哈希函数个数
+}
+
+// New 按预计元素个数 n 和期望误判率 p 创建 Filter
+func New(n uint64, p float64) *Filter {
+	if n == 0 {
+		n = 1
+	}
+	if p <= 0 || p >= 1 {
+		p = 0.01
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	m = (m + 63) / 64 * 64
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k == 0 {
+		k = 1
+	}
+	return &Filter{bits: make([]atomic.Uint64, m/64), m: m, k: k}
+}
+
+// Add 添加元素
+func (f *Filter) Add(s string) {
+	h1, h2 := hashes(s)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/64].Or(1 << (bit % 64))
+	}
+}
+
+// MayContain 元素可能存在时返回 true，返回 false 时一定不存在
+func (f *Filter) MayContain(s string) bool {
+	h1, h2 := hashes(s)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64].Load()&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hashes 用 FNV-1a 和其混淆结果作为两个基础哈希，按 h1 + i*h2 派生 k 个位置
+func hashes(s string) (uint64, uint64) {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	// splitmix64 终结函数
+	h2 := h + 0x9e3779b97f4a7c15
+	h2 = (h2 ^ (h2 >> 30)) * 0xbf58476d1ce4e5b9
+	h2 = (h2 ^ (h2 >> 27)) * 0x94d049bb133111eb
+	h2 ^= h2 >> 31
+	return h, h2 | 1
+}
@@ -0,0 +1,49 @@
+package bloom
+
+import (
+	"strconv"
+	"testing"
+)
+
+// TestFilterNoFalseNegatives 测试添加过的元素一定返回可能存在
+func TestFilterNoFalseNegatives(t *testing.T) {
+	f := New(10000, 0.01)
+	for i := 0; i < 10000; i++ {
+		f.Add("code" + strconv.Itoa(i))
+	}
+	for i := 0; i < 10000; i++ {
+		if !f.MayContain("code" + strconv.Itoa(i)) {
+			t.Fatalf("MayContain(code%d) = false, want true", i)
+		}
+	}
+}
+
+// TestFilterFalsePositiveRate 测试误判率接近配置值
+func TestFilterFalsePositiveRate(t *testing.T) {
+	tests := []struct {
+		name string
+		p    float64
+	}{
+		{"1%", 0.01},
+		{"0.1%", 0.001},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const n = 10000
+			f := New(n, tt.p)
+			for i := 0; i < n; i++ {
+				f.Add("code" + strconv.Itoa(i))
+			}
+			falsePositives := 0
+			const probes = 100000
+			for i := 0; i < probes; i++ {
+				if f.MayContain("probe" + strconv.Itoa(i)) {
+					falsePositives++
+				}
+			}
+			if rate := float64(falsePositives) / probes; rate > tt.p*2 {
+				t.Errorf("false positive rate = %.4f, want <= %.4f", rate, tt.p*2)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Transport 在实例之间广播缓存失效消息
+type Transport interface {
+	// Publish 广播一条消息，所有订阅者（包括发送方自己）都会收到
+	Publish(ctx context.Context, payload []byte) error
+	// Subscribe 订阅消息，直到 ctx 取消（返回 nil）或连接断开（返回错误）
+	// 每次订阅建立（包括底层连接断开后的重新订阅）时调用 ready，之后每条消息调用 handle
+	Subscribe(ctx context.Context, ready func(), handle func(payload []byte)) error
+}
+
+// RedisTransport 基于 Redis pub/sub 的 Transport
+// pub/sub 不保证送达，连接断开期间的消息会丢失，订阅方应在 ready 时清空本地缓存
+type RedisTransport struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+// NewRedisTransport 复用 RedisCache 的连接创建 Transport
+func NewRedisTransport(c Cache, channel string) (*RedisTransport, error) {
+	rc, ok := c.(*RedisCache)
+	if !ok {
+		return nil, fmt.Errorf("redis transport requires *RedisCache, got %T", c)
+	}
+	return &RedisTransport{client: rc.client, channel: channel}, nil
+}
+
+// Publish 发布到频道
+func (t *RedisTransport) Publish(ctx context.Context, payload []byte) error {
+	return t.client.Publish(ctx, t.channel, payload).Err()
+}
+
+// Subscribe 订阅频道
+func (t *RedisTransport) Subscribe(ctx context.Context, ready func(), handle func(payload []byte)) error {
+	pubsub := t.client.Subscribe(ctx, t.channel)
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind == "subscribe" {
+				ready()
+			}
+		case *redis.Message:
+			handle([]byte(m.Payload))
+		}
+	}
+}
+
+// LocalBus 进程内的 Transport，多个订阅者共享同一个 LocalBus 即可模拟多个实例
+type LocalBus struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   map[int]func(payload []byte)
+}
+
+// NewLocalBus 创建进程内的 Transport
+func NewLocalBus() *LocalBus {
+	return &LocalBus{subs: make(map[int]func(payload []byte))}
+}
+
+// Publish 同步投递给所有订阅者
+func (b *LocalBus) Publish(ctx context.Context, payload []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handle := range b.subs {
+		handle(payload)
+	}
+	return nil
+}
+
+// Subscribe 注册订阅者，阻塞直到 ctx 取消
+func (b *LocalBus) Subscribe(ctx context.Context, ready func(), handle func(payload []byte)) error {
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = handle
+	b.mu.Unlock()
+	ready()
+
+	<-ctx.Done()
+	b.mu.Lock()
+	delete(b.subs, id)
+	b.mu.Unlock()
+	return nil
+}
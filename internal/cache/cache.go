@@ -12,3 +12,80 @@ type Cache interface {
 	Delete(ctx context.Context, key string) error
 	Exists(ctx context.Context, key string) (bool, error)
 }
+
+// Stats 缓存的命中与容量统计
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	// Expired 因过期被删除的条数
+	Expired int64
+	Entries int
+	Bytes   int64
+}
+
+// StatsReporter 可以报告统计信息的缓存
+type StatsReporter interface {
+	Stats() Stats
+}
+
+// Clearer 可以清空全部数据的缓存
+type Clearer interface {
+	Clear()
+}
+
+// Entry 批量写入的一条缓存数据
+type Entry struct {
+	Key        string
+	Value      interface{}
+	Expiration time.Duration
+}
+
+// BatchCache 支持批量操作的缓存，Redis 通过 pipeline 实现，一次往返完成整批读写
+type BatchCache interface {
+	SetMulti(ctx context.Context, entries []Entry) error
+	ExistsMulti(ctx context.Context, keys []string) ([]bool, error)
+	DeleteMulti(ctx context.Context, keys []string) error
+}
+
+// SetMulti 批量写入，缓存不支持批量操作时逐条写入
+func SetMulti(ctx context.Context, c Cache, entries []Entry) error {
+	if bc, ok := c.(BatchCache); ok {
+		return bc.SetMulti(ctx, entries)
+	}
+	for _, e := range entries {
+		if err := c.Set(ctx, e.Key, e.Value, e.Expiration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExistsMulti 批量检查键是否存在，返回值与 keys 一一对应
+func ExistsMulti(ctx context.Context, c Cache, keys []string) ([]bool, error) {
+	if bc, ok := c.(BatchCache); ok {
+		return bc.ExistsMulti(ctx, keys)
+	}
+	result := make([]bool, len(keys))
+	for i, key := range keys {
+		exists, err := c.Exists(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = exists
+	}
+	return result, nil
+}
+
+// DeleteMulti 批量删除，缓存不支持批量操作时逐条删除
+func DeleteMulti(ctx context.Context, c Cache, keys []string) error {
+	if bc, ok := c.(BatchCache); ok {
+		return bc.DeleteMulti(ctx, keys)
+	}
+	for _, key := range keys {
+		if err := c.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
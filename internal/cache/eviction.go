@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"container/heap"
+	"container/list"
+	"fmt"
+)
+
+// 淘汰策略
+const (
+	PolicyLRU = "lru"
+	PolicyLFU = "lfu"
+)
+
+// evictionPolicy 记录键的访问情况，容量不足时选出被淘汰的键
+// 由 MemoryCache 在持有锁时调用，实现不需要自行加锁
+type evictionPolicy interface {
+	// add 新增键
+	add(key string)
+	// access 键被命中或被覆盖写入
+	access(key string)
+	// remove 键被删除、过期或淘汰
+	remove(key string)
+	// victim 返回下一个应被淘汰的键，exclude 为刚写入的键，不参与淘汰
+	// （否则 LFU 中新写入的键访问次数最少，会被立即淘汰）
+	victim(exclude string) (string, bool)
+}
+
+func newEvictionPolicy(name string) (evictionPolicy, error) {
+	switch name {
+	case "", PolicyLRU:
+		return newLRUPolicy(), nil
+	case PolicyLFU:
+		return newLFUPolicy(), nil
+	default:
+		return nil, fmt.Errorf("unknown eviction policy: %s", name)
+	}
+}
+
+// lruPolicy 最近最少使用：链表头部为最近访问，淘汰尾部
+type lruPolicy struct {
+	order *list.List
+	elems map[string]*list.Element
+}
+
+func newLRUPolicy() *lruPolicy {
+	return &lruPolicy{order: list.New(), elems: make(map[string]*list.Element)}
+}
+
+func (p *lruPolicy) add(key string) {
+	if e, ok := p.elems[key]; ok {
+		p.order.MoveToFront(e)
+		return
+	}
+	p.elems[key] = p.order.PushFront(key)
+}
+
+func (p *lruPolicy) access(key string) {
+	if e, ok := p.elems[key]; ok {
+		p.order.MoveToFront(e)
+	}
+}
+
+func (p *lruPolicy) remove(key string) {
+	if e, ok := p.elems[key]; ok {
+		p.order.Remove(e)
+		delete(p.elems, key)
+	}
+}
+
+func (p *lruPolicy) victim(exclude string) (string, bool) {
+	e := p.order.Back()
+	if e != nil && e.Value.(string) == exclude {
+		e = e.Prev()
+	}
+	if e == nil {
+		return "", false
+	}
+	return e.Value.(string), true
+}
+
+// lfuPolicy 最不经常使用：按访问次数淘汰，次数相同时淘汰最久未访问的
+type lfuPolicy struct {
+	entries lfuHeap
+	index   map[string]*lfuEntry
+	// tick 单调递增的访问序号，用于次数相同时比较先后
+	tick uint64
+}
+
+type lfuEntry struct {
+	key      string
+	freq     uint64
+	lastUsed uint64
+	pos      int
+}
+
+func newLFUPolicy() *lfuPolicy {
+	return &lfuPolicy{index: make(map[string]*lfuEntry)}
+}
+
+func (p *lfuPolicy) add(key string) {
+	if _, ok := p.index[key]; ok {
+		p.access(key)
+		return
+	}
+	p.tick++
+	e := &lfuEntry{key: key, freq: 1, lastUsed: p.tick}
+	p.index[key] = e
+	heap.Push(&p.entries, e)
+}
+
+func (p *lfuPolicy) access(key string) {
+	e, ok := p.index[key]
+	if !ok {
+		return
+	}
+	p.tick++
+	e.freq++
+	e.lastUsed = p.tick
+	heap.Fix(&p.entries, e.pos)
+}
+
+func (p *lfuPolicy) remove(key string) {
+	e, ok := p.index[key]
+	if !ok {
+		return
+	}
+	heap.Remove(&p.entries, e.pos)
+	delete(p.index, key)
+}
+
+func (p *lfuPolicy) victim(exclude string) (string, bool) {
+	h := p.entries
+	if len(h) == 0 {
+		return "", false
+	}
+	if h[0].key != exclude {
+		return h[0].key, true
+	}
+	// 堆顶被排除时，次小值一定是堆顶的某个子节点
+	switch {
+	case len(h) == 1:
+		return "", false
+	case len(h) == 2 || h.Less(1, 2):
+		return h[1].key, true
+	default:
+		return h[2].key, true
+	}
+}
+
+// lfuHeap 按 (freq, lastUsed) 排序的最小堆
+type lfuHeap []*lfuEntry
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].freq != h[j].freq {
+		return h[i].freq < h[j].freq
+	}
+	return h[i].lastUsed < h[j].lastUsed
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].pos = i
+	h[j].pos = j
+}
+
+func (h *lfuHeap) Push(x any) {
+	e := x.(*lfuEntry)
+	e.pos = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *lfuHeap) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
@@ -5,8 +5,42 @@ import (
 )
 
 func NewCache(cfg *config.Config) (Cache, error) {
-	if cfg.RedisAddr != "" {
-		return NewRedisCache(cfg.RedisAddr)
+	opts, err := RedisOptionsFromConfig(cfg)
+	if err != nil {
+		return nil, err
 	}
-	return NewMemoryCache()
+	if len(opts.Addrs) > 0 {
+		return NewRedisCache(opts)
+	}
+	return NewMemoryCacheWithOptions(MemoryOptions{
+		MaxEntries: cfg.MemoryCache.MaxEntries,
+		MaxBytes:   cfg.MemoryCache.MaxBytes,
+		Policy:     cfg.MemoryCache.Policy,
+	})
+}
+
+// RedisOptionsFromConfig 从配置读取 Redis 连接参数，未配置 Redis 时 Addrs 为空
+func RedisOptionsFromConfig(cfg *config.Config) (RedisOptions, error) {
+	rc := cfg.Redis
+	opts := RedisOptions{
+		Mode:             rc.Mode,
+		Addrs:            rc.Addrs,
+		Username:         rc.Username,
+		Password:         rc.Password,
+		DB:               rc.DB,
+		MasterName:       rc.MasterName,
+		SentinelUsername: rc.SentinelUsername,
+		SentinelPassword: rc.SentinelPassword,
+	}
+	if len(opts.Addrs) == 0 && cfg.RedisAddr != "" {
+		opts.Addrs = []string{cfg.RedisAddr}
+	}
+	if rc.TLS.Enabled {
+		tlsConfig, err := LoadTLSConfig(rc.TLS.CAFile, rc.TLS.CertFile, rc.TLS.KeyFile, rc.TLS.ServerName, rc.TLS.InsecureSkipVerify)
+		if err != nil {
+			return RedisOptions{}, err
+		}
+		opts.TLS = tlsConfig
+	}
+	return opts, nil
 }
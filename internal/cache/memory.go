@@ -4,13 +4,18 @@ import (
 	"context"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// entryOverhead 每个缓存项除键和值以外的估算内存开销（map 桶、策略节点等）
+const entryOverhead = 64
+
 // cacheItem 缓存项，包含值和过期时间
 type cacheItem struct {
 	value      interface{}
 	expiration time.Time
+	size       int64
 }
 
 // isExpired 检查缓存项是否已过期
@@ -21,19 +26,48 @@ func (item *cacheItem) isExpired() bool {
 	return time.Now().After(item.expiration)
 }
 
+// MemoryOptions 内存缓存的容量与淘汰策略
+type MemoryOptions struct {
+	// MaxEntries 最大条数，0 表示不限制
+	MaxEntries int
+	// MaxBytes 键和值占用的估算字节数上限，0 表示不限制
+	MaxBytes int64
+	// Policy 淘汰策略：lru | lfu，默认 lru
+	Policy string
+}
+
 // MemoryCache 内存缓存实现
+// 超出 MaxEntries 或 MaxBytes 时按淘汰策略移除缓存项；读取会更新访问记录，因此读写都持有互斥锁
 type MemoryCache struct {
-	data map[string]*cacheItem
-	mu   sync.RWMutex
-	stop chan struct{}
+	data   map[string]*cacheItem
+	mu     sync.Mutex
+	stop   chan struct{}
+	opts   MemoryOptions
+	policy evictionPolicy
+	bytes  int64
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+	expired   atomic.Int64
 }
 
-// NewMemoryCache 创建新的内存缓存实例
+// NewMemoryCache 创建新的内存缓存实例（不限制容量）
 func NewMemoryCache() (Cache, error) {
+	return NewMemoryCacheWithOptions(MemoryOptions{})
+}
+
+// NewMemoryCacheWithOptions 创建有容量上限的内存缓存实例
+func NewMemoryCacheWithOptions(opts MemoryOptions) (Cache, error) {
+	policy, err := newEvictionPolicy(opts.Policy)
+	if err != nil {
+		return nil, err
+	}
 	mc := &MemoryCache{
-		data: make(map[string]*cacheItem),
-		mu:   sync.RWMutex{},
-		stop: make(chan struct{}),
+		data:   make(map[string]*cacheItem),
+		stop:   make(chan struct{}),
+		opts:   opts,
+		policy: policy,
 	}
 
 	// 启动后台清理 goroutine，定期清理过期项
@@ -53,7 +87,8 @@ func (mc *MemoryCache) cleanup() {
 			mc.mu.Lock()
 			for key, item := range mc.data {
 				if item.isExpired() {
-					delete(mc.data, key)
+					mc.removeLocked(key, item)
+					mc.expired.Add(1)
 				}
 			}
 			mc.mu.Unlock()
@@ -64,12 +99,11 @@ func (mc *MemoryCache) cleanup() {
 }
 
 // Set 设置缓存值，支持过期时间
+// 单个缓存项超过 MaxBytes 时不写入缓存
 func (mc *MemoryCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
-	mc.mu.Lock()
-	defer mc.mu.Unlock()
-
 	item := &cacheItem{
 		value: value,
+		size:  entrySize(key, value),
 	}
 
 	// 如果设置了过期时间，则计算过期时间点
@@ -77,38 +111,51 @@ func (mc *MemoryCache) Set(ctx context.Context, key string, value interface{}, e
 		item.expiration = time.Now().Add(expiration)
 	}
 
-	mc.data[key] = item
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	tooLarge := mc.opts.MaxBytes > 0 && item.size > mc.opts.MaxBytes
+	if old, exists := mc.data[key]; exists {
+		if tooLarge {
+			mc.removeLocked(key, old)
+			return nil
+		}
+		// 覆盖写入视为一次访问，保留原有的访问记录
+		mc.data[key] = item
+		mc.bytes += item.size - old.size
+		mc.policy.access(key)
+	} else {
+		if tooLarge {
+			return nil
+		}
+		mc.data[key] = item
+		mc.bytes += item.size
+		mc.policy.add(key)
+	}
+	mc.evictLocked(key)
 	return nil
 }
 
 // Get 获取缓存值，如果过期则返回 nil
 func (mc *MemoryCache) Get(ctx context.Context, key string) (interface{}, error) {
-	mc.mu.RLock()
-	defer mc.mu.RUnlock()
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
 
-	item, exists := mc.data[key]
-	if !exists {
-		return nil, nil
-	}
-
-	// 检查是否过期
-	if item.isExpired() {
-		// 如果过期，异步删除（避免在读锁中加写锁）
-		go func() {
-			mc.mu.Lock()
-			delete(mc.data, key)
-			mc.mu.Unlock()
-		}()
+	item, ok := mc.liveItemLocked(key)
+	if !ok {
+		mc.misses.Add(1)
 		return nil, nil
 	}
 
+	mc.hits.Add(1)
+	mc.policy.access(key)
 	return item.value, nil
 }
 
 // Get 获取缓存值，如果过期则返回 nil
 func (mc *MemoryCache) GetAll(ctx context.Context, pattern string) (interface{}, error) {
-	mc.mu.RLock()
-	defer mc.mu.RUnlock()
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
 	result := make(map[string]*cacheItem)
 	for key, value := range mc.data {
 		if strings.HasPrefix(key, pattern) {
@@ -124,30 +171,96 @@ func (mc *MemoryCache) Delete(ctx context.Context, key string) error {
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
 
-	delete(mc.data, key)
+	if item, exists := mc.data[key]; exists {
+		mc.removeLocked(key, item)
+	}
 	return nil
 }
 
-// Exists 检查键是否存在且未过期
+// Clear 删除所有缓存项，不计入淘汰统计
+func (mc *MemoryCache) Clear() {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	for key, item := range mc.data {
+		mc.removeLocked(key, item)
+	}
+}
+
+// Exists 检查键是否存在且未过期（不计入命中统计，也不更新访问记录）
 func (mc *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
-	mc.mu.RLock()
-	defer mc.mu.RUnlock()
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
 
+	_, ok := mc.liveItemLocked(key)
+	return ok, nil
+}
+
+// Stats 返回命中、未命中、淘汰等统计
+func (mc *MemoryCache) Stats() Stats {
+	mc.mu.Lock()
+	entries, bytes := len(mc.data), mc.bytes
+	mc.mu.Unlock()
+
+	return Stats{
+		Hits:      mc.hits.Load(),
+		Misses:    mc.misses.Load(),
+		Evictions: mc.evictions.Load(),
+		Expired:   mc.expired.Load(),
+		Entries:   entries,
+		Bytes:     bytes,
+	}
+}
+
+// liveItemLocked 返回未过期的缓存项，过期的缓存项会被顺带删除
+func (mc *MemoryCache) liveItemLocked(key string) (*cacheItem, bool) {
 	item, exists := mc.data[key]
 	if !exists {
-		return false, nil
+		return nil, false
 	}
-
-	// 如果过期，返回 false
 	if item.isExpired() {
-		// 异步删除过期项
-		go func() {
-			mc.mu.Lock()
-			delete(mc.data, key)
-			mc.mu.Unlock()
-		}()
-		return false, nil
+		mc.removeLocked(key, item)
+		mc.expired.Add(1)
+		return nil, false
+	}
+	return item, true
+}
+
+// evictLocked 按淘汰策略移除缓存项，直到满足容量限制，刚写入的 exclude 不会被淘汰
+func (mc *MemoryCache) evictLocked(exclude string) {
+	for mc.overCapacityLocked() {
+		key, ok := mc.policy.victim(exclude)
+		if !ok {
+			return
+		}
+		mc.removeLocked(key, mc.data[key])
+		mc.evictions.Add(1)
 	}
+}
 
-	return true, nil
+func (mc *MemoryCache) overCapacityLocked() bool {
+	return (mc.opts.MaxEntries > 0 && len(mc.data) > mc.opts.MaxEntries) ||
+		(mc.opts.MaxBytes > 0 && mc.bytes > mc.opts.MaxBytes)
+}
+
+func (mc *MemoryCache) removeLocked(key string, item *cacheItem) {
+	delete(mc.data, key)
+	mc.policy.remove(key)
+	if item != nil {
+		mc.bytes -= item.size
+	}
+}
+
+// entrySize 估算缓存项占用的字节数，字符串和字节切片按实际长度计算
+func entrySize(key string, value interface{}) int64 {
+	size := int64(len(key)) + entryOverhead
+	switch v := value.(type) {
+	case string:
+		size += int64(len(v))
+	case []byte:
+		size += int64(len(v))
+	default:
+		size += entryOverhead
+	}
+	return size
 }
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestMemoryCacheEviction 测试不同淘汰策略在超出容量时移除的键
+func TestMemoryCacheEviction(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    MemoryOptions
+		access  []string // 写入 a、b、c 之后依次读取的键
+		insert  string   // 最后写入、触发淘汰的键
+		evicted []string
+		kept    []string
+	}{
+		{
+			name:    "LRU 淘汰最久未访问的键",
+			opts:    MemoryOptions{MaxEntries: 3, Policy: PolicyLRU},
+			access:  []string{"a"},
+			insert:  "d",
+			evicted: []string{"b"},
+			kept:    []string{"a", "c", "d"},
+		},
+		{
+			name:    "LFU 淘汰访问次数最少的键",
+			opts:    MemoryOptions{MaxEntries: 3, Policy: PolicyLFU},
+			access:  []string{"a", "a", "b", "c", "c"},
+			insert:  "d",
+			evicted: []string{"b"},
+			kept:    []string{"a", "c", "d"},
+		},
+		{
+			name: "超出字节上限时淘汰多个键",
+			// 每项约 1+64+100 字节，上限只容纳两项
+			opts:    MemoryOptions{MaxBytes: 2 * (1 + entryOverhead + 100), Policy: PolicyLRU},
+			insert:  "d",
+			evicted: []string{"a", "b"},
+			kept:    []string{"c", "d"},
+		},
+	}
+
+	value := string(make([]byte, 100))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c, err := NewMemoryCacheWithOptions(tt.opts)
+			if err != nil {
+				t.Fatalf("NewMemoryCacheWithOptions() error = %v", err)
+			}
+			for _, key := range []string{"a", "b", "c"} {
+				c.Set(ctx, key, value, 0)
+			}
+			for _, key := range tt.access {
+				c.Get(ctx, key)
+			}
+			c.Set(ctx, tt.insert, value, 0)
+
+			for _, key := range tt.evicted {
+				if ok, _ := c.Exists(ctx, key); ok {
+					t.Errorf("%s should have been evicted", key)
+				}
+			}
+			for _, key := range tt.kept {
+				if ok, _ := c.Exists(ctx, key); !ok {
+					t.Errorf("%s should have been kept", key)
+				}
+			}
+			if got := c.(StatsReporter).Stats().Evictions; got != int64(len(tt.evicted)) {
+				t.Errorf("Evictions = %d, want %d", got, len(tt.evicted))
+			}
+		})
+	}
+}
+
+// TestMemoryCacheStats 测试命中、未命中、过期计数以及覆盖写入时的字节统计
+func TestMemoryCacheStats(t *testing.T) {
+	ctx := context.Background()
+	c, _ := NewMemoryCacheWithOptions(MemoryOptions{MaxEntries: 10})
+	mc := c.(*MemoryCache)
+
+	c.Set(ctx, "k", "v1", 0)
+	c.Set(ctx, "k", "value2", 0)
+	c.Set(ctx, "short", "x", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	c.Get(ctx, "k")
+	c.Get(ctx, "missing")
+	c.Get(ctx, "short")
+
+	stats := mc.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Expired != 1 {
+		t.Errorf("Stats() = %+v, want 1 hit, 2 misses, 1 expired", stats)
+	}
+	if want := entrySize("k", "value2"); stats.Entries != 1 || stats.Bytes != want {
+		t.Errorf("Entries = %d, Bytes = %d, want 1, %d", stats.Entries, stats.Bytes, want)
+	}
+}
+
+// BenchmarkMemoryCacheGet 测试有容量上限时的读取性能
+func BenchmarkMemoryCacheGet(b *testing.B) {
+	for _, policy := range []string{PolicyLRU, PolicyLFU} {
+		b.Run(policy, func(b *testing.B) {
+			ctx := context.Background()
+			c, _ := NewMemoryCacheWithOptions(MemoryOptions{MaxEntries: 1000, Policy: policy})
+			keys := make([]string, 2000)
+			for i := range keys {
+				keys[i] = fmt.Sprintf("shorturl:%d", i)
+				c.Set(ctx, keys[i], "https://example.com", 0)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c.Get(ctx, keys[i%len(keys)])
+			}
+		})
+	}
+}
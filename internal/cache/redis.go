@@ -2,34 +2,63 @@ package cache
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-// RedisCache Redis 缓存实现
+// Redis 部署模式
+const (
+	RedisStandalone = "standalone"
+	RedisSentinel   = "sentinel"
+	RedisCluster    = "cluster"
+)
+
+// RedisOptions Redis 连接参数
+type RedisOptions struct {
+	// Mode 部署模式：standalone | sentinel | cluster，留空时有 MasterName 为 sentinel，多个地址为 cluster
+	Mode string
+	// Addrs standalone 模式下只使用第一个地址；sentinel 模式下为 sentinel 节点地址；cluster 模式下为任意几个集群节点地址
+	Addrs    []string
+	Username string
+	Password string
+	// DB cluster 模式只支持 0 号数据库
+	DB int
+	// MasterName sentinel 监控的主节点名称
+	MasterName string
+	// SentinelUsername、SentinelPassword sentinel 节点自身的认证信息，可以与数据节点不同
+	SentinelUsername string
+	SentinelPassword string
+	// TLS 不为 nil 时使用 TLS 连接
+	TLS *tls.Config
+}
+
+// RedisCache Redis 缓存实现，支持单机、Sentinel 和 Cluster
 type RedisCache struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
 // NewRedisCache 创建新的 Redis 缓存实例
-func NewRedisCache(addr string) (Cache, error) {
-	// 解析 Redis 地址，支持 "host:port" 格式
-	opts := &redis.Options{
-		Addr:     addr,
-		Password: "", // 如果需要密码，可以从配置中读取
-		DB:       0,  // 默认使用 0 号数据库
+func NewRedisCache(opts RedisOptions) (Cache, error) {
+	client, err := newRedisClient(opts)
+	if err != nil {
+		return nil, err
 	}
 
-	client := redis.NewClient(opts)
-
 	// 测试连接
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
 		return nil, err
 	}
 
@@ -38,6 +67,79 @@ func NewRedisCache(addr string) (Cache, error) {
 	}, nil
 }
 
+// Client 返回底层客户端，供需要执行脚本的组件（如限流）复用连接
+func (rc *RedisCache) Client() redis.UniversalClient {
+	return rc.client
+}
+
+// newRedisClient 按部署模式创建客户端，不建立连接
+func newRedisClient(opts RedisOptions) (redis.UniversalClient, error) {
+	if len(opts.Addrs) == 0 {
+		return nil, errors.New("redis: no address configured")
+	}
+	uopts := &redis.UniversalOptions{
+		Addrs:            opts.Addrs,
+		Username:         opts.Username,
+		Password:         opts.Password,
+		DB:               opts.DB,
+		MasterName:       opts.MasterName,
+		SentinelUsername: opts.SentinelUsername,
+		SentinelPassword: opts.SentinelPassword,
+		TLSConfig:        opts.TLS,
+	}
+
+	switch opts.Mode {
+	case "":
+		if opts.MasterName == "" && len(opts.Addrs) > 1 && opts.DB != 0 {
+			return nil, fmt.Errorf("redis: cluster mode does not support DB %d", opts.DB)
+		}
+		return redis.NewUniversalClient(uopts), nil
+	case RedisStandalone:
+		return redis.NewClient(uopts.Simple()), nil
+	case RedisSentinel:
+		if opts.MasterName == "" {
+			return nil, errors.New("redis: sentinel mode requires MasterName")
+		}
+		return redis.NewFailoverClient(uopts.Failover()), nil
+	case RedisCluster:
+		if opts.DB != 0 {
+			return nil, fmt.Errorf("redis: cluster mode does not support DB %d", opts.DB)
+		}
+		return redis.NewClusterClient(uopts.Cluster()), nil
+	default:
+		return nil, fmt.Errorf("redis: unknown mode %q", opts.Mode)
+	}
+}
+
+// LoadTLSConfig 根据证书文件创建 TLS 配置
+// caFile 为空时使用系统根证书；certFile 和 keyFile 用于双向认证，可以为空
+func LoadTLSConfig(caFile, certFile, keyFile, serverName string, insecureSkipVerify bool) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         serverName,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
 // Set 设置缓存值，支持过期时间
 func (rc *RedisCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	// 将 value 序列化为 JSON
@@ -74,39 +176,47 @@ func (rc *RedisCache) Get(ctx context.Context, key string) (interface{}, error)
 	return result, nil
 }
 
-// Get 获取缓存值
+// GetAll 获取所有以 pattern 开头的键和值
+// cluster 模式下 SCAN 只遍历单个节点，需要在每个主节点上分别执行
 func (rc *RedisCache) GetAll(ctx context.Context, pattern string) (interface{}, error) {
-	var keys []string
-	seen := make(map[string]bool)
-	var cursor uint64 = 0
-	log.Println("redis query")
-	for {
-		keysBatch, nextCursor, err := rc.client.Scan(ctx, cursor, pattern+"*", 100).Result()
-		if err != nil {
-			return nil, err
-		}
-
-		for _, key := range keysBatch {
+	var (
+		mu   sync.Mutex
+		keys []string
+		seen = make(map[string]bool)
+	)
+	add := func(batch []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, key := range batch {
+			// SCAN 可能重复返回同一个键
 			if !seen[key] {
 				seen[key] = true
 				keys = append(keys, key)
 			}
 		}
+	}
 
-		cursor = nextCursor
-		if cursor == 0 {
-			break
-		}
+	log.Println("redis query")
+	var err error
+	if cluster, ok := rc.client.(*redis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return scanKeys(ctx, node, pattern+"*", add)
+		})
+	} else {
+		err = scanKeys(ctx, rc.client, pattern+"*", add)
+	}
+	if err != nil {
+		return nil, err
 	}
 	log.Println(keys)
 	if len(keys) == 0 {
 		return nil, nil
 	}
-	vals, err := rc.client.MGet(ctx, keys...).Result()
+	vals, err := rc.getMulti(ctx, keys)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	
+
 	result := make(map[string]any)
 	for index, res := range vals {
 		result[keys[index]] = res
@@ -114,6 +224,51 @@ func (rc *RedisCache) GetAll(ctx context.Context, pattern string) (interface{},
 	return result, nil
 }
 
+// scanKeys 在单个节点上用 SCAN 遍历匹配 match 的键
+func scanKeys(ctx context.Context, client redis.Cmdable, match string, add func([]string)) error {
+	var cursor uint64 = 0
+	for {
+		keysBatch, nextCursor, err := client.Scan(ctx, cursor, match, 100).Result()
+		if err != nil {
+			return err
+		}
+		add(keysBatch)
+
+		cursor = nextCursor
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// getMulti 批量读取原始值，不存在的键对应 nil
+// cluster 模式下 MGET 的键必须位于同一个 slot，改为 pipeline，由客户端按节点分组发送
+func (rc *RedisCache) getMulti(ctx context.Context, keys []string) ([]interface{}, error) {
+	if _, ok := rc.client.(*redis.ClusterClient); !ok {
+		return rc.client.MGet(ctx, keys...).Result()
+	}
+	cmds := make([]*redis.StringCmd, len(keys))
+	// 不存在的键也会作为错误返回，逐条检查
+	_, _ = rc.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Get(ctx, key)
+		}
+		return nil
+	})
+	vals := make([]interface{}, len(keys))
+	for i, cmd := range cmds {
+		val, err := cmd.Result()
+		switch {
+		case err == redis.Nil:
+		case err != nil:
+			return nil, err
+		default:
+			vals[i] = val
+		}
+	}
+	return vals, nil
+}
+
 // Delete 删除指定的缓存键
 func (rc *RedisCache) Delete(ctx context.Context, key string) error {
 	return rc.client.Del(ctx, key).Err()
@@ -127,3 +282,60 @@ func (rc *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
 	}
 	return count > 0, nil
 }
+
+// SetMulti 通过 pipeline 批量写入，值的序列化方式与 Set 一致
+func (rc *RedisCache) SetMulti(ctx context.Context, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	_, err := rc.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, e := range entries {
+			data, err := json.Marshal(e.Value)
+			if err != nil {
+				return err
+			}
+			pipe.Set(ctx, e.Key, data, e.Expiration)
+		}
+		return nil
+	})
+	return err
+}
+
+// ExistsMulti 通过 pipeline 批量检查键是否存在
+func (rc *RedisCache) ExistsMulti(ctx context.Context, keys []string) ([]bool, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	cmds := make([]*redis.IntCmd, len(keys))
+	_, err := rc.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Exists(ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]bool, len(keys))
+	for i, cmd := range cmds {
+		result[i] = cmd.Val() > 0
+	}
+	return result, nil
+}
+
+// DeleteMulti 一条 DEL 命令删除多个键；cluster 模式下键可能位于不同 slot，改为 pipeline 逐个删除
+func (rc *RedisCache) DeleteMulti(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if _, ok := rc.client.(*redis.ClusterClient); !ok {
+		return rc.client.Del(ctx, keys...).Err()
+	}
+	_, err := rc.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, key)
+		}
+		return nil
+	})
+	return err
+}
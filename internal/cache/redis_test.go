@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TestNewRedisClientMode 测试按部署模式创建的客户端类型
+func TestNewRedisClientMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    RedisOptions
+		cluster bool
+		wantErr bool
+	}{
+		{name: "单个地址为单机", opts: RedisOptions{Addrs: []string{"localhost:6379"}, DB: 2}},
+		{name: "有 MasterName 为 sentinel", opts: RedisOptions{Addrs: []string{"a:26379", "b:26379"}, MasterName: "mymaster"}},
+		{name: "多个地址为 cluster", opts: RedisOptions{Addrs: []string{"a:6379", "b:6379"}}, cluster: true},
+		{name: "显式指定单节点 cluster", opts: RedisOptions{Mode: RedisCluster, Addrs: []string{"a:6379"}}, cluster: true},
+		{name: "显式指定 standalone 只使用第一个地址", opts: RedisOptions{Mode: RedisStandalone, Addrs: []string{"a:6379", "b:6379"}}},
+		{name: "cluster 不支持非 0 数据库", opts: RedisOptions{Mode: RedisCluster, Addrs: []string{"a:6379"}, DB: 1}, wantErr: true},
+		{name: "自动判断为 cluster 时不支持非 0 数据库", opts: RedisOptions{Addrs: []string{"a:6379", "b:6379"}, DB: 1}, wantErr: true},
+		{name: "sentinel 缺少 MasterName", opts: RedisOptions{Mode: RedisSentinel, Addrs: []string{"a:26379"}}, wantErr: true},
+		{name: "未知模式", opts: RedisOptions{Mode: "proxy", Addrs: []string{"a:6379"}}, wantErr: true},
+		{name: "没有地址", opts: RedisOptions{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := newRedisClient(tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					client.Close()
+					t.Fatal("期望返回错误")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("创建客户端失败: %v", err)
+			}
+			defer client.Close()
+
+			_, isCluster := client.(*redis.ClusterClient)
+			if isCluster != tt.cluster {
+				t.Errorf("cluster 客户端 = %v, 期望 %v", isCluster, tt.cluster)
+			}
+		})
+	}
+}
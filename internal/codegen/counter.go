@@ -0,0 +1,56 @@
+package codegen
+
+import (
+	"context"
+	"sync"
+
+	"github.com/username/shorturl/pkg/utils"
+)
+
+// blockSize 每次从数据库预取的序列值数量，减少数据库往返
+const blockSize = 100
+
+// sequenceAllocator 在本地缓存一段数据库序列区间，用完后再向数据库申请
+type sequenceAllocator struct {
+	seq  Sequence
+	mu   sync.Mutex
+	next int64
+	end  int64 // 不包含
+}
+
+func (a *sequenceAllocator) nextID(ctx context.Context) (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.next >= a.end {
+		start, err := a.seq.NextBlock(ctx, sequenceName, blockSize)
+		if err != nil {
+			return 0, err
+		}
+		a.next, a.end = start, start+blockSize
+	}
+	id := a.next
+	a.next++
+	return id, nil
+}
+
+// CounterGenerator 将数据库序列编码为 base62 短码
+// 序列本身不会重复，短码长度随序列增长自然变长
+type CounterGenerator struct {
+	ids       *sequenceAllocator
+	minLength int
+}
+
+// NewCounterGenerator 创建基于数据库序列的短码生成器
+func NewCounterGenerator(seq Sequence, minLength int) *CounterGenerator {
+	return &CounterGenerator{ids: &sequenceAllocator{seq: seq}, minLength: minLength}
+}
+
+// Generate 取下一个序列值并编码，冲突（如与自定义短码重复）时直接取下一个值
+func (g *CounterGenerator) Generate(ctx context.Context, longURL string, attempt int) (string, error) {
+	id, err := g.ids.nextID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return utils.PadBase62(utils.EncodeBase62(uint64(id)), g.minLength), nil
+}
@@ -0,0 +1,117 @@
+package codegen
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// 支持的短码生成策略
+const (
+	StrategyRandom    = "random"    // 随机 base62
+	StrategyCounter   = "counter"   // 数据库序列 + base62
+	StrategySnowflake = "snowflake" // Snowflake 风格 ID + base62
+	StrategyHashids   = "hashids"   // 数据库序列 + hashids 混淆
+	StrategyHash      = "hash"      // 长链接哈希（相同长链接得到相同短码）
+)
+
+// CodeGenerator 短码生成器
+// 生成器只负责给出候选短码，是否冲突由调用方写入时判断（依赖 short_code 唯一索引），
+// 冲突时以递增的 attempt 再次调用 Generate
+type CodeGenerator interface {
+	// Generate 生成候选短码，attempt 为当前重试次数（从 0 开始）
+	Generate(ctx context.Context, longURL string, attempt int) (string, error)
+}
+
+// Sequence 自增序列，由数据库提供
+type Sequence interface {
+	// NextBlock 分配 size 个连续值，返回区间起点
+	NextBlock(ctx context.Context, name string, size int64) (int64, error)
+}
+
+// Options 生成器配置
+type Options struct {
+	Strategy string
+	// Length 短码初始长度
+	Length int
+	// MaxLength 随机/哈希策略自动增长的长度上限
+	MaxLength int
+	// NodeID Snowflake 节点编号（0-1023），多实例部署时必须各不相同
+	NodeID int64
+	// Salt hashids 混淆使用的盐
+	Salt string
+}
+
+const (
+	// sequenceName 短码序列在 code_sequences 表中的名称
+	sequenceName = "short_code"
+	// maxCodeLength 短码最大长度
+	maxCodeLength = 20
+)
+
+// New 根据配置创建短码生成器
+func New(opts Options, seq Sequence) (CodeGenerator, error) {
+	if opts.Length <= 0 {
+		opts.Length = 6
+	}
+	if opts.MaxLength < opts.Length {
+		opts.MaxLength = opts.Length
+	}
+	// 与 utils.IsValidShortCode 的长度限制保持一致，否则生成的短码无法访问
+	if opts.MaxLength > maxCodeLength {
+		opts.MaxLength = maxCodeLength
+	}
+	if opts.Length > opts.MaxLength {
+		return nil, fmt.Errorf("code length %d exceeds limit %d", opts.Length, maxCodeLength)
+	}
+
+	switch opts.Strategy {
+	case StrategyRandom, "":
+		return NewRandomGenerator(opts.Length, opts.MaxLength), nil
+	case StrategyHash:
+		return NewHashGenerator(opts.Length, opts.MaxLength), nil
+	case StrategyCounter:
+		if seq == nil {
+			return nil, fmt.Errorf("strategy %s requires a database sequence", opts.Strategy)
+		}
+		return NewCounterGenerator(seq, opts.Length), nil
+	case StrategyHashids:
+		if seq == nil {
+			return nil, fmt.Errorf("strategy %s requires a database sequence", opts.Strategy)
+		}
+		return NewHashidsGenerator(seq, opts.Salt, opts.Length), nil
+	case StrategySnowflake:
+		return NewSnowflakeGenerator(opts.NodeID)
+	default:
+		return nil, fmt.Errorf("unknown code generator strategy: %s", opts.Strategy)
+	}
+}
+
+// growAfter 同一次创建中连续冲突达到该次数，说明当前长度的空间已较满，永久增长一位
+const growAfter = 3
+
+// adaptiveLength 随冲突自动增长的短码长度
+type adaptiveLength struct {
+	current atomic.Int64
+	max     int64
+}
+
+func newAdaptiveLength(length, maxLength int) *adaptiveLength {
+	l := &adaptiveLength{max: int64(maxLength)}
+	l.current.Store(int64(length))
+	return l
+}
+
+// forAttempt 返回本次尝试应使用的长度
+func (l *adaptiveLength) forAttempt(attempt int) int {
+	cur := l.current.Load()
+	if attempt >= growAfter && cur < l.max {
+		// 多个并发请求同时触发时只增长一次
+		if l.current.CompareAndSwap(cur, cur+1) {
+			cur++
+		} else {
+			cur = l.current.Load()
+		}
+	}
+	return int(cur)
+}
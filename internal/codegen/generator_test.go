@@ -0,0 +1,151 @@
+package codegen
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/username/shorturl/pkg/utils"
+)
+
+// memorySequence 内存序列，用于替代数据库
+type memorySequence struct {
+	mu    sync.Mutex
+	value int64
+}
+
+func (s *memorySequence) NextBlock(ctx context.Context, name string, size int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	start := s.value + 1
+	s.value += size
+	return start, nil
+}
+
+// TestNew 测试按策略创建生成器
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		seq     Sequence
+		wantErr bool
+	}{
+		{name: "默认随机", opts: Options{}},
+		{name: "哈希", opts: Options{Strategy: StrategyHash}},
+		{name: "计数器", opts: Options{Strategy: StrategyCounter}, seq: &memorySequence{}},
+		{name: "计数器缺少序列", opts: Options{Strategy: StrategyCounter}, wantErr: true},
+		{name: "hashids", opts: Options{Strategy: StrategyHashids, Salt: "salt"}, seq: &memorySequence{}},
+		{name: "snowflake", opts: Options{Strategy: StrategySnowflake, NodeID: 1}},
+		{name: "snowflake 节点编号越界", opts: Options{Strategy: StrategySnowflake, NodeID: 4096}, wantErr: true},
+		{name: "未知策略", opts: Options{Strategy: "unknown"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.opts, tt.seq)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestGenerators_ValidAndUnique 所有策略生成的短码都合法且不重复
+func TestGenerators_ValidAndUnique(t *testing.T) {
+	strategies := []string{StrategyRandom, StrategyCounter, StrategySnowflake, StrategyHashids}
+	for _, strategy := range strategies {
+		t.Run(strategy, func(t *testing.T) {
+			gen, err := New(Options{Strategy: strategy, Length: 6, Salt: "shorturl"}, &memorySequence{})
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+
+			seen := make(map[string]bool)
+			for i := 0; i < 5000; i++ {
+				code, err := gen.Generate(context.Background(), "https://example.com", 0)
+				if err != nil {
+					t.Fatalf("Generate() failed: %v", err)
+				}
+				if !utils.IsValidShortCode(code) {
+					t.Fatalf("Generate() = %q, not a valid short code", code)
+				}
+				if seen[code] {
+					t.Fatalf("Generate() returned duplicate code %q", code)
+				}
+				seen[code] = true
+			}
+		})
+	}
+}
+
+// TestHashGenerator_Deterministic 相同长链接得到相同短码，重试时得到不同短码
+func TestHashGenerator_Deterministic(t *testing.T) {
+	gen := NewHashGenerator(7, 7)
+	ctx := context.Background()
+
+	a, _ := gen.Generate(ctx, "https://example.com/a", 0)
+	b, _ := gen.Generate(ctx, "https://example.com/a", 0)
+	if a != b {
+		t.Errorf("same URL produced different codes: %q and %q", a, b)
+	}
+	if len(a) != 7 {
+		t.Errorf("Expected length 7, got %d", len(a))
+	}
+
+	retry, _ := gen.Generate(ctx, "https://example.com/a", 1)
+	if retry == a {
+		t.Errorf("retry produced the same code %q", retry)
+	}
+}
+
+// TestRandomGenerator_GrowsOnCollisions 连续冲突后长度永久增长，且不超过上限
+func TestRandomGenerator_GrowsOnCollisions(t *testing.T) {
+	gen := NewRandomGenerator(6, 7)
+	ctx := context.Background()
+
+	code, _ := gen.Generate(ctx, "", growAfter-1)
+	if len(code) != 6 {
+		t.Fatalf("Expected length 6 before growth, got %d", len(code))
+	}
+
+	code, _ = gen.Generate(ctx, "", growAfter)
+	if len(code) != 7 {
+		t.Fatalf("Expected length 7 after growth, got %d", len(code))
+	}
+
+	// 后续请求的首次尝试也使用增长后的长度
+	code, _ = gen.Generate(ctx, "", 0)
+	if len(code) != 7 {
+		t.Errorf("Expected length 7 to persist, got %d", len(code))
+	}
+
+	// 达到上限后不再增长
+	code, _ = gen.Generate(ctx, "", growAfter)
+	if len(code) != 7 {
+		t.Errorf("Expected length capped at 7, got %d", len(code))
+	}
+}
+
+// TestHashidsGenerator_MinLength 序列值较小时补齐到最小长度
+func TestHashidsGenerator_MinLength(t *testing.T) {
+	gen := NewHashidsGenerator(&memorySequence{}, "salt", 8)
+	for _, n := range []uint64{0, 1, 61, 62, 1 << 40} {
+		code := gen.encode(n)
+		if len(code) < 8 {
+			t.Errorf("encode(%d) = %q, shorter than min length", n, code)
+		}
+	}
+
+	if gen.encode(1) == NewHashidsGenerator(&memorySequence{}, "other", 8).encode(1) {
+		t.Error("different salts should produce different codes")
+	}
+}
+
+// BenchmarkSnowflakeGenerator 基准测试 Snowflake 生成
+func BenchmarkSnowflakeGenerator(b *testing.B) {
+	gen, _ := NewSnowflakeGenerator(1)
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		_, _ = gen.Generate(ctx, "", 0)
+	}
+}
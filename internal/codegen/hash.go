@@ -0,0 +1,41 @@
+package codegen
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"strconv"
+
+	"github.com/username/shorturl/pkg/utils"
+)
+
+// HashGenerator 对长链接做哈希得到确定性的短码
+// 相同长链接首次尝试总是得到相同的短码；冲突时在长链接后追加尝试次数重新哈希
+type HashGenerator struct {
+	length *adaptiveLength
+}
+
+// NewHashGenerator 创建哈希短码生成器
+func NewHashGenerator(length, maxLength int) *HashGenerator {
+	return &HashGenerator{length: newAdaptiveLength(length, maxLength)}
+}
+
+// Generate 生成基于长链接哈希的短码
+func (g *HashGenerator) Generate(ctx context.Context, longURL string, attempt int) (string, error) {
+	input := longURL
+	if attempt > 0 {
+		input += "#" + strconv.Itoa(attempt)
+	}
+	sum := sha256.Sum256([]byte(input))
+
+	// 依次取 8 字节编码，直到满足长度要求
+	length := g.length.forAttempt(attempt)
+	var code string
+	for i := 0; len(code) < length && i+8 <= len(sum); i += 8 {
+		code += utils.EncodeBase62(binary.BigEndian.Uint64(sum[i : i+8]))
+	}
+	if len(code) > length {
+		code = code[:length]
+	}
+	return code, nil
+}
@@ -0,0 +1,75 @@
+package codegen
+
+import (
+	"context"
+
+	"github.com/username/shorturl/pkg/utils"
+)
+
+// HashidsGenerator 使用 hashids 算法混淆数据库序列，
+// 短码与序列一一对应（不会冲突），但无法从短码猜出相邻的链接
+type HashidsGenerator struct {
+	ids       *sequenceAllocator
+	salt      string
+	alphabet  string
+	minLength int
+}
+
+// NewHashidsGenerator 创建 hashids 短码生成器
+func NewHashidsGenerator(seq Sequence, salt string, minLength int) *HashidsGenerator {
+	return &HashidsGenerator{
+		ids:       &sequenceAllocator{seq: seq},
+		salt:      salt,
+		alphabet:  consistentShuffle(utils.Base62Alphabet, salt),
+		minLength: minLength,
+	}
+}
+
+// Generate 取下一个序列值并混淆编码
+func (g *HashidsGenerator) Generate(ctx context.Context, longURL string, attempt int) (string, error) {
+	id, err := g.ids.nextID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return g.encode(uint64(id)), nil
+}
+
+// encode 单个数字的 hashids 编码：
+// 以 lottery 字符开头，再用 lottery+salt 打乱后的字符集编码数字，长度不足时用打乱后的字符集循环补齐
+func (g *HashidsGenerator) encode(n uint64) string {
+	alphabet := g.alphabet
+	lottery := alphabet[n%100%uint64(len(alphabet))]
+
+	buffer := string(lottery) + g.salt + alphabet
+	alphabet = consistentShuffle(alphabet, buffer[:len(alphabet)])
+	code := string(lottery) + utils.EncodeWithAlphabet(n, alphabet)
+
+	for len(code) < g.minLength {
+		alphabet = consistentShuffle(alphabet, alphabet)
+		half := len(alphabet) / 2
+		code = alphabet[half:] + code + alphabet[:half]
+		if excess := len(code) - g.minLength; excess > 0 {
+			start := excess / 2
+			code = code[start : start+g.minLength]
+		}
+	}
+	return code
+}
+
+// consistentShuffle 以 salt 为种子确定性地打乱字符集（hashids 原始算法）
+func consistentShuffle(alphabet, salt string) string {
+	if salt == "" {
+		return alphabet
+	}
+
+	result := []byte(alphabet)
+	for i, v, p := len(result)-1, 0, 0; i > 0; i-- {
+		v %= len(salt)
+		integer := int(salt[v])
+		p += integer
+		j := (integer + v + p) % i
+		result[i], result[j] = result[j], result[i]
+		v++
+	}
+	return string(result)
+}
@@ -0,0 +1,22 @@
+package codegen
+
+import (
+	"context"
+
+	"github.com/username/shorturl/pkg/utils"
+)
+
+// RandomGenerator 随机 base62 短码
+type RandomGenerator struct {
+	length *adaptiveLength
+}
+
+// NewRandomGenerator 创建随机短码生成器
+func NewRandomGenerator(length, maxLength int) *RandomGenerator {
+	return &RandomGenerator{length: newAdaptiveLength(length, maxLength)}
+}
+
+// Generate 生成随机短码，频繁冲突时自动增加长度
+func (g *RandomGenerator) Generate(ctx context.Context, longURL string, attempt int) (string, error) {
+	return utils.GenerateBase62Code(g.length.forAttempt(attempt))
+}
@@ -0,0 +1,70 @@
+package codegen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/username/shorturl/pkg/utils"
+)
+
+// Snowflake ID 结构：41 位毫秒时间戳 | 10 位节点编号 | 12 位毫秒内序号
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+	maxNodeID    = 1<<nodeBits - 1
+	maxSequence  = 1<<sequenceBits - 1
+)
+
+// snowflakeEpoch 自定义纪元，缩短生成的 ID（2024-01-01 UTC）
+var snowflakeEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+// SnowflakeGenerator 不依赖数据库的分布式唯一 ID，编码为 base62 后约 10-11 位
+type SnowflakeGenerator struct {
+	mu       sync.Mutex
+	nodeID   int64
+	lastMS   int64
+	sequence int64
+	now      func() time.Time
+}
+
+// NewSnowflakeGenerator 创建 Snowflake 短码生成器
+func NewSnowflakeGenerator(nodeID int64) (*SnowflakeGenerator, error) {
+	if nodeID < 0 || nodeID > maxNodeID {
+		return nil, fmt.Errorf("snowflake node id must be between 0 and %d, got %d", maxNodeID, nodeID)
+	}
+	return &SnowflakeGenerator{nodeID: nodeID, now: time.Now}, nil
+}
+
+// Generate 生成下一个 ID 并编码
+func (g *SnowflakeGenerator) Generate(ctx context.Context, longURL string, attempt int) (string, error) {
+	return utils.EncodeBase62(uint64(g.nextID())), nil
+}
+
+func (g *SnowflakeGenerator) nextID() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := g.now().UnixMilli() - snowflakeEpoch
+	if ms < g.lastMS {
+		// 时钟回拨时继续使用上次的时间戳，依靠序号保证唯一
+		ms = g.lastMS
+	}
+
+	if ms == g.lastMS {
+		g.sequence = (g.sequence + 1) & maxSequence
+		if g.sequence == 0 {
+			// 当前毫秒序号用尽，等待下一毫秒
+			for ms <= g.lastMS {
+				time.Sleep(100 * time.Microsecond)
+				ms = g.now().UnixMilli() - snowflakeEpoch
+			}
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastMS = ms
+
+	return ms<<(nodeBits+sequenceBits) | g.nodeID<<sequenceBits | g.sequence
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -13,11 +14,182 @@ import (
 
 // Config 应用配置的总结构体
 type Config struct {
-	GinMode    string
-	HttpAddr   string
-	MySQLDSN   string
-	RedisAddr  string
-	SQLitePath string
+	GinMode  string
+	HttpAddr string
+	// PrimaryDatabase 主数据库类型：mysql | postgres，SQLite 始终作为 fallback
+	PrimaryDatabase string
+	MySQLDSN        string
+	PostgresDSN     string
+	RedisAddr       string
+	SQLitePath      string
+	// Redis 认证、TLS 与 Sentinel / Cluster 部署配置
+	Redis struct {
+		// Mode 部署模式：standalone | sentinel | cluster，留空时有 MasterName 为 sentinel，Addrs 多于一个为 cluster
+		Mode string
+		// Addrs sentinel 或 cluster 节点地址，为空时使用 RedisAddr
+		Addrs    []string
+		Username string
+		Password string
+		// DB 数据库编号，cluster 模式只支持 0
+		DB int
+		// MasterName sentinel 监控的主节点名称
+		MasterName string
+		// SentinelUsername、SentinelPassword sentinel 节点的认证信息
+		SentinelUsername string
+		SentinelPassword string
+		TLS              struct {
+			Enabled bool
+			// CAFile 为空时使用系统根证书
+			CAFile string
+			// CertFile、KeyFile 客户端证书，服务端要求双向认证时配置
+			CertFile           string
+			KeyFile            string
+			ServerName         string
+			InsecureSkipVerify bool
+		}
+	}
+	// 内存缓存容量与淘汰策略
+	MemoryCache struct {
+		// MaxEntries 最大条数，0 表示不限制
+		MaxEntries int
+		// MaxBytes 估算占用字节数上限，0 表示不限制
+		MaxBytes int64
+		// Policy 淘汰策略：lru | lfu
+		Policy string
+	}
+	// 短链接读路径配置
+	ReadPath struct {
+		// Strategy 读取策略：tiered | racing | hedged
+		Strategy string
+		// HedgeDelay hedged 策略下，上一层超过该时长未返回时启动下一层
+		HedgeDelay time.Duration
+		// Timeouts 每层的超时时间，0 表示不单独限制
+		Timeouts struct {
+			Memory   time.Duration
+			Redis    time.Duration
+			Primary  time.Duration
+			Fallback time.Duration
+		}
+	}
+	// 不存在的短码的负缓存
+	NegativeCache struct {
+		// TTL 负缓存有效期，0 表示关闭
+		TTL time.Duration
+	}
+	// 已存在短码的 Bloom filter，一定不存在的短码不再查询缓存和数据库
+	BloomFilter struct {
+		Enabled bool
+		// ExpectedItems 预计短码数量，实际数量更多时按实际数量的两倍分配
+		ExpectedItems uint64
+		// FalsePositiveRate 期望误判率
+		FalsePositiveRate float64
+		// RebuildInterval 从数据库重建的间隔，0 表示只在启动时构建
+		RebuildInterval time.Duration
+	}
+	// 实例之间的缓存失效广播（Redis pub/sub）
+	Invalidation struct {
+		Enabled bool
+		// Channel 广播使用的 Redis 频道
+		Channel string
+	}
+	// 写后（write-behind）持久化：创建短链接时只同步写缓存，数据库由后台批量写入
+	WriteBehind struct {
+		Enabled bool
+		// QueueSize 队列长度，队列满时改为同步写入数据库
+		QueueSize int
+		// BatchSize 单次批量写入的最大条数
+		BatchSize int
+		// FlushInterval 未凑满一批时的最长等待时间
+		FlushInterval time.Duration
+		// MaxRetries 数据库写入失败时的最大重试次数
+		MaxRetries int
+		// RetryBackoff 第一次重试前的等待时间，之后每次翻倍
+		RetryBackoff time.Duration
+		// DeadLetterPath 无法写入数据库的数据追加写入该文件（JSON Lines）
+		DeadLetterPath string
+	}
+	// HTTP 网关与 gRPC 服务的认证（API key / JWT bearer token）
+	Auth struct {
+		// Enabled 关闭时所有接口都不需要认证
+		Enabled bool
+		// APIKeys 静态 API key，通过 X-API-Key 请求头传递
+		APIKeys []struct {
+			Key     string
+			Subject string
+			Roles   []string
+		}
+		// JWT 通过 Authorization: Bearer 传递，Secret（HS256）与 PublicKeyFile（RS256 / ES256）至少配置一个
+		JWT struct {
+			Secret        string
+			PublicKeyFile string
+			// Issuer、Audience 非空时校验 token 的 iss / aud
+			Issuer   string
+			Audience string
+		}
+		// ServiceKey 网关调用 gRPC 服务使用的密钥，gRPC 服务只采用持有该密钥的调用方转发的用户身份
+		ServiceKey string
+	}
+	// 自定义域名：按 Host 请求头把短链接跳转映射到工作区
+	Domains struct {
+		// BaseURL 默认工作区以及没有主域名的工作区的短链接地址前缀
+		BaseURL string
+		// Scheme 自定义域名的短链接地址使用的协议
+		Scheme string
+		// Verifier 域名所有权验证方式：dns（TXT 记录）| none（不验证，仅用于开发环境）
+		Verifier string
+	}
+	// 令牌桶限流：按路由和调用方身份（API key、IP、工作区）限制请求频率，HTTP 网关与 gRPC 服务各自执行匹配的规则
+	RateLimit struct {
+		Enabled bool
+		// Rules 请求匹配的每条规则都扣减一个令牌，任一规则的令牌耗尽即拒绝
+		Rules []struct {
+			// Name 规则名称，不能重复，用于区分令牌桶
+			Name string
+			// Routes HTTP 路由（"POST /shortener/v1/c"）或 gRPC 方法（"/shortener.ShortenerService/CreateShortLink"），
+			// 以 * 结尾表示前缀匹配，"*" 匹配所有请求
+			Routes []string
+			// By 身份维度：ip | api_key | subject | tenant | global
+			By string
+			// Limit 每个 Period 补充的令牌数
+			Limit  int
+			Period time.Duration
+			// Burst 令牌桶容量，0 表示等于 Limit
+			Burst int
+		}
+	}
+	// TrustedProxies 网关信任的反向代理地址（IP 或 CIDR），只采用这些代理设置的 X-Forwarded-For；为空时保持 Gin 的默认行为（信任所有来源）
+	TrustedProxies []string
+	// 启动时自动执行数据库迁移，关闭后需要手动执行 migrate 子命令
+	AutoMigrate bool
+	// 剪贴板片段的有效期，0 表示永不过期
+	ClipboardTTL time.Duration
+	// 短链接未指定有效期时使用的默认有效期，0 表示永不过期
+	LinkDefaultTTL time.Duration
+	// 短链接允许的最长有效期，0 表示不限制（允许永不过期）
+	LinkMaxTTL time.Duration
+	// 短码生成配置
+	CodeGenerator struct {
+		// Strategy 生成策略：random | counter | snowflake | hashids | hash
+		Strategy string
+		// Length 短码初始长度，MaxLength 为随机/哈希策略自动增长的上限
+		Length    int
+		MaxLength int
+		// MaxAttempts 短码冲突时的最大尝试次数
+		MaxAttempts int
+		// NodeID snowflake 节点编号，多实例部署时必须各不相同
+		NodeID int64
+		// Salt hashids 混淆使用的盐
+		Salt string
+	}
+	// SQLite 回退数据的对账配置
+	Reconcile struct {
+		// Enabled 是否启动对账，MySQL 恢复后把写入 SQLite 的数据回放到 MySQL
+		Enabled bool
+		// Interval 检查 MySQL 状态的间隔
+		Interval time.Duration
+		// BatchSize 每批读取的条数
+		BatchSize int
+	}
 	// 客户端访问的 gRPC 服务地址
 	GRPCServers struct {
 		Shortener struct {
@@ -64,11 +236,57 @@ func LoadAll() *Config {
 	v := viper.New()
 	v.SetDefault("GinMode", "debug")
 	v.SetDefault("HttpAddr", ":8080")
+	v.SetDefault("PrimaryDatabase", "mysql")
 	v.SetDefault("MySQLDSN", "user:password@tcp(localhost:3306)/shorturl")
+	v.SetDefault("PostgresDSN", "")
 	v.SetDefault("RedisAddr", "localhost:6379")
 	v.SetDefault("SQLitePath", "./data/shorturl.db")
-	v.SetDefault("GRPCServers.shortener", "localhost:9090")
-	v.SetDefault("GRPCServers.clipboarder", "localhost:9091")
+	v.SetDefault("Redis.Mode", "")
+	v.SetDefault("Redis.DB", 0)
+	v.SetDefault("Redis.TLS.Enabled", false)
+	v.SetDefault("MemoryCache.MaxEntries", 100000)
+	v.SetDefault("MemoryCache.MaxBytes", 64<<20)
+	v.SetDefault("MemoryCache.Policy", "lru")
+	v.SetDefault("ReadPath.Strategy", "tiered")
+	v.SetDefault("ReadPath.HedgeDelay", "20ms")
+	v.SetDefault("ReadPath.Timeouts.Memory", "0s")
+	v.SetDefault("ReadPath.Timeouts.Redis", "50ms")
+	v.SetDefault("ReadPath.Timeouts.Primary", "500ms")
+	v.SetDefault("ReadPath.Timeouts.Fallback", "500ms")
+	v.SetDefault("NegativeCache.TTL", "30s")
+	v.SetDefault("BloomFilter.Enabled", true)
+	v.SetDefault("BloomFilter.ExpectedItems", 1000000)
+	v.SetDefault("BloomFilter.FalsePositiveRate", 0.01)
+	v.SetDefault("BloomFilter.RebuildInterval", "1h")
+	v.SetDefault("Invalidation.Enabled", true)
+	v.SetDefault("Invalidation.Channel", "shorturl:invalidate")
+	v.SetDefault("WriteBehind.Enabled", false)
+	v.SetDefault("WriteBehind.QueueSize", 10000)
+	v.SetDefault("WriteBehind.BatchSize", 200)
+	v.SetDefault("WriteBehind.FlushInterval", "100ms")
+	v.SetDefault("WriteBehind.MaxRetries", 5)
+	v.SetDefault("WriteBehind.RetryBackoff", "200ms")
+	v.SetDefault("WriteBehind.DeadLetterPath", "./data/write_behind_dead_letters.jsonl")
+	v.SetDefault("AutoMigrate", true)
+	v.SetDefault("ClipboardTTL", "24h")
+	v.SetDefault("Auth.Enabled", false)
+	v.SetDefault("Domains.BaseURL", "http://localhost:8080")
+	v.SetDefault("Domains.Scheme", "https")
+	v.SetDefault("Domains.Verifier", "dns")
+	v.SetDefault("RateLimit.Enabled", false)
+	v.SetDefault("LinkDefaultTTL", "0s")
+	v.SetDefault("LinkMaxTTL", "0s")
+	v.SetDefault("CodeGenerator.Strategy", "random")
+	v.SetDefault("CodeGenerator.Length", 6)
+	v.SetDefault("CodeGenerator.MaxLength", 10)
+	v.SetDefault("CodeGenerator.MaxAttempts", 8)
+	v.SetDefault("CodeGenerator.NodeID", 0)
+	v.SetDefault("CodeGenerator.Salt", "shorturl")
+	v.SetDefault("Reconcile.Enabled", true)
+	v.SetDefault("Reconcile.Interval", "30s")
+	v.SetDefault("Reconcile.BatchSize", 200)
+	v.SetDefault("GRPCServers.Shortener.Addr", "localhost:9090")
+	v.SetDefault("GRPCServers.Clipboarder.Addr", "localhost:9091")
 	v.SetDefault("RPC.Shortneer.Addr", ":9090")   // 假设这是 Shortneer 的 RPC 监听地址
 	v.SetDefault("RPC.Clipboarder.Addr", ":9091") // 假设这是 Clipboarder 的 RPC 监听地址
 
@@ -11,4 +11,13 @@ type Database interface {
 	GetDB() *sql.DB
 	// Close 关闭数据库连接
 	Close() error
+	// Dialect SQL 方言，用于选择对应的迁移脚本和 SQL 写法（占位符、upsert）
+	Dialect() string
 }
+
+// 支持的 SQL 方言
+const (
+	DialectMySQL    = "mysql"
+	DialectPostgres = "postgres"
+	DialectSQLite   = "sqlite"
+)
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Rebind 把查询中的 ? 占位符转换为方言的写法：PostgreSQL 为 $1、$2…，MySQL 和 SQLite 不变
+// 单引号字符串和双引号标识符中的 ? 不会被替换
+func Rebind(dialect, query string) string {
+	if dialect != DialectPostgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(query) + 8)
+	n := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			n++
+			sb.WriteByte('$')
+			sb.WriteString(strconv.Itoa(n))
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// OnConflictUpdate 返回 upsert 子句的开头，后面接 "列 = 表达式" 列表
+// MySQL 为 ON DUPLICATE KEY UPDATE（keys 由唯一索引决定，忽略），SQLite 和 PostgreSQL 为 ON CONFLICT (keys) DO UPDATE SET
+func OnConflictUpdate(dialect string, keys ...string) string {
+	if dialect == DialectMySQL {
+		return "ON DUPLICATE KEY UPDATE"
+	}
+	return "ON CONFLICT (" + strings.Join(keys, ", ") + ") DO UPDATE SET"
+}
+
+// Excluded 返回 upsert 中引用待插入值的表达式：MySQL 为 VALUES(column)，SQLite 和 PostgreSQL 为 excluded.column
+// 引用已有的值时需要写成 表名.列名，PostgreSQL 不允许不带表名
+func Excluded(dialect, column string) string {
+	if dialect == DialectMySQL {
+		return "VALUES(" + column + ")"
+	}
+	return "excluded." + column
+}
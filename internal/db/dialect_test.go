@@ -0,0 +1,60 @@
+package db
+
+import "testing"
+
+// TestRebind 测试占位符转换
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		query   string
+		want    string
+	}{
+		{
+			name:    "MySQL 不变",
+			dialect: DialectMySQL,
+			query:   `SELECT * FROM t WHERE a = ? AND b = ?`,
+			want:    `SELECT * FROM t WHERE a = ? AND b = ?`,
+		},
+		{
+			name:    "PostgreSQL 按顺序编号",
+			dialect: DialectPostgres,
+			query:   `INSERT INTO t (a, b) VALUES (?, ?), (?, ?)`,
+			want:    `INSERT INTO t (a, b) VALUES ($1, $2), ($3, $4)`,
+		},
+		{
+			name:    "跳过字符串和标识符中的问号",
+			dialect: DialectPostgres,
+			query:   `SELECT '?', "a?b" FROM t WHERE c = ? AND d = 'it''s ?'`,
+			want:    `SELECT '?', "a?b" FROM t WHERE c = $1 AND d = 'it''s ?'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Rebind(tt.dialect, tt.query); got != tt.want {
+				t.Errorf("Rebind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestUpsertClause 测试各方言的 upsert 写法
+func TestUpsertClause(t *testing.T) {
+	tests := []struct {
+		dialect string
+		want    string
+	}{
+		{DialectMySQL, "ON DUPLICATE KEY UPDATE v = VALUES(v)"},
+		{DialectSQLite, "ON CONFLICT (k) DO UPDATE SET v = excluded.v"},
+		{DialectPostgres, "ON CONFLICT (k) DO UPDATE SET v = excluded.v"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			if got := OnConflictUpdate(tt.dialect, "k") + " v = " + Excluded(tt.dialect, "v"); got != tt.want {
+				t.Errorf("upsert clause = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
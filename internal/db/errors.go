@@ -0,0 +1,36 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// mysqlErrDupEntry MySQL 唯一键冲突错误码
+const mysqlErrDupEntry = 1062
+
+// postgresUniqueViolation PostgreSQL 唯一约束冲突的 SQLSTATE
+const postgresUniqueViolation = "23505"
+
+// IsDuplicateKeyError 判断错误是否为唯一键/主键冲突（兼容 MySQL、PostgreSQL 与 SQLite）
+func IsDuplicateKeyError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrDupEntry
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == postgresUniqueViolation
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique ||
+			sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+
+	return false
+}
@@ -1,15 +1,40 @@
 package db
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/username/shorturl/internal/config"
 )
 
+// ErrNotConfigured 未配置主数据库的连接地址
+var ErrNotConfigured = errors.New("primary database not configured")
+
 // NewDatabase 根据配置创建数据库连接
-// 如果配置了 MySQLDSN，则使用 MySQL；否则使用 SQLite
+// 如果配置了主数据库（MySQL 或 PostgreSQL），则使用主数据库；否则使用 SQLite
 func NewDatabase(cfg *config.Config) (Database, error) {
-	if cfg.MySQLDSN != "" {
-		return NewMySQLDB(cfg.MySQLDSN)
+	database, err := NewPrimaryDatabase(cfg)
+	if errors.Is(err, ErrNotConfigured) {
+		return NewSQLiteDB(cfg.SQLitePath)
 	}
-	return NewSQLiteDB(cfg.SQLitePath)
+	return database, err
 }
 
+// NewPrimaryDatabase 按 PrimaryDatabase 创建主数据库连接：mysql（默认）使用 MySQLDSN，postgres 使用 PostgresDSN
+// 对应的 DSN 为空时返回 ErrNotConfigured
+func NewPrimaryDatabase(cfg *config.Config) (Database, error) {
+	switch cfg.PrimaryDatabase {
+	case "", DialectMySQL:
+		if cfg.MySQLDSN == "" {
+			return nil, ErrNotConfigured
+		}
+		return NewMySQLDB(cfg.MySQLDSN)
+	case DialectPostgres:
+		if cfg.PostgresDSN == "" {
+			return nil, ErrNotConfigured
+		}
+		return NewPostgresDB(cfg.PostgresDSN)
+	default:
+		return nil, fmt.Errorf("unknown primary database: %s", cfg.PrimaryDatabase)
+	}
+}
@@ -0,0 +1,346 @@
+// Package migrate 管理数据库表结构的版本化迁移
+// 迁移脚本按方言放在 migrations/<dialect>/ 下，文件名格式为 <版本号>_<名称>.up.sql / .down.sql，
+// 已执行的版本记录在 schema_migrations 表中
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/username/shorturl/internal/db"
+)
+
+//go:embed migrations
+var migrationFS embed.FS
+
+// lockName MySQL 命名锁 / PostgreSQL advisory lock，避免多个实例同时启动时重复执行迁移
+const lockName = "shorturl_schema_migrations"
+
+// lockTimeout 等待迁移锁的最长时间
+const lockTimeout = 60 * time.Second
+
+// Migration 一个版本的迁移脚本
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status 迁移版本的执行状态
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrator 对单个数据库执行迁移
+type Migrator struct {
+	database   db.Database
+	migrations []Migration
+}
+
+// New 加载数据库方言对应的迁移脚本
+func New(database db.Database) (*Migrator, error) {
+	migrations, err := loadMigrations(database.Dialect())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{database: database, migrations: migrations}, nil
+}
+
+// Up 按版本顺序执行所有未执行的迁移，返回本次执行的版本
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig, true); err != nil {
+				return err
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down 按版本倒序回滚最近执行的 steps 个迁移，返回本次回滚的版本
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("invalid rollback steps: %d", steps)
+	}
+	var rolledBack []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := done[mig.Version]; !ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig, false); err != nil {
+				return err
+			}
+			rolledBack = append(rolledBack, mig)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Status 返回所有迁移版本及其执行状态
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.database.GetDB().Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	done, err := m.appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := Status{Migration: mig}
+		if appliedAt, ok := done[mig.Version]; ok {
+			st.Applied = true
+			st.AppliedAt = &appliedAt
+		}
+		result = append(result, st)
+	}
+	return result, nil
+}
+
+// UpAll 对所有可用的数据库执行未执行的迁移，nil 数据库会被跳过
+func UpAll(ctx context.Context, databases ...db.Database) error {
+	for _, database := range databases {
+		if database == nil {
+			continue
+		}
+		m, err := New(database)
+		if err != nil {
+			return err
+		}
+		applied, err := m.Up(ctx)
+		for _, mig := range applied {
+			log.Printf("[%s] applied migration %d_%s", database.Dialect(), mig.Version, mig.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("[%s] %w", database.Dialect(), err)
+		}
+	}
+	return nil
+}
+
+// apply 执行一个版本的 up 或 down 脚本，并更新 schema_migrations
+// 注意：MySQL 的 DDL 会隐式提交事务，脚本中的多条语句无法整体回滚，因此每个版本只做一件事（PostgreSQL 的 DDL 可以回滚）
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration, up bool) error {
+	script, direction := mig.Up, "up"
+	if !up {
+		script, direction = mig.Down, "down"
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("migration %d_%s %s failed: %w", mig.Version, mig.Name, direction, err)
+		}
+	}
+	dialect := m.database.Dialect()
+	if up {
+		_, err = tx.ExecContext(ctx, db.Rebind(dialect, `INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`), mig.Version, mig.Name, time.Now())
+	} else {
+		_, err = tx.ExecContext(ctx, db.Rebind(dialect, `DELETE FROM schema_migrations WHERE version = ?`), mig.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d_%s: %w", mig.Version, mig.Name, err)
+	}
+	return tx.Commit()
+}
+
+// withLock 在同一个连接上加锁后执行 fn（命名锁与连接绑定）
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.database.GetDB().Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	switch m.database.Dialect() {
+	case db.DialectMySQL:
+		var locked sql.NullInt64
+		if err := conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, ?)`, lockName, int(lockTimeout/time.Second)).Scan(&locked); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if locked.Int64 != 1 {
+			return errors.New("timed out waiting for migration lock")
+		}
+		defer conn.ExecContext(context.Background(), `SELECT RELEASE_LOCK(?)`, lockName)
+	case db.DialectPostgres:
+		// pg_advisory_lock 没有超时参数，通过取消查询限制等待时间
+		lockCtx, cancel := context.WithTimeout(ctx, lockTimeout)
+		_, err := conn.ExecContext(lockCtx, `SELECT pg_advisory_lock(hashtext($1))`, lockName)
+		cancel()
+		if err != nil {
+			if errors.Is(lockCtx.Err(), context.DeadlineExceeded) {
+				return errors.New("timed out waiting for migration lock")
+			}
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, lockName)
+	}
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// ensureTable 创建 schema_migrations 表
+func (m *Migrator) ensureTable(ctx context.Context, conn *sql.Conn) error {
+	// PostgreSQL 没有 DATETIME 类型
+	timeType := "DATETIME"
+	if m.database.Dialect() == db.DialectPostgres {
+		timeType = "TIMESTAMPTZ"
+	}
+	query := `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at ` + timeType + ` NOT NULL
+	)`
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// appliedVersions 查询已执行的版本及执行时间
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		done[version] = appliedAt
+	}
+	return done, rows.Err()
+}
+
+// loadMigrations 读取方言目录下的迁移脚本，按版本号排序
+// 每个版本必须同时有 up 和 down 脚本，版本号不能重复
+func loadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %s: %w", dialect, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		var up bool
+		var base string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			up, base = true, strings.TrimSuffix(name, ".up.sql")
+		case strings.HasSuffix(name, ".down.sql"):
+			base = strings.TrimSuffix(name, ".down.sql")
+		default:
+			continue
+		}
+
+		versionStr, migName, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name: %s", name)
+		}
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", name, err)
+		}
+		data, err := fs.ReadFile(migrationFS, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: migName}
+			byVersion[version] = mig
+		} else if mig.Name != migName {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, mig.Name, migName)
+		}
+		if up {
+			mig.Up = string(data)
+		} else {
+			mig.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down scripts", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// splitStatements 按行尾分号拆分脚本，去掉 -- 注释行（MySQL 驱动默认不支持一次执行多条语句）
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
@@ -0,0 +1,91 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/username/shorturl/internal/db"
+)
+
+// TestLoadMigrations 测试各方言的迁移脚本版本一致且成对存在
+func TestLoadMigrations(t *testing.T) {
+	mysql, err := loadMigrations(db.DialectMySQL)
+	if err != nil {
+		t.Fatalf("loadMigrations(mysql) error = %v", err)
+	}
+	for _, dialect := range []string{db.DialectPostgres, db.DialectSQLite} {
+		other, err := loadMigrations(dialect)
+		if err != nil {
+			t.Fatalf("loadMigrations(%s) error = %v", dialect, err)
+		}
+		if len(mysql) != len(other) {
+			t.Fatalf("mysql has %d migrations, %s has %d", len(mysql), dialect, len(other))
+		}
+		for i := range mysql {
+			if mysql[i].Version != other[i].Version || mysql[i].Name != other[i].Name {
+				t.Errorf("migration %d mismatch: mysql %d_%s, %s %d_%s",
+					i, mysql[i].Version, mysql[i].Name, dialect, other[i].Version, other[i].Name)
+			}
+		}
+	}
+}
+
+// TestMigratorSQLite 测试在 SQLite 上执行、回滚迁移
+func TestMigratorSQLite(t *testing.T) {
+	ctx := context.Background()
+	database, err := db.NewSQLiteDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteDB() error = %v", err)
+	}
+	defer database.Close()
+
+	m, err := New(database)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	total := len(m.migrations)
+
+	applied, err := m.Up(ctx)
+	if err != nil || len(applied) != total {
+		t.Fatalf("Up() = %d migrations, %v; want %d", len(applied), err, total)
+	}
+	if _, err := database.GetDB().Exec(`INSERT INTO short_urls (short_code, long_url, created_at) VALUES ('abc', 'https://example.com', CURRENT_TIMESTAMP)`); err != nil {
+		t.Fatalf("short_urls not usable after Up(): %v", err)
+	}
+
+	// 再次执行不应有新的迁移
+	if applied, err := m.Up(ctx); err != nil || len(applied) != 0 {
+		t.Fatalf("second Up() = %d migrations, %v; want 0", len(applied), err)
+	}
+
+	rolledBack, err := m.Down(ctx, 1)
+	if err != nil || len(rolledBack) != 1 || rolledBack[0].Version != m.migrations[total-1].Version {
+		t.Fatalf("Down(1) = %v, %v", rolledBack, err)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	for i, st := range statuses {
+		if want := i < total-1; st.Applied != want {
+			t.Errorf("version %d applied = %v, want %v", st.Version, st.Applied, want)
+		}
+	}
+
+	if _, err := m.Down(ctx, total); err != nil {
+		t.Fatalf("Down(all) error = %v", err)
+	}
+	if _, err := database.GetDB().Exec(`SELECT 1 FROM short_urls`); err == nil {
+		t.Error("short_urls still exists after rolling back all migrations")
+	}
+}
+
+// TestSplitStatements 测试脚本拆分
+func TestSplitStatements(t *testing.T) {
+	script := "-- 注释\nCREATE TABLE a (id INT);\n\nCREATE INDEX idx ON a (id);\n"
+	got := splitStatements(script)
+	if len(got) != 2 || got[0] != "CREATE TABLE a (id INT)" || got[1] != "CREATE INDEX idx ON a (id)" {
+		t.Errorf("splitStatements() = %q", got)
+	}
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS short_urls (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    short_code VARCHAR(64) NOT NULL,
+    long_url TEXT NOT NULL,
+    redirect_code INT NOT NULL DEFAULT 302,
+    created_at DATETIME(3) NOT NULL,
+    expires_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_short_urls_short_code (short_code),
+    KEY idx_short_urls_created_at (created_at),
+    KEY idx_short_urls_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS clipboards (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    short_key VARCHAR(64) NOT NULL,
+    content MEDIUMTEXT NOT NULL,
+    created_at DATETIME(3) NOT NULL,
+    expires_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_clipboards_short_key (short_key)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
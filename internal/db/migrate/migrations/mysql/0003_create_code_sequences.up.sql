@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS code_sequences (
+    name VARCHAR(64) NOT NULL,
+    value BIGINT NOT NULL,
+    PRIMARY KEY (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
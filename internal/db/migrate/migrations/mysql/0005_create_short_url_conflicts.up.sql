@@ -0,0 +1,14 @@
+-- 对账时冲突中落选的短链接数据，保留用于人工核对
+CREATE TABLE IF NOT EXISTS short_url_conflicts (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    short_code VARCHAR(64) NOT NULL,
+    long_url TEXT NOT NULL,
+    redirect_code INT NOT NULL,
+    created_at DATETIME(3) NOT NULL,
+    expires_at DATETIME(3) NULL,
+    source VARCHAR(16) NOT NULL,
+    host VARCHAR(255) NOT NULL DEFAULT '',
+    resolved_at DATETIME(3) NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_short_url_conflicts_short_code (short_code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,8 @@
+-- 短链接的所有者，subject 为认证身份（API key 的 Subject 或 JWT 的 sub）
+CREATE TABLE IF NOT EXISTS users (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    subject VARCHAR(255) NOT NULL,
+    created_at DATETIME(3) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_users_subject (subject)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,3 @@
+ALTER TABLE short_url_conflicts DROP COLUMN owner_id;
+DROP INDEX idx_short_urls_owner_id ON short_urls;
+ALTER TABLE short_urls DROP COLUMN owner_id;
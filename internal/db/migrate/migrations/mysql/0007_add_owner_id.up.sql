@@ -0,0 +1,4 @@
+-- owner_id 为 users.id，0 表示没有所有者（启用认证之前创建的短链接）
+ALTER TABLE short_urls ADD COLUMN owner_id BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX idx_short_urls_owner_id ON short_urls (owner_id, id);
+ALTER TABLE short_url_conflicts ADD COLUMN owner_id BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- 回滚前需要确认不同工作区之间没有重复的短码，否则唯一约束创建失败
+ALTER TABLE short_urls DROP INDEX uk_short_urls_workspace_code, ADD UNIQUE KEY uk_short_urls_short_code (short_code);
@@ -0,0 +1 @@
+ALTER TABLE short_urls DROP INDEX uk_short_urls_short_code, ADD UNIQUE KEY uk_short_urls_workspace_code (workspace_id, short_code);
@@ -0,0 +1 @@
+ALTER TABLE clicks ADD COLUMN workspace_id BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE clicks DROP INDEX idx_clicks_workspace_clicked_at, DROP INDEX idx_clicks_workspace_code_clicked_at, ADD KEY idx_clicks_short_code_clicked_at (short_code, clicked_at);
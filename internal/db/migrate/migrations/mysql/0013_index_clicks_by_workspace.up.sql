@@ -0,0 +1 @@
+ALTER TABLE clicks DROP INDEX idx_clicks_short_code_clicked_at, ADD KEY idx_clicks_workspace_code_clicked_at (workspace_id, short_code, clicked_at), ADD KEY idx_clicks_workspace_clicked_at (workspace_id, clicked_at);
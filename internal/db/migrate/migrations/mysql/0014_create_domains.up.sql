@@ -0,0 +1,14 @@
+-- 自定义域名：Host 请求头映射到工作区，验证通过（verified_at 非空）后才会生效
+-- is_primary 为工作区生成短链接地址时使用的域名，每个工作区最多一个
+CREATE TABLE IF NOT EXISTS domains (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    hostname VARCHAR(253) NOT NULL,
+    workspace_id BIGINT NOT NULL,
+    is_primary TINYINT(1) NOT NULL DEFAULT 0,
+    verification_token VARCHAR(64) NOT NULL,
+    verified_at DATETIME(3) NULL,
+    created_at DATETIME(3) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_domains_hostname (hostname),
+    KEY idx_domains_workspace_id (workspace_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS short_urls (
+    id BIGSERIAL PRIMARY KEY,
+    short_code VARCHAR(64) NOT NULL,
+    long_url TEXT NOT NULL,
+    redirect_code INT NOT NULL DEFAULT 302,
+    created_at TIMESTAMPTZ(3) NOT NULL,
+    expires_at TIMESTAMPTZ(3) NULL,
+    CONSTRAINT uk_short_urls_short_code UNIQUE (short_code)
+);
+CREATE INDEX IF NOT EXISTS idx_short_urls_created_at ON short_urls (created_at);
+CREATE INDEX IF NOT EXISTS idx_short_urls_expires_at ON short_urls (expires_at);
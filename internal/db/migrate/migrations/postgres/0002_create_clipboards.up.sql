@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS clipboards (
+    id BIGSERIAL PRIMARY KEY,
+    short_key VARCHAR(64) NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ(3) NOT NULL,
+    expires_at TIMESTAMPTZ(3) NULL,
+    CONSTRAINT uk_clipboards_short_key UNIQUE (short_key)
+);
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS code_sequences (
+    name VARCHAR(64) NOT NULL PRIMARY KEY,
+    value BIGINT NOT NULL
+);
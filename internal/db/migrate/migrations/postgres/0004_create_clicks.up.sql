@@ -0,0 +1,12 @@
+-- clicked_at 为 Unix 秒，统计时按整数计算时间桶
+CREATE TABLE IF NOT EXISTS clicks (
+    id BIGSERIAL PRIMARY KEY,
+    short_code VARCHAR(64) NOT NULL,
+    clicked_at BIGINT NOT NULL,
+    referrer VARCHAR(2048) NOT NULL DEFAULT '',
+    user_agent VARCHAR(1024) NOT NULL DEFAULT '',
+    ip_hash CHAR(64) NOT NULL DEFAULT '',
+    ip_prefix VARCHAR(64) NOT NULL DEFAULT '',
+    country VARCHAR(8) NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_clicks_short_code_clicked_at ON clicks (short_code, clicked_at);
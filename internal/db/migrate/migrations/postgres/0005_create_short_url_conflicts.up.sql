@@ -0,0 +1,13 @@
+-- 对账时冲突中落选的短链接数据，保留用于人工核对
+CREATE TABLE IF NOT EXISTS short_url_conflicts (
+    id BIGSERIAL PRIMARY KEY,
+    short_code VARCHAR(64) NOT NULL,
+    long_url TEXT NOT NULL,
+    redirect_code INT NOT NULL,
+    created_at TIMESTAMPTZ(3) NOT NULL,
+    expires_at TIMESTAMPTZ(3) NULL,
+    source VARCHAR(16) NOT NULL,
+    host VARCHAR(255) NOT NULL DEFAULT '',
+    resolved_at TIMESTAMPTZ(3) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_short_url_conflicts_short_code ON short_url_conflicts (short_code);
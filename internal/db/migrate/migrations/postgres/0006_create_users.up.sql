@@ -0,0 +1,7 @@
+-- 短链接的所有者，subject 为认证身份（API key 的 Subject 或 JWT 的 sub）
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    subject VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ(3) NOT NULL,
+    CONSTRAINT uk_users_subject UNIQUE (subject)
+);
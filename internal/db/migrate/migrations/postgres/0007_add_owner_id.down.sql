@@ -0,0 +1,3 @@
+ALTER TABLE short_url_conflicts DROP COLUMN IF EXISTS owner_id;
+DROP INDEX IF EXISTS idx_short_urls_owner_id;
+ALTER TABLE short_urls DROP COLUMN IF EXISTS owner_id;
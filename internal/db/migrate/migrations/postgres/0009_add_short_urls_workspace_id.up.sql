@@ -0,0 +1,2 @@
+-- 短码只在工作区内唯一，workspace_id 0 为默认工作区（启用工作区之前的数据）
+ALTER TABLE short_urls ADD COLUMN IF NOT EXISTS workspace_id BIGINT NOT NULL DEFAULT 0;
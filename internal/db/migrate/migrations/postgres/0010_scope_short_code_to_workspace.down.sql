@@ -0,0 +1,3 @@
+-- 回滚前需要确认不同工作区之间没有重复的短码，否则唯一约束创建失败
+ALTER TABLE short_urls DROP CONSTRAINT IF EXISTS uk_short_urls_workspace_code;
+ALTER TABLE short_urls ADD CONSTRAINT uk_short_urls_short_code UNIQUE (short_code);
@@ -0,0 +1,2 @@
+ALTER TABLE short_urls DROP CONSTRAINT IF EXISTS uk_short_urls_short_code;
+ALTER TABLE short_urls ADD CONSTRAINT uk_short_urls_workspace_code UNIQUE (workspace_id, short_code);
@@ -0,0 +1 @@
+ALTER TABLE short_url_conflicts DROP COLUMN IF EXISTS workspace_id;
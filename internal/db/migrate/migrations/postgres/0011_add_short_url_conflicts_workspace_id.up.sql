@@ -0,0 +1 @@
+ALTER TABLE short_url_conflicts ADD COLUMN IF NOT EXISTS workspace_id BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_clicks_short_code_clicked_at;
+CREATE INDEX IF NOT EXISTS idx_clicks_workspace_code_clicked_at ON clicks (workspace_id, short_code, clicked_at);
+CREATE INDEX IF NOT EXISTS idx_clicks_workspace_clicked_at ON clicks (workspace_id, clicked_at);
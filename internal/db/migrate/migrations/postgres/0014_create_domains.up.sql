@@ -0,0 +1,13 @@
+-- 自定义域名：Host 请求头映射到工作区，验证通过（verified_at 非空）后才会生效
+-- is_primary 为工作区生成短链接地址时使用的域名，每个工作区最多一个
+CREATE TABLE IF NOT EXISTS domains (
+    id BIGSERIAL PRIMARY KEY,
+    hostname VARCHAR(253) NOT NULL,
+    workspace_id BIGINT NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    verification_token VARCHAR(64) NOT NULL,
+    verified_at TIMESTAMPTZ(3) NULL,
+    created_at TIMESTAMPTZ(3) NOT NULL,
+    CONSTRAINT uk_domains_hostname UNIQUE (hostname)
+);
+CREATE INDEX IF NOT EXISTS idx_domains_workspace_id ON domains (workspace_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS short_urls (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    short_code TEXT NOT NULL UNIQUE,
+    long_url TEXT NOT NULL,
+    redirect_code INTEGER NOT NULL DEFAULT 302,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NULL
+);
+CREATE INDEX IF NOT EXISTS idx_short_urls_created_at ON short_urls (created_at);
+CREATE INDEX IF NOT EXISTS idx_short_urls_expires_at ON short_urls (expires_at);
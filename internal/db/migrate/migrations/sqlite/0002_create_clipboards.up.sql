@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS clipboards (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    short_key TEXT NOT NULL UNIQUE,
+    content TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NULL
+);
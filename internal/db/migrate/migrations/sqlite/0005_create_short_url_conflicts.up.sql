@@ -0,0 +1,13 @@
+-- 对账时冲突中落选的短链接数据，保留用于人工核对
+CREATE TABLE IF NOT EXISTS short_url_conflicts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    short_code TEXT NOT NULL,
+    long_url TEXT NOT NULL,
+    redirect_code INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NULL,
+    source TEXT NOT NULL,
+    host TEXT NOT NULL DEFAULT '',
+    resolved_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_short_url_conflicts_short_code ON short_url_conflicts (short_code);
@@ -0,0 +1,7 @@
+-- 短链接的所有者，subject 为认证身份（API key 的 Subject 或 JWT 的 sub）
+-- 主数据库可用时 id 以主数据库为准，SQLite 中保存同 id 的副本
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    subject TEXT NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL
+);
@@ -0,0 +1,19 @@
+-- 工作区（租户）：独立的短码命名空间、成员、配置与配额；id 0 保留给默认工作区
+-- settings 为 JSON，max_links / max_monthly_clicks 为 0 表示不限制
+CREATE TABLE IF NOT EXISTS workspaces (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    slug TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL DEFAULT '',
+    settings TEXT NOT NULL,
+    max_links INTEGER NOT NULL DEFAULT 0,
+    max_monthly_clicks INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL
+);
+-- role: admin 可以管理工作区内所有短链接，member 只能管理自己创建的短链接
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    role TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (workspace_id, user_id)
+);
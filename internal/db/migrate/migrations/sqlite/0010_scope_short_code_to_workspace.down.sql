@@ -0,0 +1,21 @@
+-- 回滚前需要确认不同工作区之间没有重复的短码，否则复制时唯一约束失败
+CREATE TABLE short_urls_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    short_code TEXT NOT NULL UNIQUE,
+    long_url TEXT NOT NULL,
+    redirect_code INTEGER NOT NULL DEFAULT 302,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NULL,
+    owner_id INTEGER NOT NULL DEFAULT 0,
+    workspace_id INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO short_urls_old (id, short_code, long_url, redirect_code, created_at, expires_at, owner_id, workspace_id)
+    SELECT id, short_code, long_url, redirect_code, created_at, expires_at, owner_id, workspace_id FROM short_urls;
+-- 沿用原表的自增序列，避免已删除（已对账）的 id 被重新分配
+DELETE FROM sqlite_sequence WHERE name = 'short_urls_old';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'short_urls_old', seq FROM sqlite_sequence WHERE name = 'short_urls';
+DROP TABLE short_urls;
+ALTER TABLE short_urls_old RENAME TO short_urls;
+CREATE INDEX IF NOT EXISTS idx_short_urls_created_at ON short_urls (created_at);
+CREATE INDEX IF NOT EXISTS idx_short_urls_expires_at ON short_urls (expires_at);
+CREATE INDEX IF NOT EXISTS idx_short_urls_owner_id ON short_urls (owner_id, id);
@@ -0,0 +1,22 @@
+-- SQLite 无法删除列上的 UNIQUE 约束，重建 short_urls（保留 id，对账依赖 SQLite 中的 id）
+CREATE TABLE short_urls_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    workspace_id INTEGER NOT NULL DEFAULT 0,
+    short_code TEXT NOT NULL,
+    long_url TEXT NOT NULL,
+    redirect_code INTEGER NOT NULL DEFAULT 302,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NULL,
+    owner_id INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (workspace_id, short_code)
+);
+INSERT INTO short_urls_new (id, workspace_id, short_code, long_url, redirect_code, created_at, expires_at, owner_id)
+    SELECT id, workspace_id, short_code, long_url, redirect_code, created_at, expires_at, owner_id FROM short_urls;
+-- 沿用原表的自增序列，避免已删除（已对账）的 id 被重新分配
+DELETE FROM sqlite_sequence WHERE name = 'short_urls_new';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'short_urls_new', seq FROM sqlite_sequence WHERE name = 'short_urls';
+DROP TABLE short_urls;
+ALTER TABLE short_urls_new RENAME TO short_urls;
+CREATE INDEX IF NOT EXISTS idx_short_urls_created_at ON short_urls (created_at);
+CREATE INDEX IF NOT EXISTS idx_short_urls_expires_at ON short_urls (expires_at);
+CREATE INDEX IF NOT EXISTS idx_short_urls_owner_id ON short_urls (owner_id, id);
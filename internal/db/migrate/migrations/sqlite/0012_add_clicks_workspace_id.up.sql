@@ -0,0 +1 @@
+ALTER TABLE clicks ADD COLUMN workspace_id INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,12 @@
+-- 自定义域名：Host 请求头映射到工作区，验证通过（verified_at 非空）后才会生效
+-- is_primary 为工作区生成短链接地址时使用的域名，每个工作区最多一个
+CREATE TABLE IF NOT EXISTS domains (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    hostname TEXT NOT NULL UNIQUE,
+    workspace_id INTEGER NOT NULL,
+    is_primary INTEGER NOT NULL DEFAULT 0,
+    verification_token TEXT NOT NULL,
+    verified_at DATETIME NULL,
+    created_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_domains_workspace_id ON domains (workspace_id);
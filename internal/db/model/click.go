@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// Click 短链接点击事件
+type Click struct {
+	ID          int64     `json:"id"`
+	WorkspaceID int64     `json:"workspace_id"`
+	ShortCode   string    `json:"short_code"`
+	ClickedAt   time.Time `json:"clicked_at"`
+	Referrer    string    `json:"referrer"`
+	UserAgent   string    `json:"user_agent"`
+	// IPHash 访问者 IP 的哈希，用于去重统计，不保存原始 IP
+	IPHash string `json:"ip_hash"`
+	// IPPrefix 匿名化后的网段（IPv4 /24，IPv6 /48）
+	IPPrefix string `json:"ip_prefix"`
+	Country  string `json:"country"`
+}
+
+// LinkStats 单个短链接的点击统计
+type LinkStats struct {
+	TotalClicks   int64
+	Buckets       []ClickBucket
+	TopReferrers  []CountEntry
+	TopUserAgents []CountEntry
+}
+
+// ClickBucket 时间桶内的点击数
+type ClickBucket struct {
+	Start time.Time
+	Count int64
+}
+
+// CountEntry 排行榜条目
+type CountEntry struct {
+	Value string
+	Count int64
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Clipboard 剪贴板片段模型
+type Clipboard struct {
+	ID        int64      `json:"id"`
+	ShortKey  string     `json:"short_key"` // 访问短码
+	Content   string     `json:"content"`   // 片段内容
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // 可选：过期时间
+}
+
+// IsExpired 检查片段是否已过期，未设置过期时间则永不过期
+func (c *Clipboard) IsExpired() bool {
+	if c.ExpiresAt == nil || c.ExpiresAt.IsZero() {
+		return false
+	}
+	return time.Now().After(*c.ExpiresAt)
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Domain 自定义域名，把 Host 请求头映射到工作区
+type Domain struct {
+	ID int64 `json:"id"`
+	// Hostname 小写、不带端口的主机名
+	Hostname    string `json:"hostname"`
+	WorkspaceID int64  `json:"workspace_id"`
+	// Primary 工作区生成短链接地址时使用的域名
+	Primary bool `json:"primary"`
+	// VerificationToken 验证域名所有权时需要发布的值
+	VerificationToken string `json:"verification_token"`
+	// VerifiedAt 验证通过的时间，未验证的域名不会用于跳转和生成短链接地址
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Verified 域名是否已验证
+func (d *Domain) Verified() bool {
+	return d.VerifiedAt != nil
+}
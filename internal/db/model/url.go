@@ -1,13 +1,85 @@
 package model
 
-import "time"
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultRedirectCode 未指定时使用的重定向状态码
+const DefaultRedirectCode = http.StatusFound
 
 // ShortURL 短链接模型
 type ShortURL struct {
-	ID        int64     `json:"id"`
-	ShortCode string    `json:"short_code"` // 短码
-	LongURL   string    `json:"long_url"`   // 原始长链接
-	CreatedAt time.Time `json:"created_at"`
-	ExpiresAt *time.Time `json:"expires_at,omitempty"` // 可选：过期时间
+	ID           int64      `json:"id"`
+	ShortCode    string     `json:"short_code"`    // 短码
+	LongURL      string     `json:"long_url"`      // 原始长链接
+	RedirectCode int        `json:"redirect_code"` // 重定向状态码：301/302/307/308
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`   // 可选：过期时间
+	OwnerID      int64      `json:"owner_id,omitempty"`     // 所有者 users.id，0 表示没有所有者
+	WorkspaceID  int64      `json:"workspace_id,omitempty"` // 所属工作区，0 表示默认工作区
+}
+
+// ShortURLUpdate 短链接的部分更新，nil 字段保持不变
+type ShortURLUpdate struct {
+	LongURL      *string
+	RedirectCode *int
+	// ExpiresAt 新的过期时间；ClearExpiresAt 为 true 时改为永不过期
+	ExpiresAt      *time.Time
+	ClearExpiresAt bool
+}
+
+// IsEmpty 是否没有任何需要更新的字段
+func (u *ShortURLUpdate) IsEmpty() bool {
+	return u.LongURL == nil && u.RedirectCode == nil && u.ExpiresAt == nil && !u.ClearExpiresAt
+}
+
+// IsExpired 检查短链接是否已过期，未设置过期时间则永不过期
+func (u *ShortURL) IsExpired() bool {
+	if u.ExpiresAt == nil || u.ExpiresAt.IsZero() {
+		return false
+	}
+	return time.Now().After(*u.ExpiresAt)
 }
 
+// IsValidRedirectCode 检查是否为支持的重定向状态码
+func IsValidRedirectCode(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// 列表排序方式
+const (
+	SortCreatedDesc   = "created_desc"
+	SortCreatedAsc    = "created_asc"
+	SortShortCodeAsc  = "short_code_asc"
+	SortShortCodeDesc = "short_code_desc"
+)
+
+// 列表过期状态过滤
+const (
+	StatusAll     = ""
+	StatusActive  = "active"
+	StatusExpired = "expired"
+)
+
+// ListQuery 短链接分页查询条件（基于游标的分页）
+type ListQuery struct {
+	PageSize int
+	Sort     string
+	// AfterID / AfterCode 上一页最后一条的排序键，按创建顺序排序时使用 ID，按短码排序时使用 ShortCode
+	AfterID   int64
+	AfterCode string
+
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Status        string
+	// HostContains 目标地址域名包含的子串（不区分大小写）
+	HostContains string
+	// OwnerID 只查询该用户的短链接，0 表示不限制
+	OwnerID int64
+}
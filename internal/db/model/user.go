@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// User 短链接的所有者，由认证身份首次访问时创建
+type User struct {
+	ID int64 `json:"id"`
+	// Subject 认证身份：API key 的 Subject 或 JWT 的 sub
+	Subject   string    `json:"subject"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// DefaultWorkspaceID 默认工作区，启用工作区之前的数据和未指定工作区的请求都属于默认工作区
+const DefaultWorkspaceID int64 = 0
+
+// 工作区成员角色
+const (
+	// WorkspaceRoleAdmin 可以管理工作区内所有成员创建的短链接
+	WorkspaceRoleAdmin = "admin"
+	// WorkspaceRoleMember 只能管理自己创建的短链接
+	WorkspaceRoleMember = "member"
+)
+
+// Workspace 工作区（租户），拥有独立的短码命名空间、成员、配置与配额
+type Workspace struct {
+	ID       int64             `json:"id"`
+	Slug     string            `json:"slug"`
+	Name     string            `json:"name"`
+	Settings WorkspaceSettings `json:"settings"`
+	// MaxLinks 短链接数量上限，0 表示不限制
+	MaxLinks int64 `json:"max_links"`
+	// MaxMonthlyClicks 每个自然月（UTC）的点击数上限，超出后短链接停止跳转，0 表示不限制
+	MaxMonthlyClicks int64     `json:"max_monthly_clicks"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// WorkspaceSettings 工作区配置，零值字段使用全局配置
+type WorkspaceSettings struct {
+	// DefaultRedirectCode 未指定时使用的重定向状态码
+	DefaultRedirectCode int `json:"default_redirect_code,omitempty"`
+	// LinkDefaultTTL 未指定有效期时使用的默认有效期
+	LinkDefaultTTL time.Duration `json:"link_default_ttl,omitempty"`
+	// LinkMaxTTL 允许的最长有效期
+	LinkMaxTTL time.Duration `json:"link_max_ttl,omitempty"`
+}
+
+// WorkspaceMember 工作区成员
+type WorkspaceMember struct {
+	WorkspaceID int64     `json:"workspace_id"`
+	UserID      int64     `json:"user_id"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+}
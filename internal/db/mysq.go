@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
 // MySQLDB MySQL 数据库实现
@@ -13,8 +13,12 @@ type MySQLDB struct {
 	db *sql.DB
 }
 
-// NewMySQLDB 创建新的 MySQL 数据库连接
+// NewMySQLDB 创建新的 MySQL 数据库连接，DSN 中的连接参数见 normalizeMySQLDSN
 func NewMySQLDB(dsn string) (Database, error) {
+	dsn, err := normalizeMySQLDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open mysql connection: %w", err)
@@ -34,6 +38,21 @@ func NewMySQLDB(dsn string) (Database, error) {
 	return &MySQLDB{db: db}, nil
 }
 
+// normalizeMySQLDSN 设置仓库依赖的连接参数，覆盖 DSN 中的同名参数：
+//   - clientFoundRows：UPDATE 的影响行数按匹配的行计算，与 SQLite、PostgreSQL 一致，
+//     否则写入与原值相同的数据时影响行数为 0，会被误判为短码不存在
+//   - parseTime 与 loc=UTC：DATETIME 列扫描为 time.Time / sql.NullTime，否则驱动返回 []byte，扫描失败
+func normalizeMySQLDSN(dsn string) (string, error) {
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return "", fmt.Errorf("invalid mysql dsn: %w", err)
+	}
+	cfg.ClientFoundRows = true
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+	return cfg.FormatDSN(), nil
+}
+
 // GetDB 获取数据库连接
 func (m *MySQLDB) GetDB() *sql.DB {
 	return m.db
@@ -43,3 +62,8 @@ func (m *MySQLDB) GetDB() *sql.DB {
 func (m *MySQLDB) Close() error {
 	return m.db.Close()
 }
+
+// Dialect 返回 SQL 方言
+func (m *MySQLDB) Dialect() string {
+	return DialectMySQL
+}
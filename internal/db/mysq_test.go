@@ -0,0 +1,44 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// TestNormalizeMySQLDSN 测试 DSN 中启用 clientFoundRows、parseTime 和 UTC 时区，且保留原有参数
+func TestNormalizeMySQLDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		dsn     string
+		wantErr bool
+	}{
+		{name: "没有参数", dsn: "user:password@tcp(localhost:3306)/shorturl"},
+		{name: "保留原有参数", dsn: "user:password@tcp(localhost:3306)/shorturl?charset=utf8mb4&timeout=5s"},
+		{name: "覆盖显式关闭", dsn: "user:password@tcp(localhost:3306)/shorturl?clientFoundRows=false&parseTime=false&loc=Local"},
+		{name: "无效 DSN", dsn: "user:password@tcp(localhost:3306)", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeMySQLDSN(tt.dsn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeMySQLDSN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			cfg, err := mysql.ParseDSN(got)
+			if err != nil {
+				t.Fatalf("ParseDSN(%s) error = %v", got, err)
+			}
+			if !cfg.ClientFoundRows || !cfg.ParseTime || cfg.Loc != time.UTC {
+				t.Errorf("normalizeMySQLDSN() = %s, want clientFoundRows, parseTime and loc=UTC", got)
+			}
+			original, _ := mysql.ParseDSN(tt.dsn)
+			if cfg.User != original.User || cfg.Addr != original.Addr || cfg.DBName != original.DBName || cfg.Timeout != original.Timeout {
+				t.Errorf("normalizeMySQLDSN() = %s, lost parameters of %s", got, tt.dsn)
+			}
+		})
+	}
+}
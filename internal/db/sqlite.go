@@ -42,3 +42,7 @@ func (s *SQLiteDB) Close() error {
 	return s.db.Close()
 }
 
+// Dialect 返回 SQL 方言
+func (s *SQLiteDB) Dialect() string {
+	return DialectSQLite
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/shorturl/internal/auth"
+)
+
+// authRequired 认证 X-API-Key 或 Authorization: Bearer，认证身份写入请求的 context，由 gRPC 客户端拦截器转发给后端
+// 未启用认证（authenticator 为 nil）时直接放行
+func authRequired(authenticator *auth.Authenticator) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if authenticator == nil {
+			ctx.Next()
+			return
+		}
+		creds := auth.Credentials{APIKey: ctx.GetHeader("X-API-Key")}
+		if bearer, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer "); ok {
+			creds.Bearer = strings.TrimSpace(bearer)
+		}
+		id, err := authenticator.Authenticate(creds)
+		if err != nil {
+			ctx.Header("WWW-Authenticate", `Bearer realm="shorturl"`)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		ctx.Request = ctx.Request.WithContext(auth.WithIdentity(ctx.Request.Context(), id))
+		ctx.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	clipboarderpb "github.com/username/shorturl/internal/rpc/proto"
+)
+
+func (rh *RouterHandlers) RegisterClipboardRoutes(group *gin.RouterGroup) {
+	group.POST("/c", rh.HandleSaveClipboard)
+	group.GET("/:key", rh.HandleGetClipboard)
+}
+
+// HandleSaveClipboard 保存剪贴板片段，返回访问短码
+func (rh *RouterHandlers) HandleSaveClipboard(ctx *gin.Context) {
+	var reqBody struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	resp, err := rh.Clipboard.SaveClipboard(ctx, &clipboarderpb.SaveClipboardRequest{
+		ClipboarderContent: reqBody.Content,
+	})
+	if err != nil {
+		writeRPCError(ctx, "Clipboarder", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"short_key": resp.GetShortKey()})
+}
+
+// HandleGetClipboard 根据短码读取剪贴板片段
+func (rh *RouterHandlers) HandleGetClipboard(ctx *gin.Context) {
+	resp, err := rh.Clipboard.GetClipboard(ctx, &clipboarderpb.GetClipboardRequest{
+		ShortKey: ctx.Param("key"),
+	})
+	if err != nil {
+		log.Printf("Clipboarder RPC failed: %v", err)
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": "Backend service unavailable"})
+		return
+	}
+
+	if !resp.GetIsFound() {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Clipboard not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"content": resp.GetGetClipboard()})
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// writeRPCError 将后端 gRPC 错误转换为 HTTP 响应
+// 业务错误（参数错误、冲突等）透传错误信息，其余错误统一返回 502
+func writeRPCError(ctx *gin.Context, rpcName string, err error) {
+	st := status.Convert(err)
+	code := httpStatusFromCode(st.Code())
+	if code == http.StatusBadGateway {
+		log.Printf("%s RPC failed: %v", rpcName, err)
+		ctx.JSON(code, gin.H{"error": "Backend service unavailable"})
+		return
+	}
+	if delay, ok := retryAfter(st); ok {
+		setRetryAfter(ctx, delay)
+	}
+	ctx.JSON(code, gin.H{"error": st.Message()})
+}
+
+// httpStatusFromCode 业务错误对应的 HTTP 状态码，其余错误统一为 502
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusBadGateway
+	}
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// TestWriteRPCError 测试 gRPC 错误码到 HTTP 状态码的映射
+func TestWriteRPCError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limited, _ := status.New(codes.ResourceExhausted, "请求过于频繁").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(3 * time.Second)})
+	tests := []struct {
+		name           string
+		err            error
+		wantStatus     int
+		wantRetryAfter string
+	}{
+		{name: "参数错误", err: status.Error(codes.InvalidArgument, "bad"), wantStatus: http.StatusBadRequest},
+		{name: "未认证", err: status.Error(codes.Unauthenticated, "missing credentials"), wantStatus: http.StatusUnauthorized},
+		{name: "无权限", err: status.Error(codes.PermissionDenied, "denied"), wantStatus: http.StatusForbidden},
+		{name: "不存在", err: status.Error(codes.NotFound, "missing"), wantStatus: http.StatusNotFound},
+		{name: "冲突", err: status.Error(codes.AlreadyExists, "taken"), wantStatus: http.StatusConflict},
+		{name: "限流", err: limited.Err(), wantStatus: http.StatusTooManyRequests, wantRetryAfter: "3"},
+		{name: "后端错误", err: status.Error(codes.Internal, "boom"), wantStatus: http.StatusBadGateway},
+		{name: "后端不可用", err: status.Error(codes.Unavailable, "down"), wantStatus: http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			writeRPCError(ctx, "Shortener", tt.err)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+		})
+	}
+}
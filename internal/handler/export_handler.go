@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	shortenerpb "github.com/username/shorturl/internal/rpc/proto"
+)
+
+// exportFlushEvery 每写出多少条刷新一次响应缓冲
+const exportFlushEvery = 100
+
+var csvHeader = []string{"short_key", "short_url", "long_url", "redirect_code", "created_at", "expires_at"}
+
+// HandleExportShortLinks 通过 StreamShortLinks 流式导出短链接
+// 查询参数：format=ndjson|csv（默认 ndjson），其余过滤与排序参数同 /all
+func (rh *RouterHandlers) HandleExportShortLinks(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", "ndjson")
+	if format != "ndjson" && format != "csv" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unsupported format: %s", format)})
+		return
+	}
+	listReq, err := listRequestFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 客户端断开时 Request.Context 被取消，gRPC 流随之结束
+	stream, err := rh.Shortener.StreamShortLinks(ctx.Request.Context(), &shortenerpb.StreamShortLinksRequest{
+		CreatedAfter:  listReq.GetCreatedAfter(),
+		CreatedBefore: listReq.GetCreatedBefore(),
+		Status:        listReq.GetStatus(),
+		HostContains:  listReq.GetHostContains(),
+		Sort:          listReq.GetSort(),
+		AllUsers:      listReq.GetAllUsers(),
+	})
+	if err != nil {
+		writeRPCError(ctx, "Shortener", err)
+		return
+	}
+
+	// 先取第一条：参数错误等在发送响应头之前返回，仍可以给出正确的状态码
+	first, err := stream.Recv()
+	if err != nil && !errors.Is(err, io.EOF) {
+		writeRPCError(ctx, "Shortener", err)
+		return
+	}
+
+	filename := "short_links_" + time.Now().UTC().Format("20060102T150405Z") + "." + format
+	ctx.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	var writeLink func(l *shortenerpb.ShortLink) error
+	var flush func() error
+	switch format {
+	case "csv":
+		ctx.Header("Content-Type", "text/csv; charset=utf-8")
+		w := csv.NewWriter(ctx.Writer)
+		_ = w.Write(csvHeader)
+		writeLink = func(l *shortenerpb.ShortLink) error { return w.Write(shortLinkCSV(l)) }
+		flush = func() error {
+			w.Flush()
+			return w.Error()
+		}
+	default:
+		ctx.Header("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(ctx.Writer)
+		writeLink = func(l *shortenerpb.ShortLink) error { return enc.Encode(shortLinkJSON(l)) }
+		flush = func() error { return nil }
+	}
+	ctx.Status(http.StatusOK)
+
+	// 响应头已发出，之后的错误只能中断输出并记录日志
+	count := 0
+	for link := first; link != nil; {
+		if err := writeLink(link); err != nil {
+			log.Printf("export write failed after %d links: %v", count, err)
+			return
+		}
+		count++
+		if count%exportFlushEvery == 0 {
+			if err := flush(); err != nil {
+				log.Printf("export write failed after %d links: %v", count, err)
+				return
+			}
+			ctx.Writer.Flush()
+		}
+
+		link, err = stream.Recv()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Printf("Shortener RPC failed during export after %d links: %v", count, err)
+			}
+			break
+		}
+	}
+	if err := flush(); err == nil {
+		ctx.Writer.Flush()
+	}
+}
+
+// shortLinkCSV 与 csvHeader 顺序一致的一行数据
+func shortLinkCSV(l *shortenerpb.ShortLink) []string {
+	var expiresAt string
+	if l.GetExpiresAt() != 0 {
+		expiresAt = time.Unix(l.GetExpiresAt(), 0).UTC().Format(time.RFC3339)
+	}
+	return []string{
+		l.GetShortLink(),
+		l.GetShortUrl(),
+		l.GetLongLink(),
+		strconv.Itoa(int(l.GetRedirectCode())),
+		time.Unix(l.GetCreatedAt(), 0).UTC().Format(time.RFC3339),
+		expiresAt,
+	}
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	shortenerpb "github.com/username/shorturl/internal/rpc/proto"
+	"google.golang.org/grpc"
+)
+
+// fakeShortener 只实现 StreamShortLinks，依次返回 links
+type fakeShortener struct {
+	shortenerpb.ShortenerServiceClient
+	links []*shortenerpb.ShortLink
+}
+
+func (f *fakeShortener) StreamShortLinks(ctx context.Context, in *shortenerpb.StreamShortLinksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[shortenerpb.ShortLink], error) {
+	return &fakeLinkStream{links: f.links}, nil
+}
+
+type fakeLinkStream struct {
+	grpc.ClientStream
+	links []*shortenerpb.ShortLink
+}
+
+func (s *fakeLinkStream) Recv() (*shortenerpb.ShortLink, error) {
+	if len(s.links) == 0 {
+		return nil, io.EOF
+	}
+	link := s.links[0]
+	s.links = s.links[1:]
+	return link, nil
+}
+
+// TestHandleExportShortLinks 测试 NDJSON 与 CSV 的输出格式
+func TestHandleExportShortLinks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	links := []*shortenerpb.ShortLink{
+		{ShortLink: "abc", ShortUrl: "https://go.example.com/abc", LongLink: "https://example.com/a", RedirectCode: 302, CreatedAt: 1700000000},
+		{ShortLink: "xyz", ShortUrl: "https://go.example.com/xyz", LongLink: "https://example.com/x,y", RedirectCode: 301, CreatedAt: 1700000000, ExpiresAt: 1800000000, OwnerId: 7},
+	}
+	tests := []struct {
+		name            string
+		query           string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "默认 NDJSON",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantBody: `{"created_at":"2023-11-14T22:13:20Z","long_url":"https://example.com/a","redirect_code":302,"short_key":"abc","short_url":"https://go.example.com/abc"}
+{"created_at":"2023-11-14T22:13:20Z","expires_at":"2027-01-15T08:00:00Z","long_url":"https://example.com/x,y","owner_id":7,"redirect_code":301,"short_key":"xyz","short_url":"https://go.example.com/xyz"}
+`,
+		},
+		{
+			name:            "CSV",
+			query:           "?format=csv",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody: `short_key,short_url,long_url,redirect_code,created_at,expires_at
+abc,https://go.example.com/abc,https://example.com/a,302,2023-11-14T22:13:20Z,
+xyz,https://go.example.com/xyz,"https://example.com/x,y",301,2023-11-14T22:13:20Z,2027-01-15T08:00:00Z
+`,
+		},
+		{name: "不支持的格式", query: "?format=xml", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			rh := &RouterHandlers{Shortener: &fakeShortener{links: links}}
+			router.GET("/export", rh.HandleExportShortLinks)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export"+tt.query, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %s, want %s", got, tt.wantContentType)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
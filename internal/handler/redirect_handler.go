@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	shortenerpb "github.com/username/shorturl/internal/rpc/proto"
+	"github.com/username/shorturl/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RegisterRedirectRoutes 注册根路径下的短链跳转路由
+func (rh *RouterHandlers) RegisterRedirectRoutes(router gin.IRoutes) {
+	router.GET("/:code", rh.HandleRedirect)
+	router.HEAD("/:code", rh.HandleRedirect)
+}
+
+// HandleRedirect 将 /{short_code} 跳转到原始长链接，自定义域名上的请求查找所属工作区的短码
+// 短码不存在返回 404 页面，已过期返回 410 页面，工作区本月点击数超出配额返回 429 页面
+func (rh *RouterHandlers) HandleRedirect(ctx *gin.Context) {
+	code := ctx.Param("code")
+	if !utils.IsValidShortCode(code) {
+		renderStatusPage(ctx, http.StatusNotFound, "短链接不存在")
+		return
+	}
+
+	resp, err := rh.Shortener.GetLongURL(ctx, &shortenerpb.GetLongURLRequest{
+		ShortKey: code,
+		Visitor:  visitorFromRequest(ctx),
+		Host:     ctx.Request.Host,
+	})
+	if st := status.Convert(err); st.Code() == codes.ResourceExhausted {
+		if delay, ok := retryAfter(st); ok {
+			setRetryAfter(ctx, delay)
+			renderStatusPage(ctx, http.StatusTooManyRequests, "访问过于频繁，请稍后再试")
+			return
+		}
+		renderStatusPage(ctx, http.StatusTooManyRequests, "短链接本月的访问量已达上限")
+		return
+	}
+	if err != nil {
+		log.Printf("Shortener RPC failed: %v", err)
+		renderStatusPage(ctx, http.StatusBadGateway, "服务暂时不可用，请稍后再试")
+		return
+	}
+
+	if !resp.GetIsFound() {
+		renderStatusPage(ctx, http.StatusNotFound, "短链接不存在")
+		return
+	}
+	if resp.GetIsExpired() {
+		renderStatusPage(ctx, http.StatusGone, "短链接已过期")
+		return
+	}
+
+	redirectCode := int(resp.GetRedirectCode())
+	if redirectCode == 0 {
+		redirectCode = http.StatusFound
+	}
+	// 跳转结果不应被中间缓存，便于后续修改目标地址
+	ctx.Header("Cache-Control", "private, max-age=0")
+	ctx.Redirect(redirectCode, utils.NormalizeURL(resp.GetLongUrl()))
+}
+
+// visitorFromRequest 提取用于点击统计的访问者信息
+func visitorFromRequest(ctx *gin.Context) *shortenerpb.Visitor {
+	country := ctx.GetHeader("CF-IPCountry")
+	if country == "" {
+		country = ctx.GetHeader("X-Country-Code")
+	}
+	return &shortenerpb.Visitor{
+		Referrer:  ctx.Request.Referer(),
+		UserAgent: ctx.Request.UserAgent(),
+		Ip:        ctx.ClientIP(),
+		Country:   country,
+	}
+}
+
+// renderStatusPage 输出简单的 HTML 错误页面
+func renderStatusPage(ctx *gin.Context, code int, message string) {
+	page := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>%d %s</title></head>
+<body><h1>%d %s</h1><p>%s</p></body>
+</html>
+`, code, http.StatusText(code), code, http.StatusText(code), html.EscapeString(message))
+	ctx.Data(code, "text/html; charset=utf-8", []byte(page))
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	shortenerpb "github.com/username/shorturl/internal/rpc/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// redirectShortener 只实现 GetLongURL，按短码返回 responses 或 errs 中的结果，并记录收到的请求
+type redirectShortener struct {
+	shortenerpb.ShortenerServiceClient
+	responses map[string]*shortenerpb.GetLongURLResponse
+	errs      map[string]error
+	requests  []*shortenerpb.GetLongURLRequest
+}
+
+func (f *redirectShortener) GetLongURL(ctx context.Context, in *shortenerpb.GetLongURLRequest, opts ...grpc.CallOption) (*shortenerpb.GetLongURLResponse, error) {
+	f.requests = append(f.requests, in)
+	if err, ok := f.errs[in.GetShortKey()]; ok {
+		return nil, err
+	}
+	if resp, ok := f.responses[in.GetShortKey()]; ok {
+		return resp, nil
+	}
+	return &shortenerpb.GetLongURLResponse{IsFound: false}, nil
+}
+
+// TestHandleRedirect 测试按短链接的状态码跳转，以及不存在、已过期和超出配额时的错误页面
+func TestHandleRedirect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	throttled, err := status.New(codes.ResourceExhausted, "rate limited").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)})
+	if err != nil {
+		t.Fatalf("WithDetails() error = %v", err)
+	}
+	found := func(code int32) *shortenerpb.GetLongURLResponse {
+		return &shortenerpb.GetLongURLResponse{IsFound: true, LongUrl: "https://example.com/target", RedirectCode: code}
+	}
+	shortener := &redirectShortener{
+		responses: map[string]*shortenerpb.GetLongURLResponse{
+			"moved301": found(http.StatusMovedPermanently),
+			"found302": found(http.StatusFound),
+			"temp0307": found(http.StatusTemporaryRedirect),
+			"perm0308": found(http.StatusPermanentRedirect),
+			"legacy00": found(0),
+			"expired0": {IsFound: true, IsExpired: true},
+		},
+		errs: map[string]error{
+			"quota000": status.Error(codes.ResourceExhausted, "工作区本月的点击数已达上限"),
+			"throttle": throttled.Err(),
+			"broken00": status.Error(codes.Unavailable, "connection refused"),
+		},
+	}
+	router := gin.New()
+	(&RouterHandlers{Shortener: shortener}).RegisterRedirectRoutes(router)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		wantStatus     int
+		wantLocation   string
+		wantRetryAfter string
+		wantBody       string
+	}{
+		{name: "301", method: http.MethodGet, path: "/moved301", wantStatus: http.StatusMovedPermanently, wantLocation: "https://example.com/target"},
+		{name: "302", method: http.MethodGet, path: "/found302", wantStatus: http.StatusFound, wantLocation: "https://example.com/target"},
+		{name: "307", method: http.MethodGet, path: "/temp0307", wantStatus: http.StatusTemporaryRedirect, wantLocation: "https://example.com/target"},
+		{name: "308", method: http.MethodGet, path: "/perm0308", wantStatus: http.StatusPermanentRedirect, wantLocation: "https://example.com/target"},
+		{name: "未记录状态码时为 302", method: http.MethodGet, path: "/legacy00", wantStatus: http.StatusFound, wantLocation: "https://example.com/target"},
+		{name: "HEAD 跳转", method: http.MethodHead, path: "/moved301", wantStatus: http.StatusMovedPermanently, wantLocation: "https://example.com/target"},
+		{name: "HEAD 不存在", method: http.MethodHead, path: "/missing0", wantStatus: http.StatusNotFound},
+		{name: "不存在", method: http.MethodGet, path: "/missing0", wantStatus: http.StatusNotFound, wantBody: "短链接不存在"},
+		{name: "不合法的短码", method: http.MethodGet, path: "/a", wantStatus: http.StatusNotFound, wantBody: "短链接不存在"},
+		{name: "已过期", method: http.MethodGet, path: "/expired0", wantStatus: http.StatusGone, wantBody: "短链接已过期"},
+		{name: "超出点击配额", method: http.MethodGet, path: "/quota000", wantStatus: http.StatusTooManyRequests, wantBody: "本月的访问量已达上限"},
+		{name: "被限流", method: http.MethodGet, path: "/throttle", wantStatus: http.StatusTooManyRequests, wantRetryAfter: "2", wantBody: "访问过于频繁"},
+		{name: "后端不可用", method: http.MethodGet, path: "/broken00", wantStatus: http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("Location = %q, want %q", got, tt.wantLocation)
+			}
+			if tt.wantLocation != "" && w.Header().Get("Cache-Control") != "private, max-age=0" {
+				t.Errorf("Cache-Control = %q, want private, max-age=0", w.Header().Get("Cache-Control"))
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+			if tt.wantBody != "" {
+				if ct := w.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+					t.Errorf("Content-Type = %q, want text/html", ct)
+				}
+				if !strings.Contains(w.Body.String(), tt.wantBody) {
+					t.Errorf("body = %q, want it to contain %q", w.Body.String(), tt.wantBody)
+				}
+			}
+		})
+	}
+
+	// 不合法的短码不调用后端，其他请求把 Host 传给后端用于选择工作区
+	for _, req := range shortener.requests {
+		if req.GetShortKey() == "a" {
+			t.Error("GetLongURL() called for an invalid short code")
+		}
+		if req.GetHost() != "example.com" || req.GetVisitor() == nil {
+			t.Errorf("GetLongURL(%s) host = %q, visitor = %v, want example.com and a visitor", req.GetShortKey(), req.GetHost(), req.GetVisitor())
+		}
+	}
+}
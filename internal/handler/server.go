@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/username/shorturl/internal/auth"
 	"github.com/username/shorturl/internal/config"
 	"github.com/username/shorturl/internal/manager"
 	shortenerpb "github.com/username/shorturl/internal/rpc/proto"
@@ -62,13 +63,42 @@ func NewRouter(clientManager *manager.ClientManager) *gin.Engine {
 		log.Fatalf("无法创建ShortenerServiceClient,%v", err)
 	}
 
+	ClipboarderServiceClient, err := clientManager.GetClient(config.GetConfig().GRPCServers.Clipboarder.Addr)
+	if err != nil {
+		log.Fatalf("获取链接失败,%v", err)
+	}
+	clientClipboard, ok := ClipboarderServiceClient.(shortenerpb.ClipboarderServiceClient)
+	if !ok {
+		log.Fatalf("无法创建ClipboarderServiceClient,%v", err)
+	}
+
 	// 1. 初始化 RouterHandlers（注入依赖，只做一次）
 	rh := &RouterHandlers{
 		Shortener: clientShort,
-		// Clipboard: clientManager,
+		Clipboard: clientClipboard,
+	}
+
+	authenticator, err := auth.FromConfig(config.GetConfig())
+	if err != nil {
+		log.Fatalf("初始化认证失败,%v", err)
+	}
+	if authenticator == nil {
+		log.Println("WARNING: authentication is disabled, all API routes are public")
+	}
+
+	limiter, err := newRateLimiter(config.GetConfig())
+	if err != nil {
+		log.Fatalf("初始化限流失败,%v", err)
 	}
 
 	router := gin.New()
+	if proxies := config.GetConfig().TrustedProxies; len(proxies) > 0 {
+		if err := router.SetTrustedProxies(proxies); err != nil {
+			log.Fatalf("TrustedProxies 配置错误,%v", err)
+		}
+	}
+	// handler 直接把 *gin.Context 作为 gRPC 调用的 context，需要能取到请求 context 中的认证身份
+	router.ContextWithFallback = true
 	router.Use(gin.Logger(), gin.Recovery())
 
 	router.GET("/", func(ctx *gin.Context) {
@@ -77,14 +107,17 @@ func NewRouter(clientManager *manager.ClientManager) *gin.Engine {
 
 	// 2. 按功能资源创建路由分组
 	// --- Shortener 路由 ---
-	shortenerGroup := router.Group("/shortener/v1")
+	shortenerGroup := router.Group("/shortener/v1", authRequired(authenticator), workspaceSelector(), rateLimited(limiter))
 	// 调用外部文件中的注册函数
 	rh.RegisterShortenerRoutes(shortenerGroup)
 
+	// --- 短链跳转路由（根路径 /:code），不需要认证 ---
+	rh.RegisterRedirectRoutes(router.Group("/", rateLimited(limiter)))
+
 	// --- Clipboard 路由 ---
-	// clipboardGroup := router.Group("/clipboard/v1")
+	clipboardGroup := router.Group("/clipboard/v1", authRequired(authenticator), rateLimited(limiter))
 	// 调用外部文件中的注册函数
-	// rh.RegisterClipboardRoutes(clipboardGroup)
+	rh.RegisterClipboardRoutes(clipboardGroup)
 
 	return router
 }
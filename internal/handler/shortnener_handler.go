@@ -1,76 +1,353 @@
 package handler
 
 import (
+	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	shortenerpb "github.com/username/shorturl/internal/rpc/proto"
+	"google.golang.org/grpc/codes"
 )
 
 func (rh *RouterHandlers) RegisterShortenerRoutes(group *gin.RouterGroup) {
 	group.POST("/c", rh.HandleCreateShortLink)
+	group.POST("/batch", rh.HandleBatchCreateShortLinks)
 	group.GET("/:key", rh.HandleGetLongURL)
 	group.GET("/all", rh.HandleGetAllShortLink)
+	group.GET("/export", rh.HandleExportShortLinks)
+	group.GET("/stats/:key", rh.HandleGetLinkStats)
+	group.PATCH("/:key", rh.HandleUpdateShortLink)
+	group.DELETE("/:key", rh.HandleDeleteShortLink)
+}
+
+// createShortLinkBody 创建短链接的请求体，批量创建时每一条使用相同的结构
+type createShortLinkBody struct {
+	LongURL      string `json:"long_url" binding:"required"`
+	RedirectCode int32  `json:"redirect_code"`
+	CustomAlias  string `json:"custom_alias"`
+	// ExpiresIn 有效期（秒）
+	ExpiresIn int64 `json:"expires_in"`
+	// ExpiresAt 绝对过期时间（RFC3339）
+	ExpiresAt    *time.Time `json:"expires_at"`
+	NeverExpires bool       `json:"never_expires"`
+}
+
+func (b *createShortLinkBody) toRPC() *shortenerpb.CreateShortLinkRequest {
+	rpcReq := &shortenerpb.CreateShortLinkRequest{
+		LongUrl:      b.LongURL,
+		RedirectCode: b.RedirectCode,
+		CustomAlias:  b.CustomAlias,
+		ExpiresIn:    b.ExpiresIn,
+		NeverExpires: b.NeverExpires,
+	}
+	if b.ExpiresAt != nil {
+		rpcReq.ExpiresAt = b.ExpiresAt.Unix()
+	}
+	return rpcReq
 }
 
 // HandleCreateShortLink 是 Shortener 资源的 HTTP Handler
 func (rh *RouterHandlers) HandleCreateShortLink(ctx *gin.Context) {
-	var reqBody struct {
-		LongURL string `json:"long_url" binding:"required"`
-	}
+	var reqBody createShortLinkBody
 	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
+	rpcReq := reqBody.toRPC()
 
 	// 调用 gRPC 客户端封装层（核心：转发请求）
-	resp, err := rh.Shortener.CreateShortLink(ctx, &shortenerpb.CreateShortLinkRequest{
-		LongUrl: reqBody.LongURL,
-	})
+	resp, err := rh.Shortener.CreateShortLink(ctx, rpcReq)
 
 	if err != nil {
-		log.Printf("Shortener RPC failed: %v", err)
-		ctx.JSON(http.StatusBadGateway, gin.H{"error": "Backend service unavailable"})
+		writeRPCError(ctx, "Shortener", err)
 		return
 	}
 
 	// 格式化并返回 HTTP 响应
-	ctx.JSON(http.StatusOK, gin.H{"short_url": resp.ShortKey})
+	body := gin.H{"short_key": resp.GetShortKey(), "short_url": resp.GetShortUrl()}
+	if resp.GetExpiresAt() != 0 {
+		body["expires_at"] = time.Unix(resp.GetExpiresAt(), 0).UTC().Format(time.RFC3339)
+	}
+	ctx.JSON(http.StatusOK, body)
 }
 
-func (rh *RouterHandlers) HandleGetLongURL(ctx *gin.Context) {
+// HandleBatchCreateShortLinks 批量创建短链接，请求体为 {"items": [...]}，每一条的字段同 /c
+// 单条失败不影响其他条目，响应中按顺序返回每一条的结果
+func (rh *RouterHandlers) HandleBatchCreateShortLinks(ctx *gin.Context) {
 	var reqBody struct {
-		ShortKey string `json:"short_key" binding:"required"`
+		Items []createShortLinkBody `json:"items" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
 
-	// 调用 gRPC 客户端封装层
-	resp, err := rh.Shortener.GetLongURL(ctx, &shortenerpb.GetLongURLRequest{
-		ShortKey: reqBody.ShortKey,
+	rpcReq := &shortenerpb.BatchCreateShortLinksRequest{}
+	for i := range reqBody.Items {
+		rpcReq.Items = append(rpcReq.Items, reqBody.Items[i].toRPC())
+	}
+	resp, err := rh.Shortener.BatchCreateShortLinks(ctx, rpcReq)
+	if err != nil {
+		writeRPCError(ctx, "Shortener", err)
+		return
+	}
+
+	results := make([]gin.H, 0, len(resp.GetResults()))
+	for _, r := range resp.GetResults() {
+		item := gin.H{"index": r.GetIndex()}
+		if r.GetCode() != 0 {
+			code := httpStatusFromCode(codes.Code(r.GetCode()))
+			item["status"] = code
+			item["error"] = r.GetError()
+			if code == http.StatusBadGateway {
+				log.Printf("Shortener batch item %d failed: %s", r.GetIndex(), r.GetError())
+				item["error"] = "Backend service unavailable"
+			}
+		} else {
+			item["short_key"] = r.GetShortKey()
+			item["short_url"] = r.GetShortUrl()
+			if r.GetExpiresAt() != 0 {
+				item["expires_at"] = time.Unix(r.GetExpiresAt(), 0).UTC().Format(time.RFC3339)
+			}
+		}
+		results = append(results, item)
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"results":   results,
+		"succeeded": resp.GetSucceeded(),
+		"failed":    resp.GetFailed(),
 	})
+}
 
+// HandleGetLongURL 查询短码对应的原始长链接，短码不存在返回 404，已过期返回 410
+func (rh *RouterHandlers) HandleGetLongURL(ctx *gin.Context) {
+	resp, err := rh.Shortener.GetLongURL(ctx, &shortenerpb.GetLongURLRequest{
+		ShortKey: ctx.Param("key"),
+	})
 	if err != nil {
-		// ... 错误处理
+		writeRPCError(ctx, "Shortener", err)
+		return
+	}
+	if !resp.GetIsFound() {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Short link not found"})
+		return
+	}
+	if resp.GetIsExpired() {
+		ctx.JSON(http.StatusGone, gin.H{"error": "Short link has expired"})
 		return
 	}
 
-	// ... 返回响应
 	ctx.JSON(http.StatusOK, gin.H{"long_url": resp.GetLongUrl()})
 }
 
+// HandleGetAllShortLink 分页查询短链接
+// 查询参数：page_size、page_token，created_after/created_before 为 RFC3339 时间，
+// status=all|active|expired，host 为目标域名包含的子串，sort=created_desc|created_asc|short_code_asc|short_code_desc，
+// all_users=true 返回所有用户的短链接（仅管理员）
 func (rh *RouterHandlers) HandleGetAllShortLink(ctx *gin.Context) {
+	rpcReq, err := listRequestFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// 调用 gRPC 客户端封装层
-	resp, err := rh.Shortener.GetAllShortLink(ctx, &shortenerpb.GetAllShortLinkRequest{})
-
+	resp, err := rh.Shortener.GetAllShortLink(ctx, rpcReq)
 	if err != nil {
 		log.Printf("调用获取全部链接错误,%v", err)
+		writeRPCError(ctx, "Shortener", err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"short_links": resp.ShortLinks})
+	links := make([]gin.H, 0, len(resp.GetShortLinks()))
+	for _, l := range resp.GetShortLinks() {
+		links = append(links, shortLinkJSON(l))
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"short_links":     links,
+		"next_page_token": resp.GetNextPageToken(),
+	})
+}
+
+var linkStatuses = map[string]shortenerpb.LinkStatus{
+	"":        shortenerpb.LinkStatus_LINK_STATUS_ALL,
+	"all":     shortenerpb.LinkStatus_LINK_STATUS_ALL,
+	"active":  shortenerpb.LinkStatus_LINK_STATUS_ACTIVE,
+	"expired": shortenerpb.LinkStatus_LINK_STATUS_EXPIRED,
+}
+
+var linkSorts = map[string]shortenerpb.LinkSort{
+	"":                shortenerpb.LinkSort_LINK_SORT_CREATED_DESC,
+	"created_desc":    shortenerpb.LinkSort_LINK_SORT_CREATED_DESC,
+	"created_asc":     shortenerpb.LinkSort_LINK_SORT_CREATED_ASC,
+	"short_code_asc":  shortenerpb.LinkSort_LINK_SORT_SHORT_CODE_ASC,
+	"short_code_desc": shortenerpb.LinkSort_LINK_SORT_SHORT_CODE_DESC,
+}
+
+// listRequestFromQuery 将列表查询参数转换为 gRPC 请求
+func listRequestFromQuery(ctx *gin.Context) (*shortenerpb.GetAllShortLinkRequest, error) {
+	var query struct {
+		PageSize      int32      `form:"page_size"`
+		PageToken     string     `form:"page_token"`
+		CreatedAfter  *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+		CreatedBefore *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+		Status        string     `form:"status"`
+		Host          string     `form:"host"`
+		Sort          string     `form:"sort"`
+		AllUsers      bool       `form:"all_users"`
+	}
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	linkStatus, ok := linkStatuses[query.Status]
+	if !ok {
+		return nil, fmt.Errorf("unsupported status: %s", query.Status)
+	}
+	linkSort, ok := linkSorts[query.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort: %s", query.Sort)
+	}
+
+	req := &shortenerpb.GetAllShortLinkRequest{
+		PageSize:     query.PageSize,
+		PageToken:    query.PageToken,
+		Status:       linkStatus,
+		HostContains: query.Host,
+		Sort:         linkSort,
+		AllUsers:     query.AllUsers,
+	}
+	if query.CreatedAfter != nil {
+		req.CreatedAfter = query.CreatedAfter.Unix()
+	}
+	if query.CreatedBefore != nil {
+		req.CreatedBefore = query.CreatedBefore.Unix()
+	}
+	return req, nil
+}
+
+// shortLinkJSON 列表中单条短链接的 JSON 表示
+func shortLinkJSON(l *shortenerpb.ShortLink) gin.H {
+	item := gin.H{
+		"short_key":     l.GetShortLink(),
+		"short_url":     l.GetShortUrl(),
+		"long_url":      l.GetLongLink(),
+		"redirect_code": l.GetRedirectCode(),
+		"created_at":    time.Unix(l.GetCreatedAt(), 0).UTC().Format(time.RFC3339),
+	}
+	if l.GetExpiresAt() != 0 {
+		item["expires_at"] = time.Unix(l.GetExpiresAt(), 0).UTC().Format(time.RFC3339)
+	}
+	if l.GetOwnerId() != 0 {
+		item["owner_id"] = l.GetOwnerId()
+	}
+	return item
+}
+
+// HandleUpdateShortLink 修改短链接的目标地址、过期时间或重定向状态码，未传的字段保持不变
+func (rh *RouterHandlers) HandleUpdateShortLink(ctx *gin.Context) {
+	var reqBody struct {
+		LongURL      *string    `json:"long_url"`
+		RedirectCode *int32     `json:"redirect_code"`
+		ExpiresIn    *int64     `json:"expires_in"`
+		ExpiresAt    *time.Time `json:"expires_at"`
+		NeverExpires *bool      `json:"never_expires"`
+	}
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	rpcReq := &shortenerpb.UpdateShortLinkRequest{
+		ShortKey:     ctx.Param("key"),
+		LongUrl:      reqBody.LongURL,
+		RedirectCode: reqBody.RedirectCode,
+		ExpiresIn:    reqBody.ExpiresIn,
+		NeverExpires: reqBody.NeverExpires,
+	}
+	if reqBody.ExpiresAt != nil {
+		expiresAt := reqBody.ExpiresAt.Unix()
+		rpcReq.ExpiresAt = &expiresAt
+	}
+
+	resp, err := rh.Shortener.UpdateShortLink(ctx, rpcReq)
+	if err != nil {
+		writeRPCError(ctx, "Shortener", err)
+		return
+	}
+
+	body := gin.H{
+		"short_key":     resp.GetShortKey(),
+		"short_url":     resp.GetShortUrl(),
+		"long_url":      resp.GetLongUrl(),
+		"redirect_code": resp.GetRedirectCode(),
+	}
+	if resp.GetExpiresAt() != 0 {
+		body["expires_at"] = time.Unix(resp.GetExpiresAt(), 0).UTC().Format(time.RFC3339)
+	}
+	ctx.JSON(http.StatusOK, body)
+}
+
+// HandleDeleteShortLink 删除短链接
+func (rh *RouterHandlers) HandleDeleteShortLink(ctx *gin.Context) {
+	_, err := rh.Shortener.DeleteShortLink(ctx, &shortenerpb.DeleteShortLinkRequest{
+		ShortKey: ctx.Param("key"),
+	})
+	if err != nil {
+		writeRPCError(ctx, "Shortener", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// HandleGetLinkStats 查询短链接的点击统计
+// 查询参数：granularity=hour|day，from/to 为 Unix 秒，top 为排行榜条数
+func (rh *RouterHandlers) HandleGetLinkStats(ctx *gin.Context) {
+	var query struct {
+		Granularity string `form:"granularity"`
+		From        int64  `form:"from"`
+		To          int64  `form:"to"`
+		Top         int32  `form:"top"`
+	}
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	resp, err := rh.Shortener.GetLinkStats(ctx, &shortenerpb.GetLinkStatsRequest{
+		ShortKey:    ctx.Param("key"),
+		Granularity: query.Granularity,
+		From:        query.From,
+		To:          query.To,
+		TopN:        query.Top,
+	})
+	if err != nil {
+		writeRPCError(ctx, "Shortener", err)
+		return
+	}
+
+	buckets := make([]gin.H, 0, len(resp.GetBuckets()))
+	for _, b := range resp.GetBuckets() {
+		buckets = append(buckets, gin.H{
+			"start": time.Unix(b.GetStart(), 0).UTC().Format(time.RFC3339),
+			"count": b.GetCount(),
+		})
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"total_clicks":    resp.GetTotalClicks(),
+		"buckets":         buckets,
+		"top_referrers":   countEntries(resp.GetTopReferrers()),
+		"top_user_agents": countEntries(resp.GetTopUserAgents()),
+	})
+}
+
+func countEntries(entries []*shortenerpb.CountEntry) []gin.H {
+	result := make([]gin.H, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, gin.H{"value": e.GetValue(), "count": e.GetCount()})
+	}
+	return result
 }
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/shorturl/internal/tenant"
+)
+
+// workspaceSelector 读取 X-Workspace 请求头中的工作区 slug 写入请求的 context，由 gRPC 客户端拦截器转发给后端
+// 未指定时使用默认工作区；工作区是否存在、调用方是否为成员由后端校验
+func workspaceSelector() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if slug := strings.TrimSpace(ctx.GetHeader(tenant.Header)); slug != "" {
+			ctx.Request = ctx.Request.WithContext(tenant.WithWorkspace(ctx.Request.Context(), slug))
+		}
+		ctx.Next()
+	}
+}
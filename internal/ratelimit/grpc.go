@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// UnaryServerInterceptor 按 gRPC 方法限流，需要放在认证拦截器之后；未启用（nil）时直接放行
+// 被拒绝时返回 ResourceExhausted，附带 RetryInfo 与 QuotaFailure，RateLimit-* 写入 header metadata
+// ByTenant 规则由服务在校验工作区之后调用 CheckTenant 计数
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		d, matched := l.Allow(ctx, info.FullMethod, CallerFromContext(ctx, peerIP(ctx)))
+		if matched {
+			_ = grpc.SetHeader(ctx, metadata.Pairs(d.Headers()...))
+			if !d.Allowed {
+				return nil, d.Status().Err()
+			}
+		}
+		return handler(l.withTenantCheck(ctx, info.FullMethod), req)
+	}
+}
+
+// StreamServerInterceptor 同 UnaryServerInterceptor，用于流式调用，每个流只计一次
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		d, matched := l.Allow(ctx, info.FullMethod, CallerFromContext(ctx, peerIP(ctx)))
+		if matched {
+			_ = ss.SetHeader(metadata.Pairs(d.Headers()...))
+			if !d.Allowed {
+				return d.Status().Err()
+			}
+		}
+		return handler(srv, &tenantCheckedStream{ServerStream: ss, ctx: l.withTenantCheck(ctx, info.FullMethod)})
+	}
+}
+
+// tenantCheckedStream 替换 Context，携带 CheckTenant 使用的限流状态
+type tenantCheckedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tenantCheckedStream) Context() context.Context { return s.ctx }
+
+// tenantCheck 一次调用的工作区限流，只在第一次 CheckTenant 时计数
+type tenantCheck struct {
+	limiter *Limiter
+	route   string
+
+	once sync.Once
+	err  error
+}
+
+type tenantCheckKey struct{}
+
+// withTenantCheck 在 ctx 中记录本次调用，供 CheckTenant 使用；没有 ByTenant 规则时不记录
+func (l *Limiter) withTenantCheck(ctx context.Context, route string) context.Context {
+	if l == nil || !slices.ContainsFunc(l.rules, func(r Rule) bool { return r.By == ByTenant }) {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantCheckKey{}, &tenantCheck{limiter: l, route: route})
+}
+
+// CheckTenant 在服务确认调用方可以使用工作区之后按 ByTenant 规则限流，workspace 为工作区 slug，默认工作区为空
+// 同一次调用多次检查时只计数一次；被拒绝时返回 ResourceExhausted，未经限流拦截器的调用直接放行
+func CheckTenant(ctx context.Context, workspace string) error {
+	check, ok := ctx.Value(tenantCheckKey{}).(*tenantCheck)
+	if !ok {
+		return nil
+	}
+	check.once.Do(func() {
+		d, matched := check.limiter.AllowTenant(ctx, check.route, workspace)
+		if !matched {
+			return
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(d.Headers()...))
+		if !d.Allowed {
+			check.err = d.Status().Err()
+		}
+	})
+	return check.err
+}
+
+// Status 被拒绝的请求对应的 gRPC 状态
+func (d Decision) Status() *status.Status {
+	st := status.New(codes.ResourceExhausted, "请求过于频繁，请稍后重试")
+	detailed, err := st.WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(d.RetryAfter)},
+		&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{
+			Subject:     d.Identity,
+			Description: fmt.Sprintf("rate limit %s: %d requests per %v", d.Rule.Name, d.Rule.Limit, d.Rule.Period),
+		}}},
+	)
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// peerIP gRPC 调用方的地址；经过网关的请求为网关的地址，按客户端 IP 限流应配置在网关的 HTTP 路由上
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
@@ -0,0 +1,234 @@
+// Package ratelimit 令牌桶限流：按路由和调用方身份（API key、IP、工作区）配置规则，HTTP 网关与 gRPC 服务共用
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/username/shorturl/internal/auth"
+	"github.com/username/shorturl/internal/cache"
+	"github.com/username/shorturl/internal/config"
+)
+
+// 规则的身份维度
+const (
+	// ByIP 客户端 IP
+	ByIP = "ip"
+	// ByAPIKey 通过 API key 认证的调用方（按 API key 配置的 Subject 计数），其他调用方不受该规则限制
+	ByAPIKey = "api_key"
+	// BySubject 任何认证通过的调用方（API key 或 JWT 的 sub）
+	BySubject = "subject"
+	// ByTenant 工作区，未指定工作区的请求计入默认工作区
+	// 请求头中的工作区未经校验，该维度只在 gRPC 服务确认调用方可以使用工作区之后通过 CheckTenant 计数，Allow 不计数
+	ByTenant = "tenant"
+	// ByGlobal 所有请求共用一个令牌桶
+	ByGlobal = "global"
+)
+
+// keyPrefix 令牌桶在 Redis 中的键前缀
+const keyPrefix = "ratelimit:"
+
+// Rule 一条限流规则：匹配的请求按 By 维度分别计数，每个 Period 补充 Limit 个令牌，桶容量为 Burst
+type Rule struct {
+	Name   string
+	Routes []string
+	By     string
+	Limit  int
+	Period time.Duration
+	Burst  int
+}
+
+// rate 每秒补充的令牌数
+func (r Rule) rate() float64 {
+	return float64(r.Limit) / r.Period.Seconds()
+}
+
+// matches 请求的路由是否匹配规则
+func (r Rule) matches(route string) bool {
+	for _, pattern := range r.Routes {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(route, prefix) {
+				return true
+			}
+		} else if pattern == route {
+			return true
+		}
+	}
+	return false
+}
+
+// Caller 请求的调用方
+type Caller struct {
+	IP string
+	// APIKey 通过 API key 认证时为 key 配置的 Subject，不使用 key 本身
+	APIKey  string
+	Subject string
+}
+
+// CallerFromContext 从 ctx 中的认证身份得到调用方，ip 为客户端地址
+func CallerFromContext(ctx context.Context, ip string) Caller {
+	c := Caller{IP: ip}
+	if id, ok := auth.FromContext(ctx); ok {
+		c.Subject = id.Subject
+		if id.Method == auth.MethodAPIKey {
+			c.APIKey = id.Subject
+		}
+	}
+	return c
+}
+
+// identity 调用方在 by 维度上的标识，没有对应身份时返回 false
+func (c Caller) identity(by string) (string, bool) {
+	var value string
+	switch by {
+	case ByIP:
+		value = c.IP
+	case ByAPIKey:
+		value = c.APIKey
+	case BySubject:
+		value = c.Subject
+	case ByGlobal:
+		return "all", true
+	}
+	return value, value != ""
+}
+
+// Decision 请求的限流结果，匹配多条规则时为拒绝请求或剩余令牌最少的规则
+type Decision struct {
+	Rule Rule
+	// Identity 计数的调用方，如 "api_key:ci-bot"
+	Identity string
+	Result
+}
+
+// Headers 按 IETF RateLimit 响应头草案返回 RateLimit-*，被拒绝时还包括 Retry-After
+// 依次为键和值，HTTP 网关写入响应头，gRPC 服务写入 header metadata
+func (d Decision) Headers() []string {
+	kv := []string{
+		"RateLimit-Limit", strconv.Itoa(d.Rule.Burst),
+		"RateLimit-Remaining", strconv.Itoa(d.Remaining),
+		"RateLimit-Reset", strconv.Itoa(ceilSeconds(d.Reset)),
+		"RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d", d.Rule.Limit, ceilSeconds(d.Rule.Period), d.Rule.Burst),
+	}
+	if !d.Allowed {
+		kv = append(kv, "Retry-After", strconv.Itoa(max(ceilSeconds(d.RetryAfter), 1)))
+	}
+	return kv
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// Limiter 按规则对请求限流
+type Limiter struct {
+	rules []Rule
+	store Store
+}
+
+// New 校验规则并创建 Limiter，Burst 未设置时等于 Limit
+func New(rules []Rule, store Store) (*Limiter, error) {
+	rules = slices.Clone(rules)
+	seen := make(map[string]bool)
+	for i := range rules {
+		r := &rules[i]
+		if r.Name == "" {
+			return nil, fmt.Errorf("rate limit rule %d: missing name", i)
+		}
+		if len(r.Routes) == 0 {
+			return nil, fmt.Errorf("rate limit rule %s: no routes", r.Name)
+		}
+		switch r.By {
+		case ByIP, ByAPIKey, BySubject, ByTenant, ByGlobal:
+		default:
+			return nil, fmt.Errorf("rate limit rule %s: unknown identity %q", r.Name, r.By)
+		}
+		if r.Limit <= 0 || r.Period <= 0 {
+			return nil, fmt.Errorf("rate limit rule %s: Limit and Period must be positive", r.Name)
+		}
+		if r.Burst <= 0 {
+			r.Burst = r.Limit
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("rate limit rule %s: duplicate name", r.Name)
+		}
+		seen[r.Name] = true
+	}
+	return &Limiter{rules: rules, store: store}, nil
+}
+
+// FromConfig 按配置创建 Limiter，未启用或没有规则时返回 nil
+// redisCache 为 *cache.RedisCache 时令牌桶保存在 Redis 中，所有实例共享，Redis 出错时改用本地令牌桶；否则只使用本地令牌桶
+func FromConfig(cfg *config.Config, redisCache cache.Cache) (*Limiter, error) {
+	if !cfg.RateLimit.Enabled || len(cfg.RateLimit.Rules) == 0 {
+		return nil, nil
+	}
+	rules := make([]Rule, len(cfg.RateLimit.Rules))
+	for i, r := range cfg.RateLimit.Rules {
+		rules[i] = Rule{Name: r.Name, Routes: r.Routes, By: r.By, Limit: r.Limit, Period: r.Period, Burst: r.Burst}
+	}
+
+	var store Store = NewMemoryStore()
+	if rc, ok := redisCache.(*cache.RedisCache); ok {
+		store = &fallbackStore{primary: NewRedisStore(rc.Client()), fallback: store}
+	}
+	return New(rules, store)
+}
+
+// Allow 对匹配 route 的每条规则（ByTenant 除外）取一个令牌，任一规则被拒绝时拒绝请求，之后的规则不再扣减
+// 没有规则匹配（或调用方缺少规则要求的身份）时第二个返回值为 false；未启用（nil）时总是放行
+func (l *Limiter) Allow(ctx context.Context, route string, caller Caller) (Decision, bool) {
+	return l.allow(ctx, route, func(by string) (string, bool) {
+		if by == ByTenant {
+			return "", false
+		}
+		return caller.identity(by)
+	})
+}
+
+// AllowTenant 同 Allow，只计数 ByTenant 规则；workspace 为已经校验过的工作区 slug，默认工作区为空
+func (l *Limiter) AllowTenant(ctx context.Context, route string, workspace string) (Decision, bool) {
+	if workspace == "" {
+		workspace = "default"
+	}
+	return l.allow(ctx, route, func(by string) (string, bool) {
+		return workspace, by == ByTenant
+	})
+}
+
+// allow identity 返回调用方在规则维度上的标识，返回 false 的规则不计数
+func (l *Limiter) allow(ctx context.Context, route string, identity func(by string) (string, bool)) (Decision, bool) {
+	if l == nil {
+		return Decision{Result: Result{Allowed: true}}, false
+	}
+	var decision Decision
+	matched := false
+	for _, rule := range l.rules {
+		if !rule.matches(route) {
+			continue
+		}
+		value, ok := identity(rule.By)
+		if !ok {
+			continue
+		}
+		identity := rule.By + ":" + value
+		res, err := l.store.Take(ctx, keyPrefix+rule.Name+":"+identity, rule.rate(), rule.Burst)
+		if err != nil {
+			// 令牌桶不可用时放行，限流不应影响服务可用性
+			continue
+		}
+		d := Decision{Rule: rule, Identity: identity, Result: res}
+		if !res.Allowed {
+			return d, true
+		}
+		if !matched || d.Remaining < decision.Remaining {
+			decision = d
+		}
+		matched = true
+	}
+	return decision, matched
+}
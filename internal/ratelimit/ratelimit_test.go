@@ -0,0 +1,228 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestMemoryStoreTokenBucket 测试令牌桶的容量、补充速度和等待时长
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// 容量 3，每秒补充 1 个
+	for want := 2; want >= 0; want-- {
+		res, _ := store.Take(ctx, "k", 1, 3)
+		if !res.Allowed || res.Remaining != want {
+			t.Fatalf("Take() = %+v, want allowed with %d remaining", res, want)
+		}
+	}
+	res, _ := store.Take(ctx, "k", 1, 3)
+	if res.Allowed || res.RetryAfter != time.Second || res.Reset != 3*time.Second {
+		t.Fatalf("Take() on empty bucket = %+v, want denied, retry after 1s, reset 3s", res)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if res, _ := store.Take(ctx, "k", 1, 3); res.Allowed || res.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("Take() after 500ms = %+v, want denied, retry after 500ms", res)
+	}
+	now = now.Add(500 * time.Millisecond)
+	if res, _ := store.Take(ctx, "k", 1, 3); !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("Take() after 1s = %+v, want allowed", res)
+	}
+
+	// 其他 key 的令牌桶互不影响；补满的令牌桶在清理时删除
+	if res, _ := store.Take(ctx, "other", 1, 3); !res.Allowed || res.Remaining != 2 {
+		t.Fatalf("Take() other key = %+v, want allowed with 2 remaining", res)
+	}
+	now = now.Add(time.Hour)
+	_, _ = store.Take(ctx, "k", 1, 3)
+	if _, ok := store.buckets["other"]; ok {
+		t.Error("full bucket was not swept")
+	}
+}
+
+// TestLimiterAllow 测试规则按路由和身份匹配，任一规则耗尽即拒绝
+func TestLimiterAllow(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Unix(1700000000, 0)
+	store.now = func() time.Time { return now }
+	limiter, err := New([]Rule{
+		{Name: "create-per-key", Routes: []string{"POST /shortener/v1/*"}, By: ByAPIKey, Limit: 2, Period: time.Minute},
+		{Name: "per-ip", Routes: []string{"*"}, By: ByIP, Limit: 100, Period: time.Minute},
+	}, store)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx := context.Background()
+	bot := Caller{IP: "10.0.0.1", APIKey: "ci-bot", Subject: "ci-bot"}
+
+	for i := 0; i < 2; i++ {
+		d, matched := limiter.Allow(ctx, "POST /shortener/v1/c", bot)
+		if !matched || !d.Allowed || d.Rule.Name != "create-per-key" {
+			t.Fatalf("Allow() #%d = %+v, %v, want allowed by create-per-key", i, d, matched)
+		}
+	}
+	d, _ := limiter.Allow(ctx, "POST /shortener/v1/c", bot)
+	if d.Allowed || d.Identity != "api_key:ci-bot" || d.RetryAfter != 30*time.Second {
+		t.Fatalf("Allow() = %+v, want denied for api_key:ci-bot, retry after 30s", d)
+	}
+	if kv := d.Headers(); !slices.Contains(kv, "Retry-After") || !slices.Contains(kv, "2;w=60;burst=2") {
+		t.Errorf("Headers() = %v, want Retry-After and policy 2;w=60;burst=2", kv)
+	}
+	st := d.Status()
+	if st.Code() != codes.ResourceExhausted || len(st.Details()) != 2 {
+		t.Errorf("Status() = %v with %d details, want ResourceExhausted with RetryInfo and QuotaFailure", st.Code(), len(st.Details()))
+	} else if info, ok := st.Details()[0].(*errdetails.RetryInfo); !ok || info.GetRetryDelay().AsDuration() != 30*time.Second {
+		t.Errorf("Status() details[0] = %v, want RetryInfo of 30s", st.Details()[0])
+	}
+
+	// 其他 API key、未通过 API key 认证的调用方和其他路由不受 create-per-key 限制
+	if d, _ := limiter.Allow(ctx, "POST /shortener/v1/c", Caller{IP: "10.0.0.1", APIKey: "other"}); !d.Allowed {
+		t.Errorf("Allow() other key = %+v, want allowed", d)
+	}
+	if d, _ := limiter.Allow(ctx, "POST /shortener/v1/c", Caller{IP: "10.0.0.1", Subject: "alice"}); !d.Allowed || d.Rule.Name != "per-ip" {
+		t.Errorf("Allow() jwt caller = %+v, want allowed by per-ip", d)
+	}
+	if d, _ := limiter.Allow(ctx, "GET /shortener/v1/all", bot); !d.Allowed {
+		t.Errorf("Allow() other route = %+v, want allowed", d)
+	}
+	if _, matched := limiter.Allow(ctx, "GET /:code", Caller{}); matched {
+		t.Error("Allow() without identity matched, want no rule applied")
+	}
+
+	var disabled *Limiter
+	if d, matched := disabled.Allow(ctx, "POST /shortener/v1/c", bot); !d.Allowed || matched {
+		t.Errorf("nil Limiter Allow() = %+v, %v, want allowed and unmatched", d, matched)
+	}
+}
+
+// TestNewValidatesRules 测试规则校验
+func TestNewValidatesRules(t *testing.T) {
+	valid := Rule{Name: "r", Routes: []string{"*"}, By: ByTenant, Limit: 10, Period: time.Second}
+	tests := []struct {
+		name    string
+		rules   []Rule
+		wantErr bool
+	}{
+		{name: "合法规则", rules: []Rule{valid}},
+		{name: "缺少名称", rules: []Rule{{Routes: valid.Routes, By: valid.By, Limit: 10, Period: time.Second}}, wantErr: true},
+		{name: "没有路由", rules: []Rule{{Name: "r", By: valid.By, Limit: 10, Period: time.Second}}, wantErr: true},
+		{name: "未知身份维度", rules: []Rule{{Name: "r", Routes: valid.Routes, By: "cookie", Limit: 10, Period: time.Second}}, wantErr: true},
+		{name: "Limit 为 0", rules: []Rule{{Name: "r", Routes: valid.Routes, By: valid.By, Period: time.Second}}, wantErr: true},
+		{name: "名称重复", rules: []Rule{valid, valid}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := New(tt.rules, NewMemoryStore())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && limiter.rules[0].Burst != limiter.rules[0].Limit {
+				t.Errorf("Burst = %d, want default to Limit", limiter.rules[0].Burst)
+			}
+		})
+	}
+}
+
+// failingStore 总是返回错误的 Store
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, rate float64, burst int) (Result, error) {
+	return Result{}, errors.New("connection refused")
+}
+
+// TestFallbackStore 测试 Redis 出错时改用本地令牌桶继续限流
+func TestFallbackStore(t *testing.T) {
+	store := &fallbackStore{primary: failingStore{}, fallback: NewMemoryStore()}
+	ctx := context.Background()
+	if res, err := store.Take(ctx, "k", 1, 1); err != nil || !res.Allowed {
+		t.Fatalf("Take() = %+v, %v, want allowed", res, err)
+	}
+	if res, err := store.Take(ctx, "k", 1, 1); err != nil || res.Allowed {
+		t.Fatalf("Take() = %+v, %v, want denied by local bucket", res, err)
+	}
+}
+
+// TestUnaryServerInterceptor 测试 gRPC 拦截器按方法限流并返回 ResourceExhausted
+func TestUnaryServerInterceptor(t *testing.T) {
+	limiter, err := New([]Rule{{Name: "create", Routes: []string{"/shortener.ShortenerService/CreateShortLink"}, By: ByGlobal, Limit: 1, Period: time.Hour}}, NewMemoryStore())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	interceptor := limiter.UnaryServerInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	call := func(method string) error {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	if err := call("/shortener.ShortenerService/CreateShortLink"); err != nil {
+		t.Fatalf("first call error = %v", err)
+	}
+	if err := call("/shortener.ShortenerService/CreateShortLink"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second call error = %v, want ResourceExhausted", err)
+	}
+	if err := call("/shortener.ShortenerService/GetLongURL"); err != nil {
+		t.Errorf("unlimited method error = %v", err)
+	}
+}
+
+// TestCheckTenant 测试工作区规则只在服务调用 CheckTenant 时按传入的工作区计数，同一次调用只计数一次
+func TestCheckTenant(t *testing.T) {
+	limiter, err := New([]Rule{{Name: "per-tenant", Routes: []string{"/shortener.ShortenerService/*"}, By: ByTenant, Limit: 1, Period: time.Hour}}, NewMemoryStore())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	interceptor := limiter.UnaryServerInterceptor()
+	// call 模拟服务：workspaces 为 nil 时不调用 CheckTenant（例如工作区校验失败）
+	call := func(workspaces ...string) error {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/shortener.ShortenerService/CreateShortLink"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				for _, ws := range workspaces {
+					if err := CheckTenant(ctx, ws); err != nil {
+						return nil, err
+					}
+				}
+				return "ok", nil
+			})
+		return err
+	}
+
+	if err := call(); err != nil {
+		t.Fatalf("call without CheckTenant error = %v", err)
+	}
+	if err := call("a", "a"); err != nil {
+		t.Fatalf("first call for a error = %v, want charged once", err)
+	}
+	if err := call("a"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second call for a error = %v, want ResourceExhausted", err)
+	}
+	if err := call("b"); err != nil {
+		t.Errorf("first call for b error = %v", err)
+	}
+	if err := call(""); err != nil {
+		t.Errorf("first call for the default workspace error = %v", err)
+	}
+	if err := call(""); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second call for the default workspace error = %v, want ResourceExhausted", err)
+	}
+
+	// Allow 不计数工作区规则，未经拦截器的调用不限流
+	if _, matched := limiter.Allow(context.Background(), "/shortener.ShortenerService/CreateShortLink", Caller{Subject: "bob"}); matched {
+		t.Error("Allow() matched a tenant rule")
+	}
+	if err := CheckTenant(context.Background(), "a"); err != nil {
+		t.Errorf("CheckTenant() without interceptor error = %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Result 一次取令牌的结果
+type Result struct {
+	Allowed bool
+	// Remaining 取令牌后桶中剩余的完整令牌数
+	Remaining int
+	// RetryAfter 被拒绝时，下一个令牌补充到位还需要等待的时长
+	RetryAfter time.Duration
+	// Reset 令牌桶补满还需要的时长
+	Reset time.Duration
+}
+
+// Store 保存令牌桶状态
+type Store interface {
+	// Take 从 key 对应的令牌桶中取一个令牌，rate 为每秒补充的令牌数，burst 为桶容量
+	Take(ctx context.Context, key string, rate float64, burst int) (Result, error)
+}
+
+// refill 按经过的时间补充令牌并尝试取出一个，返回取令牌后的令牌数
+func refill(tokens float64, elapsed time.Duration, rate float64, burst int) (float64, Result) {
+	tokens = math.Min(float64(burst), tokens+max(elapsed.Seconds(), 0)*rate)
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	return tokens, result(allowed, tokens, rate, burst)
+}
+
+// result 由取令牌后的令牌数计算 Result
+func result(allowed bool, tokens, rate float64, burst int) Result {
+	r := Result{
+		Allowed:   allowed,
+		Remaining: int(tokens),
+		Reset:     seconds((float64(burst) - tokens) / rate),
+	}
+	if !allowed {
+		r.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	return r
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
+
+// bucket 内存中的令牌桶
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full 令牌桶补满的时间，之后可以删除，再次使用时按满桶重新创建
+	full time.Time
+}
+
+// memorySweepInterval 清理已补满的令牌桶的最小间隔
+const memorySweepInterval = time.Minute
+
+// MemoryStore 进程内的令牌桶，多实例部署时每个实例各自计数
+type MemoryStore struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore 创建 MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, rate float64, burst int) (Result, error) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), updated: now}
+		s.buckets[key] = b
+	}
+	tokens, res := refill(b.tokens, now.Sub(b.updated), rate, burst)
+	b.tokens, b.updated, b.full = tokens, now, now.Add(res.Reset)
+	return res, nil
+}
+
+// sweep 删除已经补满的令牌桶，避免大量一次性的调用方（如扫描的 IP）占用内存
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// takeScript 在 Redis 中原子地补充并取出令牌，使用 Redis 服务器时间避免各实例的时钟偏差
+// KEYS[1] 令牌桶；ARGV[1] 每毫秒补充的令牌数；ARGV[2] 桶容量
+// 返回 {是否取到令牌, 取令牌后的令牌数}，令牌数为小数，以字符串返回
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore 保存在 Redis 中的令牌桶，所有实例共享
+type RedisStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisStore 创建 RedisStore
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, rate float64, burst int) (Result, error) {
+	reply, err := takeScript.Run(ctx, s.client, []string{key},
+		strconv.FormatFloat(rate/1000, 'g', -1, 64), burst).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(reply) != 2 {
+		return Result{}, fmt.Errorf("unexpected rate limit script reply: %v", reply)
+	}
+	allowed, _ := reply[0].(int64)
+	str, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return Result{}, err
+	}
+	return result(allowed == 1, tokens, rate, burst), nil
+}
+
+// fallbackLogInterval Redis 持续出错时记录日志的最小间隔
+const fallbackLogInterval = time.Minute
+
+// fallbackStore 优先使用 primary，出错时改用进程内的令牌桶，Redis 故障不影响请求
+type fallbackStore struct {
+	primary  Store
+	fallback Store
+	// lastLog 上次记录错误日志的时间（UnixNano）
+	lastLog atomic.Int64
+}
+
+func (s *fallbackStore) Take(ctx context.Context, key string, rate float64, burst int) (Result, error) {
+	res, err := s.primary.Take(ctx, key, rate, burst)
+	if err == nil {
+		return res, nil
+	}
+	if now, last := time.Now().UnixNano(), s.lastLog.Load(); now-last >= int64(fallbackLogInterval) && s.lastLog.CompareAndSwap(last, now) {
+		log.Printf("rate limit store unavailable, using local buckets: %v", err)
+	}
+	return s.fallback.Take(ctx, key, rate, burst)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// ClickRepository 点击事件的存储与统计
+// clicks.clicked_at 以 Unix 秒保存，时间桶通过整数运算计算，MySQL 与 SQLite 使用同一套 SQL
+type ClickRepository interface {
+	// SaveBatch 批量写入点击事件
+	// 数据库：优先写入 MySQL，如果失败则写入 SQLite
+	SaveBatch(ctx context.Context, clicks []model.Click) error
+
+	// GetStats 统计工作区内短码在 [from, to) 区间内的点击，bucket 为时间桶宽度，topN 为排行榜条数
+	GetStats(ctx context.Context, workspaceID int64, shortCode string, from, to time.Time, bucket time.Duration, topN int) (*model.LinkStats, error)
+
+	// CountSince 统计工作区内 since 之后的点击总数，用于点击配额
+	CountSince(ctx context.Context, workspaceID int64, since time.Time) (int64, error)
+}
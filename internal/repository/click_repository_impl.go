@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/username/shorturl/internal/db"
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// clickRepository 实现 ClickRepository 接口
+type clickRepository struct {
+	sources *DataSources
+}
+
+// NewClickRepository 创建新的 Click Repository
+func NewClickRepository(sources *DataSources) ClickRepository {
+	return &clickRepository{
+		sources: sources,
+	}
+}
+
+// SaveBatch 使用多行 INSERT 批量写入
+func (r *clickRepository) SaveBatch(ctx context.Context, clicks []model.Click) error {
+	if len(clicks) == 0 {
+		return nil
+	}
+
+	var err error
+	if r.sources.PrimaryDB != nil {
+		if err = r.insertClicks(ctx, r.sources.PrimaryDB, clicks); err == nil {
+			return nil
+		}
+	}
+	if r.sources.SQLiteDB != nil {
+		err = r.insertClicks(ctx, r.sources.SQLiteDB, clicks)
+	}
+	if err == nil && r.sources.PrimaryDB == nil && r.sources.SQLiteDB == nil {
+		err = errors.New("no database available")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save clicks: %w", err)
+	}
+	return nil
+}
+
+func (r *clickRepository) insertClicks(ctx context.Context, database db.Database, clicks []model.Click) error {
+	var sb strings.Builder
+	sb.WriteString(`INSERT INTO clicks (workspace_id, short_code, clicked_at, referrer, user_agent, ip_hash, ip_prefix, country) VALUES `)
+
+	args := make([]interface{}, 0, len(clicks)*8)
+	for i, c := range clicks {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, c.WorkspaceID, c.ShortCode, c.ClickedAt.Unix(), c.Referrer, c.UserAgent, c.IPHash, c.IPPrefix, c.Country)
+	}
+
+	_, err := database.GetDB().ExecContext(ctx, db.Rebind(database.Dialect(), sb.String()), args...)
+	return err
+}
+
+// GetStats 汇总主数据库和 SQLite 中的点击，与 CountSince 一致
+// 排行榜由各数据库的前 topN 合并而成，SQLite 中只有主数据库不可用期间的点击，排在 topN 之后的值可能少计
+func (r *clickRepository) GetStats(ctx context.Context, workspaceID int64, shortCode string, from, to time.Time, bucket time.Duration, topN int) (*model.LinkStats, error) {
+	var merged *model.LinkStats
+	var err error
+	for _, database := range []db.Database{r.sources.PrimaryDB, r.sources.SQLiteDB} {
+		if database == nil {
+			continue
+		}
+		stats, e := r.getStats(ctx, database, workspaceID, shortCode, from, to, bucket, topN)
+		if e != nil {
+			err = e
+			continue
+		}
+		if merged == nil {
+			merged = stats
+		} else {
+			merged = mergeStats(merged, stats, topN)
+		}
+	}
+	if merged == nil {
+		if err == nil {
+			err = errors.New("no database available")
+		}
+		return nil, err
+	}
+	return merged, nil
+}
+
+// mergeStats 合并两个数据库的统计结果：总数相加，时间桶按起始时间合并，排行榜重新排序后取前 topN
+func mergeStats(a, b *model.LinkStats, topN int) *model.LinkStats {
+	stats := &model.LinkStats{TotalClicks: a.TotalClicks + b.TotalClicks}
+
+	counts := make(map[time.Time]int64)
+	for _, bk := range append(slices.Clone(a.Buckets), b.Buckets...) {
+		if _, ok := counts[bk.Start]; !ok {
+			stats.Buckets = append(stats.Buckets, model.ClickBucket{Start: bk.Start})
+		}
+		counts[bk.Start] += bk.Count
+	}
+	for i := range stats.Buckets {
+		stats.Buckets[i].Count = counts[stats.Buckets[i].Start]
+	}
+	slices.SortFunc(stats.Buckets, func(x, y model.ClickBucket) int { return x.Start.Compare(y.Start) })
+
+	stats.TopReferrers = mergeTop(a.TopReferrers, b.TopReferrers, topN)
+	stats.TopUserAgents = mergeTop(a.TopUserAgents, b.TopUserAgents, topN)
+	return stats
+}
+
+// mergeTop 合并两个排行榜，排序规则同 topValues：次数降序，次数相同时按值升序
+func mergeTop(a, b []model.CountEntry, topN int) []model.CountEntry {
+	counts := make(map[string]int64)
+	var entries []model.CountEntry
+	for _, e := range append(slices.Clone(a), b...) {
+		if _, ok := counts[e.Value]; !ok {
+			entries = append(entries, model.CountEntry{Value: e.Value})
+		}
+		counts[e.Value] += e.Count
+	}
+	for i := range entries {
+		entries[i].Count = counts[entries[i].Value]
+	}
+	slices.SortFunc(entries, func(x, y model.CountEntry) int {
+		if x.Count != y.Count {
+			return cmp.Compare(y.Count, x.Count)
+		}
+		return strings.Compare(x.Value, y.Value)
+	})
+	if len(entries) > topN {
+		entries = entries[:topN]
+	}
+	return entries
+}
+
+// CountSince 汇总主数据库和 SQLite 中的点击：主数据库不可用期间的点击只在 SQLite 中，回放后会从 SQLite 删除
+func (r *clickRepository) CountSince(ctx context.Context, workspaceID int64, since time.Time) (int64, error) {
+	var total int64
+	counted := false
+	var err error
+	for _, database := range []db.Database{r.sources.PrimaryDB, r.sources.SQLiteDB} {
+		if database == nil {
+			continue
+		}
+		var n int64
+		if e := database.GetDB().QueryRowContext(ctx, db.Rebind(database.Dialect(),
+			`SELECT COUNT(*) FROM clicks WHERE workspace_id = ? AND clicked_at >= ?`), workspaceID, since.Unix()).Scan(&n); e != nil {
+			err = e
+			continue
+		}
+		total += n
+		counted = true
+	}
+	if !counted {
+		if err == nil {
+			err = errors.New("no database available")
+		}
+		return 0, fmt.Errorf("failed to count clicks: %w", err)
+	}
+	return total, nil
+}
+
+func (r *clickRepository) getStats(ctx context.Context, database db.Database, workspaceID int64, shortCode string, from, to time.Time, bucket time.Duration, topN int) (*model.LinkStats, error) {
+	sqlDB := database.GetDB()
+	dialect := database.Dialect()
+	stats := &model.LinkStats{}
+
+	// 1. 总点击数（不限区间）
+	err := sqlDB.QueryRowContext(ctx, db.Rebind(dialect, `SELECT COUNT(*) FROM clicks WHERE workspace_id = ? AND short_code = ?`), workspaceID, shortCode).Scan(&stats.TotalClicks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count clicks: %w", err)
+	}
+
+	// 2. 按时间桶统计
+	bucketSeconds := int64(bucket / time.Second)
+	rows, err := sqlDB.QueryContext(ctx, db.Rebind(dialect,
+		`SELECT clicked_at - clicked_at % ? AS bucket, COUNT(*) FROM clicks
+		 WHERE workspace_id = ? AND short_code = ? AND clicked_at >= ? AND clicked_at < ?
+		 GROUP BY bucket ORDER BY bucket`),
+		bucketSeconds, workspaceID, shortCode, from.Unix(), to.Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query click buckets: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var start, count int64
+		if err := rows.Scan(&start, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		stats.Buckets = append(stats.Buckets, model.ClickBucket{Start: time.Unix(start, 0).UTC(), Count: count})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	// 3. 排行榜
+	if stats.TopReferrers, err = r.topValues(ctx, database, "referrer", workspaceID, shortCode, from, to, topN); err != nil {
+		return nil, err
+	}
+	if stats.TopUserAgents, err = r.topValues(ctx, database, "user_agent", workspaceID, shortCode, from, to, topN); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// topValues 统计某一列出现次数最多的值，column 只能是内部常量
+func (r *clickRepository) topValues(ctx context.Context, database db.Database, column string, workspaceID int64, shortCode string, from, to time.Time, topN int) ([]model.CountEntry, error) {
+	query := fmt.Sprintf(
+		`SELECT %[1]s, COUNT(*) AS cnt FROM clicks
+		 WHERE workspace_id = ? AND short_code = ? AND clicked_at >= ? AND clicked_at < ?
+		 GROUP BY %[1]s ORDER BY cnt DESC, %[1]s LIMIT ?`, column)
+
+	rows, err := database.GetDB().QueryContext(ctx, db.Rebind(database.Dialect(), query), workspaceID, shortCode, from.Unix(), to.Unix(), topN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top %s: %w", column, err)
+	}
+	defer rows.Close()
+
+	var entries []model.CountEntry
+	for rows.Next() {
+		var entry model.CountEntry
+		if err := rows.Scan(&entry.Value, &entry.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// TestClickStats 测试 SQLite 上的时间桶、排行榜和工作区隔离
+func TestClickStats(t *testing.T) {
+	ctx := context.Background()
+	repo := NewClickRepository(&DataSources{SQLiteDB: newTestSQLite(t)})
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	click := func(ws int64, code string, at time.Time, referrer, ua string) model.Click {
+		return model.Click{WorkspaceID: ws, ShortCode: code, ClickedAt: at, Referrer: referrer, UserAgent: ua}
+	}
+	if err := repo.SaveBatch(ctx, []model.Click{
+		click(0, "abc", day.Add(-time.Hour), "a.com", "curl"), // 区间之前，只计入总数
+		click(0, "abc", day.Add(10*time.Minute), "b.com", "firefox"),
+		click(0, "abc", day.Add(50*time.Minute), "a.com", "chrome"),
+		click(0, "abc", day.Add(3*time.Hour+time.Second), "a.com", "chrome"),
+		click(0, "abc", day.Add(25*time.Hour), "c.com", "chrome"),
+		click(0, "abc", day.Add(49*time.Hour), "", "chrome"), // 区间之后
+		click(0, "xyz", day.Add(time.Hour), "a.com", "chrome"),
+		click(2, "abc", day.Add(time.Hour), "a.com", "chrome"),
+	}); err != nil {
+		t.Fatalf("SaveBatch() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		workspaceID   int64
+		bucket        time.Duration
+		topN          int
+		wantTotal     int64
+		wantBuckets   []model.ClickBucket
+		wantReferrers []model.CountEntry
+		wantAgents    []model.CountEntry
+	}{
+		{
+			name:      "按小时",
+			bucket:    time.Hour,
+			topN:      10,
+			wantTotal: 6,
+			wantBuckets: []model.ClickBucket{
+				{Start: day, Count: 2},
+				{Start: day.Add(3 * time.Hour), Count: 1},
+				{Start: day.Add(25 * time.Hour), Count: 1},
+			},
+			wantReferrers: []model.CountEntry{{Value: "a.com", Count: 2}, {Value: "b.com", Count: 1}, {Value: "c.com", Count: 1}},
+			wantAgents:    []model.CountEntry{{Value: "chrome", Count: 3}, {Value: "firefox", Count: 1}},
+		},
+		{
+			name:          "按天，排行榜取前 2，次数相同时按值排序",
+			bucket:        24 * time.Hour,
+			topN:          2,
+			wantTotal:     6,
+			wantBuckets:   []model.ClickBucket{{Start: day, Count: 3}, {Start: day.Add(24 * time.Hour), Count: 1}},
+			wantReferrers: []model.CountEntry{{Value: "a.com", Count: 2}, {Value: "b.com", Count: 1}},
+			wantAgents:    []model.CountEntry{{Value: "chrome", Count: 3}, {Value: "firefox", Count: 1}},
+		},
+		{
+			name:          "其他工作区",
+			workspaceID:   2,
+			bucket:        24 * time.Hour,
+			topN:          10,
+			wantTotal:     1,
+			wantBuckets:   []model.ClickBucket{{Start: day, Count: 1}},
+			wantReferrers: []model.CountEntry{{Value: "a.com", Count: 1}},
+			wantAgents:    []model.CountEntry{{Value: "chrome", Count: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := repo.GetStats(ctx, tt.workspaceID, "abc", day, day.Add(48*time.Hour), tt.bucket, tt.topN)
+			if err != nil {
+				t.Fatalf("GetStats() error = %v", err)
+			}
+			if stats.TotalClicks != tt.wantTotal {
+				t.Errorf("TotalClicks = %d, want %d", stats.TotalClicks, tt.wantTotal)
+			}
+			if !reflect.DeepEqual(stats.Buckets, tt.wantBuckets) {
+				t.Errorf("Buckets = %v, want %v", stats.Buckets, tt.wantBuckets)
+			}
+			if !reflect.DeepEqual(stats.TopReferrers, tt.wantReferrers) {
+				t.Errorf("TopReferrers = %v, want %v", stats.TopReferrers, tt.wantReferrers)
+			}
+			if !reflect.DeepEqual(stats.TopUserAgents, tt.wantAgents) {
+				t.Errorf("TopUserAgents = %v, want %v", stats.TopUserAgents, tt.wantAgents)
+			}
+		})
+	}
+}
+
+// TestClickStatsMergesDatabases 测试 GetStats 与 CountSince 一样汇总主数据库和 SQLite
+func TestClickStatsMergesDatabases(t *testing.T) {
+	ctx := context.Background()
+	primary, fallback := newTestSQLite(t), newTestSQLite(t)
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// 主数据库不可用期间的点击只写入 SQLite
+	if err := NewClickRepository(&DataSources{PrimaryDB: primary}).SaveBatch(ctx, []model.Click{
+		{ShortCode: "abc", ClickedAt: day, Referrer: "a.com", UserAgent: "chrome"},
+		{ShortCode: "abc", ClickedAt: day.Add(time.Hour), Referrer: "b.com", UserAgent: "chrome"},
+	}); err != nil {
+		t.Fatalf("SaveBatch() primary error = %v", err)
+	}
+	if err := NewClickRepository(&DataSources{SQLiteDB: fallback}).SaveBatch(ctx, []model.Click{
+		{ShortCode: "abc", ClickedAt: day.Add(time.Hour), Referrer: "b.com", UserAgent: "curl"},
+		{ShortCode: "abc", ClickedAt: day.Add(2 * time.Hour), Referrer: "b.com", UserAgent: "curl"},
+		{ShortCode: "abc", ClickedAt: day.Add(2 * time.Hour), Referrer: "c.com", UserAgent: "curl"},
+	}); err != nil {
+		t.Fatalf("SaveBatch() fallback error = %v", err)
+	}
+
+	repo := NewClickRepository(&DataSources{PrimaryDB: primary, SQLiteDB: fallback})
+	stats, err := repo.GetStats(ctx, 0, "abc", day, day.Add(24*time.Hour), time.Hour, 2)
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	count, err := repo.CountSince(ctx, 0, day)
+	if err != nil {
+		t.Fatalf("CountSince() error = %v", err)
+	}
+	if stats.TotalClicks != 5 || count != stats.TotalClicks {
+		t.Errorf("TotalClicks = %d, CountSince() = %d, want 5", stats.TotalClicks, count)
+	}
+	wantBuckets := []model.ClickBucket{
+		{Start: day, Count: 1},
+		{Start: day.Add(time.Hour), Count: 2},
+		{Start: day.Add(2 * time.Hour), Count: 2},
+	}
+	if !reflect.DeepEqual(stats.Buckets, wantBuckets) {
+		t.Errorf("Buckets = %v, want %v", stats.Buckets, wantBuckets)
+	}
+	if want := []model.CountEntry{{Value: "b.com", Count: 3}, {Value: "a.com", Count: 1}}; !reflect.DeepEqual(stats.TopReferrers, want) {
+		t.Errorf("TopReferrers = %v, want %v", stats.TopReferrers, want)
+	}
+	if want := []model.CountEntry{{Value: "curl", Count: 3}, {Value: "chrome", Count: 2}}; !reflect.DeepEqual(stats.TopUserAgents, want) {
+		t.Errorf("TopUserAgents = %v, want %v", stats.TopUserAgents, want)
+	}
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/username/shorturl/internal/db/model"
+)
+
+type ClipboardRepository interface {
+	// Get 从多个数据源并发获取，谁先返回就用谁的
+	// 不存在时返回 ErrNotFound
+	Get(ctx context.Context, shortKey string) (*model.Clipboard, error)
+
+	// Save 保存到多个数据源
+	// 缓存：优先写入 Redis，如果失败则写入 Memory
+	// 数据库：优先写入 MySQL，如果失败则写入 SQLite
+	Save(ctx context.Context, clip *model.Clipboard) error
+}
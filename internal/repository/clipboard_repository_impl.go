@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/username/shorturl/internal/db"
+	"github.com/username/shorturl/internal/db/model"
+)
+
+const clipboardKeyPrefix = "clipboard:"
+
+// clipboardRepository 实现 ClipboardRepository 接口
+type clipboardRepository struct {
+	sources *DataSources
+}
+
+// NewClipboardRepository 创建新的 Clipboard Repository
+func NewClipboardRepository(sources *DataSources) ClipboardRepository {
+	return &clipboardRepository{
+		sources: sources,
+	}
+}
+
+// Get 从多个数据源并发获取，谁先返回就用谁的
+// 优先级：RedisCache > MemoryCache > PrimaryDB > SQLiteDB
+func (r *clipboardRepository) Get(ctx context.Context, shortKey string) (*model.Clipboard, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var getters []func(ctx context.Context, shortKey string) (*model.Clipboard, error)
+	if r.sources.RedisCache != nil {
+		getters = append(getters, r.getFromRedis)
+	}
+	if r.sources.MemoryCache != nil {
+		getters = append(getters, r.getFromMemory)
+	}
+	if r.sources.PrimaryDB != nil {
+		getters = append(getters, r.getFromPrimary)
+	}
+	if r.sources.SQLiteDB != nil {
+		getters = append(getters, r.getFromSQLite)
+	}
+
+	// 缓冲区足够大，未被读取的结果不会阻塞 goroutine
+	resultCh := make(chan *model.Clipboard, len(getters))
+	var wg sync.WaitGroup
+	for _, get := range getters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clip, err := get(ctx, shortKey)
+			if err == nil && clip != nil && !clip.IsExpired() {
+				resultCh <- clip
+			}
+		}()
+	}
+
+	doneCh := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(doneCh)
+	}()
+
+	select {
+	case clip := <-resultCh:
+		// 异步回写缓存（如果从数据库获取的）
+		go r.asyncWriteToCache(context.Background(), clip)
+		return clip, nil
+	case <-doneCh:
+		// doneCh 与最后一个结果可能同时就绪，再检查一次
+		select {
+		case clip := <-resultCh:
+			return clip, nil
+		default:
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, shortKey)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Save 保存到多个数据源
+// 缓存：优先写入 Redis，如果失败则写入 Memory
+// 数据库：优先写入 MySQL，如果失败则写入 SQLite
+func (r *clipboardRepository) Save(ctx context.Context, clip *model.Clipboard) error {
+	var wg sync.WaitGroup
+	errCh := make(chan error, 2)
+
+	// 保存到缓存
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if r.sources.RedisCache != nil {
+			if err := r.saveToCache(ctx, r.sources.RedisCache.Set, clip); err == nil {
+				return
+			}
+		}
+		if r.sources.MemoryCache != nil {
+			if err := r.saveToCache(ctx, r.sources.MemoryCache.Set, clip); err != nil {
+				errCh <- fmt.Errorf("failed to save to cache: %w", err)
+			}
+		}
+	}()
+
+	// 保存到数据库
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var err error
+		if r.sources.PrimaryDB != nil {
+			if err = r.saveToDB(ctx, r.sources.PrimaryDB, clip); err == nil {
+				return
+			}
+		}
+		if r.sources.SQLiteDB != nil {
+			err = r.saveToDB(ctx, r.sources.SQLiteDB, clip)
+		}
+		if err != nil {
+			errCh <- fmt.Errorf("failed to save to database: %w", err)
+		}
+	}()
+
+	wg.Wait()
+	close(errCh)
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("some saves failed: %v", errs)
+	}
+	return nil
+}
+
+// 从各个数据源获取的辅助方法
+
+func (r *clipboardRepository) getFromRedis(ctx context.Context, shortKey string) (*model.Clipboard, error) {
+	val, err := r.sources.RedisCache.Get(ctx, clipboardKeyPrefix+shortKey)
+	return decodeClipboard(val, err)
+}
+
+func (r *clipboardRepository) getFromMemory(ctx context.Context, shortKey string) (*model.Clipboard, error) {
+	val, err := r.sources.MemoryCache.Get(ctx, clipboardKeyPrefix+shortKey)
+	return decodeClipboard(val, err)
+}
+
+func (r *clipboardRepository) getFromPrimary(ctx context.Context, shortKey string) (*model.Clipboard, error) {
+	return r.getFromDB(ctx, r.sources.PrimaryDB, shortKey)
+}
+
+func (r *clipboardRepository) getFromSQLite(ctx context.Context, shortKey string) (*model.Clipboard, error) {
+	return r.getFromDB(ctx, r.sources.SQLiteDB, shortKey)
+}
+
+func (r *clipboardRepository) getFromDB(ctx context.Context, database db.Database, shortKey string) (*model.Clipboard, error) {
+	query := db.Rebind(database.Dialect(), `SELECT id, short_key, content, created_at, expires_at FROM clipboards WHERE short_key = ?`)
+
+	var clip model.Clipboard
+	var expiresAt sql.NullTime
+	err := database.GetDB().QueryRowContext(ctx, query, shortKey).Scan(
+		&clip.ID, &clip.ShortKey, &clip.Content, &clip.CreatedAt, &expiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		clip.ExpiresAt = &expiresAt.Time
+	}
+
+	return &clip, nil
+}
+
+// decodeClipboard 将缓存中的 JSON 字符串解析为模型
+func decodeClipboard(val interface{}, err error) (*model.Clipboard, error) {
+	if err != nil || val == nil {
+		return nil, err
+	}
+
+	data, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid cache value type")
+	}
+	var clip model.Clipboard
+	if err := json.Unmarshal([]byte(data), &clip); err != nil {
+		return nil, err
+	}
+	return &clip, nil
+}
+
+// 保存到各个数据源的辅助方法
+
+func (r *clipboardRepository) saveToCache(ctx context.Context, set func(context.Context, string, interface{}, time.Duration) error, clip *model.Clipboard) error {
+	data, err := json.Marshal(clip)
+	if err != nil {
+		return err
+	}
+
+	var expiration time.Duration
+	if clip.ExpiresAt != nil {
+		expiration = time.Until(*clip.ExpiresAt)
+		if expiration < 0 {
+			return fmt.Errorf("clipboard already expired")
+		}
+	}
+
+	return set(ctx, clipboardKeyPrefix+clip.ShortKey, string(data), expiration)
+}
+
+// saveToDB 插入新片段
+func (r *clipboardRepository) saveToDB(ctx context.Context, database db.Database, clip *model.Clipboard) error {
+	query := db.Rebind(database.Dialect(), `INSERT INTO clipboards (short_key, content, created_at, expires_at) VALUES (?, ?, ?, ?)`)
+
+	var expiresAt interface{}
+	if clip.ExpiresAt != nil {
+		expiresAt = *clip.ExpiresAt
+	}
+
+	_, err := database.GetDB().ExecContext(ctx, query, clip.ShortKey, clip.Content, clip.CreatedAt, expiresAt)
+	return err
+}
+
+// 异步回写缓存
+func (r *clipboardRepository) asyncWriteToCache(ctx context.Context, clip *model.Clipboard) {
+	if r.sources.RedisCache != nil {
+		_ = r.saveToCache(ctx, r.sources.RedisCache.Set, clip)
+	} else if r.sources.MemoryCache != nil {
+		_ = r.saveToCache(ctx, r.sources.MemoryCache.Set, clip)
+	}
+}
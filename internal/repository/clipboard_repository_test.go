@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/username/shorturl/internal/cache"
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// TestClipboardRepository 测试片段的保存、读取、过期和不存在，分别只使用缓存、只使用数据库以及两者都有
+func TestClipboardRepository(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	future, past := now.Add(time.Hour), now.Add(-time.Minute)
+
+	newSources := map[string]func(t *testing.T) *DataSources{
+		"缓存和数据库": func(t *testing.T) *DataSources {
+			memory, err := cache.NewMemoryCache()
+			if err != nil {
+				t.Fatalf("NewMemoryCache() error = %v", err)
+			}
+			return &DataSources{MemoryCache: memory, SQLiteDB: newTestSQLite(t)}
+		},
+		"只有数据库": func(t *testing.T) *DataSources {
+			return &DataSources{SQLiteDB: newTestSQLite(t)}
+		},
+		"只有缓存": func(t *testing.T) *DataSources {
+			memory, err := cache.NewMemoryCache()
+			if err != nil {
+				t.Fatalf("NewMemoryCache() error = %v", err)
+			}
+			return &DataSources{MemoryCache: memory}
+		},
+	}
+
+	tests := []struct {
+		name      string
+		saved     *model.Clipboard
+		key       string
+		wantErr   error
+		wantValue string
+	}{
+		{name: "永不过期", saved: &model.Clipboard{ShortKey: "forever", Content: "hello", CreatedAt: now}, key: "forever", wantValue: "hello"},
+		{name: "未过期", saved: &model.Clipboard{ShortKey: "later", Content: "你好", CreatedAt: now, ExpiresAt: &future}, key: "later", wantValue: "你好"},
+		{name: "不存在", key: "missing", wantErr: ErrNotFound},
+	}
+	for sourcesName, newSources := range newSources {
+		for _, tt := range tests {
+			t.Run(sourcesName+"/"+tt.name, func(t *testing.T) {
+				repo := NewClipboardRepository(newSources(t))
+				if tt.saved != nil {
+					if err := repo.Save(ctx, tt.saved); err != nil {
+						t.Fatalf("Save() error = %v", err)
+					}
+				}
+				clip, err := repo.Get(ctx, tt.key)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Get() error = %v, want %v", err, tt.wantErr)
+				}
+				if err == nil && clip.Content != tt.wantValue {
+					t.Errorf("Get() content = %q, want %q", clip.Content, tt.wantValue)
+				}
+			})
+		}
+	}
+
+	// 已过期的片段不写入缓存，数据库中残留的过期数据按不存在处理
+	t.Run("已过期", func(t *testing.T) {
+		sources := newSources["缓存和数据库"](t)
+		repo := &clipboardRepository{sources: sources}
+		expired := &model.Clipboard{ShortKey: "expired", Content: "old", CreatedAt: now.Add(-time.Hour), ExpiresAt: &past}
+		if err := repo.Save(ctx, expired); err == nil {
+			t.Error("Save() expired clipboard error = nil, want cache error")
+		}
+		if _, err := repo.Get(ctx, "expired"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get() expired error = %v, want ErrNotFound", err)
+		}
+	})
+
+	// 只在数据库中的片段读取后回写缓存
+	t.Run("回写缓存", func(t *testing.T) {
+		sources := newSources["缓存和数据库"](t)
+		repo := &clipboardRepository{sources: sources}
+		if err := repo.saveToDB(ctx, sources.SQLiteDB, &model.Clipboard{ShortKey: "db-only", Content: "from db", CreatedAt: now, ExpiresAt: &future}); err != nil {
+			t.Fatalf("saveToDB() error = %v", err)
+		}
+		if clip, err := repo.Get(ctx, "db-only"); err != nil || clip.Content != "from db" {
+			t.Fatalf("Get() = %v, %v, want from db", clip, err)
+		}
+		deadline := time.Now().Add(time.Second)
+		for {
+			if exists, _ := sources.MemoryCache.Exists(ctx, clipboardKeyPrefix+"db-only"); exists {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("clipboard was not written back to the cache")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// coalescer 合并同一个键的并发查询：同一时刻只有一次查询在执行，其余调用方等待并共享结果
+// 与 singleflight 不同，调用方的 ctx 取消只影响自己的等待；全部调用方都放弃时才取消共享查询
+type coalescer struct {
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+type coalescedCall struct {
+	done    chan struct{}
+	url     *model.ShortURL
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func newCoalescer() *coalescer {
+	return &coalescer{calls: make(map[string]*coalescedCall)}
+}
+
+// do 执行或加入 key 对应的查询，shared 表示结果来自其他调用方发起的查询
+func (c *coalescer) do(ctx context.Context, key string, fetch func(ctx context.Context) (*model.ShortURL, error)) (url *model.ShortURL, err error, shared bool) {
+	c.mu.Lock()
+	call, shared := c.calls[key]
+	if shared {
+		call.waiters++
+	} else {
+		// 共享查询不继承发起方的取消，但保留 ctx 中的值
+		fetchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &coalescedCall{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		c.calls[key] = call
+		go c.run(key, call, fetchCtx, fetch)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if call.url != nil {
+			// 每个调用方拿到独立的副本，避免互相修改
+			cp := *call.url
+			return &cp, call.err, shared
+		}
+		return nil, call.err, shared
+	case <-ctx.Done():
+		c.mu.Lock()
+		if call.waiters--; call.waiters == 0 {
+			call.cancel()
+			if c.calls[key] == call {
+				delete(c.calls, key)
+			}
+		}
+		c.mu.Unlock()
+		return nil, ctx.Err(), shared
+	}
+}
+
+func (c *coalescer) run(key string, call *coalescedCall, ctx context.Context, fetch func(ctx context.Context) (*model.ShortURL, error)) {
+	defer call.cancel()
+	call.url, call.err = fetch(ctx)
+
+	c.mu.Lock()
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+	c.mu.Unlock()
+	close(call.done)
+}
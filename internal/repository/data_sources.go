@@ -1,12 +1,13 @@
 package repository
 
 import (
+	"errors"
 	"log"
+	"time"
 
 	"github.com/username/shorturl/internal/cache"
 	"github.com/username/shorturl/internal/config"
 	"github.com/username/shorturl/internal/db"
-	"gorm.io/gorm"
 )
 
 // DataSources 管理所有可用的数据源
@@ -15,9 +16,18 @@ type DataSources struct {
 	RedisCache  cache.Cache
 	MemoryCache cache.Cache
 
-	// 数据库：MySQL 优先，SQLite 作为 fallback
-	MySQLDB  db.Database
-	SQLiteDB db.Database
+	// 数据库：主数据库（MySQL 或 PostgreSQL）优先，SQLite 作为 fallback
+	PrimaryDB db.Database
+	SQLiteDB  db.Database
+
+	// 短链接读路径策略与每层超时
+	Read ReadOptions
+	// NegativeTTL 不存在的短码在缓存中记录的时长，0 表示不缓存
+	NegativeTTL time.Duration
+	// KnownCodes 已存在短码的 Bloom filter，nil 表示未启用
+	KnownCodes *KnownCodes
+	// Invalidation 实例之间的缓存失效广播，nil 表示未启用
+	Invalidation *Invalidator
 }
 
 // NewDataSources 创建数据源管理器
@@ -25,28 +35,72 @@ type DataSources struct {
 func NewDataSources(cfg *config.Config) *DataSources {
 	ds := &DataSources{}
 
+	strategy, err := ParseReadStrategy(cfg.ReadPath.Strategy)
+	if err != nil {
+		log.Printf("%v，使用 %s", err, ReadTiered)
+		strategy = ReadTiered
+	}
+	ds.Read = ReadOptions{
+		Strategy:   strategy,
+		HedgeDelay: cfg.ReadPath.HedgeDelay,
+		Timeouts: map[Tier]time.Duration{
+			TierMemory:   cfg.ReadPath.Timeouts.Memory,
+			TierRedis:    cfg.ReadPath.Timeouts.Redis,
+			TierPrimary:  cfg.ReadPath.Timeouts.Primary,
+			TierFallback: cfg.ReadPath.Timeouts.Fallback,
+		},
+	}
+
+	ds.NegativeTTL = cfg.NegativeCache.TTL
+	if cfg.BloomFilter.Enabled {
+		ds.KnownCodes = NewKnownCodes(cfg.BloomFilter.ExpectedItems, cfg.BloomFilter.FalsePositiveRate)
+	}
+
 	// 初始化缓存
 	// 尝试创建 Redis（如果配置了）
-	if cfg.RedisAddr != "" {
-		if redisCache, err := cache.NewRedisCache(cfg.RedisAddr); err == nil {
-			log.Println("初始化redis:", cfg.RedisAddr)
+	if redisOpts, err := cache.RedisOptionsFromConfig(cfg); err != nil {
+		log.Printf("Redis 配置错误: %v", err)
+	} else if len(redisOpts.Addrs) > 0 {
+		if redisCache, err := cache.NewRedisCache(redisOpts); err == nil {
+			log.Println("初始化redis:", redisOpts.Addrs)
 			ds.RedisCache = redisCache
 		}
 	}
 	// MemoryCache 总是可用的
-	if memoryCache, err := cache.NewMemoryCache(); err == nil {
+	memoryOpts := cache.MemoryOptions{
+		MaxEntries: cfg.MemoryCache.MaxEntries,
+		MaxBytes:   cfg.MemoryCache.MaxBytes,
+		Policy:     cfg.MemoryCache.Policy,
+	}
+	if memoryCache, err := cache.NewMemoryCacheWithOptions(memoryOpts); err != nil {
+		log.Printf("初始化memoryCache失败: %v", err)
+	} else {
 		log.Println("初始化memoryCache")
 		ds.MemoryCache = memoryCache
 	}
 
-	// 初始化数据库
-	// 尝试创建 MySQL（如果配置了）
-	if cfg.MySQLDSN != "" {
-		if mysqlDB, err := db.NewMySQLDB(cfg.MySQLDSN); err == nil {
-			log.Println("初始化MysqlDB")
-			ds.MySQLDB = mysqlDB
+	// 缓存失效广播依赖 Redis pub/sub
+	if cfg.Invalidation.Enabled && ds.RedisCache != nil {
+		if transport, err := cache.NewRedisTransport(ds.RedisCache, cfg.Invalidation.Channel); err != nil {
+			log.Printf("初始化缓存失效广播失败: %v", err)
+		} else {
+			ds.Invalidation = NewInvalidator(transport, ds)
 		}
 	}
+	// 没有广播时无法得知其他实例新建的短码，Bloom filter 会把它们误判为不存在
+	if ds.KnownCodes != nil && ds.Invalidation == nil {
+		log.Println("缓存失效广播未启用，不使用短码 Bloom filter")
+		ds.KnownCodes = nil
+	}
+
+	// 初始化数据库
+	// 尝试创建主数据库 MySQL / PostgreSQL（如果配置了）
+	if primaryDB, err := db.NewPrimaryDatabase(cfg); err == nil {
+		log.Println("初始化主数据库:", primaryDB.Dialect())
+		ds.PrimaryDB = primaryDB
+	} else if !errors.Is(err, db.ErrNotConfigured) {
+		log.Printf("初始化主数据库失败: %v", err)
+	}
 	// 尝试创建 SQLite（总是尝试，作为最后的 fallback）
 	if sqliteDB, err := db.NewSQLiteDB(cfg.SQLitePath); err == nil {
 		log.Println("初始化SqlLite")
@@ -65,12 +119,3 @@ func GetDataSources() (*DataSources, error) {
 	}
 	return GloablDataSources, nil
 }
-
-// migrateTable is migrate db table.
-func migrateTable(db *gorm.DB, tables ...interface{}) {
-	err := db.Set("gorm:table_options", "CHARSET=utf8mb4").AutoMigrate(tables...)
-	if err != nil {
-
-		panic(err)
-	}
-}
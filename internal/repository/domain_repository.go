@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/username/shorturl/internal/db/model"
+)
+
+var (
+	// ErrDomainNotFound 域名未登记
+	ErrDomainNotFound = errors.New("domain not found")
+	// ErrDomainExists 域名已登记到某个工作区
+	ErrDomainExists = errors.New("domain already registered")
+)
+
+// DomainRepository 自定义域名登记表
+// 与工作区一样以主数据库为准并复制到 SQLite，查询结果在进程内缓存一小段时间
+type DomainRepository interface {
+	// Create 登记域名并回填 ID，域名已登记时返回 ErrDomainExists
+	Create(ctx context.Context, domain *model.Domain) error
+
+	// GetByHostname 按主机名查询，未登记时返回 ErrDomainNotFound
+	GetByHostname(ctx context.Context, hostname string) (*model.Domain, error)
+
+	// Primary 返回工作区已验证的主域名，没有时返回 nil, nil
+	Primary(ctx context.Context, workspaceID int64) (*model.Domain, error)
+
+	// ListByWorkspace 返回工作区的所有域名，workspaceID 为负数时返回所有工作区的域名
+	ListByWorkspace(ctx context.Context, workspaceID int64) ([]model.Domain, error)
+
+	// MarkVerified 记录域名验证通过的时间
+	MarkVerified(ctx context.Context, hostname string) error
+
+	// SetPrimary 把域名设为所在工作区的主域名，同一工作区的其他域名取消主域名
+	SetPrimary(ctx context.Context, hostname string) error
+
+	// Delete 删除域名，未登记时返回 ErrDomainNotFound
+	Delete(ctx context.Context, hostname string) error
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/username/shorturl/internal/db"
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// domainRepository 实现 DomainRepository 接口
+type domainRepository struct {
+	sources *DataSources
+}
+
+// NewDomainRepository 创建新的域名 Repository
+func NewDomainRepository(sources *DataSources) DomainRepository {
+	return &domainRepository{
+		sources: sources,
+	}
+}
+
+const domainColumns = `id, hostname, workspace_id, is_primary, verification_token, verified_at, created_at`
+
+func (r *domainRepository) Create(ctx context.Context, domain *model.Domain) error {
+	database, err := systemOfRecord(r.sources)
+	if err != nil {
+		return err
+	}
+	if domain.CreatedAt.IsZero() {
+		domain.CreatedAt = time.Now()
+	}
+
+	query := `INSERT INTO domains (hostname, workspace_id, is_primary, verification_token, verified_at, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	args := []interface{}{domain.Hostname, domain.WorkspaceID, domain.Primary, domain.VerificationToken, nullableTime(domain.VerifiedAt), domain.CreatedAt}
+	if database.Dialect() == db.DialectPostgres {
+		err = database.GetDB().QueryRowContext(ctx, db.Rebind(db.DialectPostgres, query+` RETURNING id`), args...).Scan(&domain.ID)
+	} else {
+		var res sql.Result
+		if res, err = database.GetDB().ExecContext(ctx, query, args...); err == nil {
+			domain.ID, err = res.LastInsertId()
+		}
+	}
+	if err != nil {
+		if db.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %s", ErrDomainExists, domain.Hostname)
+		}
+		return err
+	}
+
+	if sqliteDB := r.sources.SQLiteDB; sqliteDB != nil && database != sqliteDB {
+		_, err := sqliteDB.GetDB().ExecContext(ctx,
+			`INSERT INTO domains (`+domainColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?) `+db.OnConflictUpdate(db.DialectSQLite, "id")+
+				` hostname = excluded.hostname, workspace_id = excluded.workspace_id, is_primary = excluded.is_primary, verification_token = excluded.verification_token, verified_at = excluded.verified_at`,
+			domain.ID, domain.Hostname, domain.WorkspaceID, domain.Primary, domain.VerificationToken, nullableTime(domain.VerifiedAt), domain.CreatedAt,
+		)
+		if err != nil {
+			log.Printf("failed to copy domain %s to SQLite: %v", domain.Hostname, err)
+		}
+	}
+	r.invalidate(domain.Hostname, domain.WorkspaceID)
+	return nil
+}
+
+func (r *domainRepository) GetByHostname(ctx context.Context, hostname string) (*model.Domain, error) {
+	value, err := cached("domain:"+hostname, func() (any, error) {
+		domains, err := r.query(ctx, `WHERE hostname = ?`, hostname)
+		if err != nil || len(domains) == 0 {
+			return nil, err
+		}
+		return &domains[0], nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain %s: %w", hostname, err)
+	}
+	if value == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDomainNotFound, hostname)
+	}
+	return value.(*model.Domain), nil
+}
+
+func (r *domainRepository) Primary(ctx context.Context, workspaceID int64) (*model.Domain, error) {
+	value, err := cached("primary:"+strconv.FormatInt(workspaceID, 10), func() (any, error) {
+		domains, err := r.query(ctx, `WHERE workspace_id = ? AND is_primary = ? AND verified_at IS NOT NULL`, workspaceID, true)
+		if err != nil || len(domains) == 0 {
+			return nil, err
+		}
+		return &domains[0], nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get primary domain of workspace %d: %w", workspaceID, err)
+	}
+	if value == nil {
+		return nil, nil
+	}
+	return value.(*model.Domain), nil
+}
+
+func (r *domainRepository) ListByWorkspace(ctx context.Context, workspaceID int64) ([]model.Domain, error) {
+	if workspaceID < 0 {
+		return r.query(ctx, ``)
+	}
+	return r.query(ctx, `WHERE workspace_id = ?`, workspaceID)
+}
+
+// query 按条件查询，结果按 id 排序；where 只能是内部常量
+func (r *domainRepository) query(ctx context.Context, where string, args ...interface{}) ([]model.Domain, error) {
+	var domains []model.Domain
+	err := readWithFallback(r.sources, func(database db.Database) error {
+		domains = nil
+		rows, err := database.GetDB().QueryContext(ctx, db.Rebind(database.Dialect(),
+			`SELECT `+domainColumns+` FROM domains `+where+` ORDER BY id`), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var d model.Domain
+			var verifiedAt sql.NullTime
+			if err := rows.Scan(&d.ID, &d.Hostname, &d.WorkspaceID, &d.Primary, &d.VerificationToken, &verifiedAt, &d.CreatedAt); err != nil {
+				return fmt.Errorf("failed to scan row: %w", err)
+			}
+			if verifiedAt.Valid {
+				d.VerifiedAt = &verifiedAt.Time
+			}
+			domains = append(domains, d)
+		}
+		return rows.Err()
+	})
+	return domains, err
+}
+
+func (r *domainRepository) MarkVerified(ctx context.Context, hostname string) error {
+	return r.update(ctx, hostname, `UPDATE domains SET verified_at = ? WHERE hostname = ?`, time.Now(), hostname)
+}
+
+func (r *domainRepository) SetPrimary(ctx context.Context, hostname string) error {
+	domain, err := r.GetByHostname(ctx, hostname)
+	if err != nil {
+		return err
+	}
+	return r.update(ctx, hostname,
+		`UPDATE domains SET is_primary = (hostname = ?) WHERE workspace_id = ?`, hostname, domain.WorkspaceID)
+}
+
+func (r *domainRepository) Delete(ctx context.Context, hostname string) error {
+	return r.update(ctx, hostname, `DELETE FROM domains WHERE hostname = ?`, hostname)
+}
+
+// update 在主数据库执行修改并同步到 SQLite 中的副本，未修改任何行时返回 ErrDomainNotFound
+func (r *domainRepository) update(ctx context.Context, hostname, query string, args ...interface{}) error {
+	database, err := systemOfRecord(r.sources)
+	if err != nil {
+		return err
+	}
+	// 修改前记录所在的工作区，用于清除主域名缓存
+	var workspaceID int64 = -1
+	if domains, err := r.query(ctx, `WHERE hostname = ?`, hostname); err == nil && len(domains) > 0 {
+		workspaceID = domains[0].WorkspaceID
+	}
+
+	res, err := database.GetDB().ExecContext(ctx, db.Rebind(database.Dialect(), query), args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", ErrDomainNotFound, hostname)
+	}
+	if sqliteDB := r.sources.SQLiteDB; sqliteDB != nil && database != sqliteDB {
+		if _, err := sqliteDB.GetDB().ExecContext(ctx, query, args...); err != nil {
+			log.Printf("failed to copy domain %s to SQLite: %v", hostname, err)
+		}
+	}
+	r.invalidate(hostname, workspaceID)
+	return nil
+}
+
+// invalidate 清除本进程的缓存，其他实例在缓存过期后生效
+func (r *domainRepository) invalidate(hostname string, workspaceID int64) {
+	workspaceCache.Delete("domain:" + hostname)
+	if workspaceID >= 0 {
+		workspaceCache.Delete("primary:" + strconv.FormatInt(workspaceID, 10))
+	}
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/username/shorturl/internal/cache"
+)
+
+// invalidationMessage 实例之间广播的缓存失效消息
+type invalidationMessage struct {
+	// Origin 发送方实例 ID，实例忽略自己发出的消息
+	Origin string `json:"origin"`
+	// Codes 受影响的 namespacedCode（非默认工作区带有工作区前缀）
+	Codes []string `json:"codes"`
+	// Created 短码是新写入的，接收方还需要记录到 Bloom filter
+	Created bool `json:"created,omitempty"`
+}
+
+// Invalidator 通过 Transport 在实例之间同步本地缓存
+// 修改短链接的实例广播受影响的短码，其他实例从自己的 MemoryCache 中删除对应的数据和负缓存；
+// Redis 是共享的，由发送方负责清理
+type Invalidator struct {
+	transport  cache.Transport
+	instanceID string
+	local      cache.Cache
+	knownCodes *KnownCodes
+	// sources 订阅后重建 Bloom filter 时读取的数据库
+	sources *DataSources
+}
+
+// NewInvalidator 创建 Invalidator，清理 sources.MemoryCache 并同步 sources.KnownCodes（可以为 nil）
+func NewInvalidator(transport cache.Transport, sources *DataSources) *Invalidator {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return &Invalidator{
+		transport:  transport,
+		instanceID: hex.EncodeToString(id),
+		local:      sources.MemoryCache,
+		knownCodes: sources.KnownCodes,
+		sources:    sources,
+	}
+}
+
+// Publish 广播短码的变更，created 表示短码是新写入的；未启用时不做任何事
+// 广播失败只记录日志，其他实例的本地缓存会在过期后更新
+func (inv *Invalidator) Publish(ctx context.Context, codes []string, created bool) {
+	if inv == nil || len(codes) == 0 {
+		return
+	}
+	payload, err := json.Marshal(invalidationMessage{Origin: inv.instanceID, Codes: codes, Created: created})
+	if err != nil {
+		return
+	}
+	if err := inv.transport.Publish(ctx, payload); err != nil {
+		log.Printf("failed to publish cache invalidation for %d codes: %v", len(codes), err)
+	}
+}
+
+// Run 订阅失效消息直到 ctx 取消，连接断开后自动重新订阅
+// 每次订阅成功后重建 Bloom filter，此后才信任其"一定不存在"的判断；订阅断开期间不信任
+// 重新订阅时断开期间的消息已经丢失，因此还要清空本地缓存
+func (inv *Invalidator) Run(ctx context.Context) {
+	const initialBackoff = time.Second
+	backoff := initialBackoff
+	subscribed := false
+	ready := func() {
+		if subscribed {
+			if c, ok := inv.local.(cache.Clearer); ok {
+				c.Clear()
+			}
+		}
+		if inv.knownCodes != nil {
+			// 重建期间到达的消息由 KnownCodes.Add 同时记录到新的 filter，不阻塞消息处理
+			go func() {
+				if err := inv.knownCodes.Resync(ctx, inv.sources); err != nil && ctx.Err() == nil {
+					log.Printf("同步短码 Bloom filter 失败: %v", err)
+				}
+			}()
+		}
+		subscribed = true
+		backoff = initialBackoff
+	}
+
+	for {
+		err := inv.transport.Subscribe(ctx, ready, inv.handle)
+		inv.knownCodes.Desync()
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("cache invalidation subscription lost: %v, retrying in %v", err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+// handle 处理一条失效消息
+func (inv *Invalidator) handle(payload []byte) {
+	var msg invalidationMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		log.Printf("invalid cache invalidation message: %v", err)
+		return
+	}
+	if msg.Origin == inv.instanceID {
+		return
+	}
+
+	ctx := context.Background()
+	for _, code := range msg.Codes {
+		if msg.Created {
+			inv.knownCodes.Add(code)
+		}
+		if inv.local != nil {
+			_ = inv.local.Delete(ctx, "shorturl:"+code)
+			_ = inv.local.Delete(ctx, negativeKeyPrefix+code)
+		}
+	}
+}
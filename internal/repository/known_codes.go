@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/username/shorturl/internal/bloom"
+	"github.com/username/shorturl/internal/db"
+)
+
+// knownCodesScanBatch 重建时每次从数据库读取的短码数量
+const knownCodesScanBatch = 5000
+
+// KnownCodes 已存在短码的 Bloom filter，用于在不访问缓存和数据库的情况下拒绝一定不存在的短码
+// 记录的是 namespacedCode，不同工作区的同名短码互不影响
+// Bloom filter 不支持删除，已删除的短码在下次重建前仍视为可能存在
+// 其他实例新建的短码只能通过 Invalidator 广播得知，因此只有订阅广播之后重建完成（Resync）才拒绝短码，
+// 订阅断开（Desync）期间的消息会丢失，在重新同步之前不拒绝任何短码
+type KnownCodes struct {
+	expected uint64
+	fpRate   float64
+
+	filter atomic.Pointer[bloom.Filter]
+	// synced filter 包含了所有实例写入的短码
+	synced atomic.Bool
+
+	mu       sync.Mutex
+	building *bloom.Filter // 重建期间的写入同时记录到新的 filter 中
+	// epoch 每次 Desync 加一，Resync 期间发生过 Desync 时不标记为已同步
+	epoch uint64
+}
+
+// NewKnownCodes 创建空的 KnownCodes，expected 为预计短码数量，fpRate 为期望误判率
+func NewKnownCodes(expected uint64, fpRate float64) *KnownCodes {
+	return &KnownCodes{expected: expected, fpRate: fpRate}
+}
+
+// MayContain 短码可能存在时返回 true，未启用、尚未构建或未与其他实例同步时总是返回 true
+func (k *KnownCodes) MayContain(shortCode string) bool {
+	if k == nil || !k.synced.Load() {
+		return true
+	}
+	f := k.filter.Load()
+	return f == nil || f.MayContain(shortCode)
+}
+
+// Add 记录新写入的短码，需要在数据库写入成功后调用
+func (k *KnownCodes) Add(shortCode string) {
+	if k == nil {
+		return
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if f := k.filter.Load(); f != nil {
+		f.Add(shortCode)
+	}
+	if k.building != nil {
+		k.building.Add(shortCode)
+	}
+}
+
+// Rebuild 从所有数据库读取短码重新构建 filter
+// 任一数据库读取失败时保留旧的 filter，避免漏掉该数据库中的短码而误拒
+func (k *KnownCodes) Rebuild(ctx context.Context, sources *DataSources) error {
+	var databases []db.Database
+	for _, database := range []db.Database{sources.PrimaryDB, sources.SQLiteDB} {
+		if database != nil {
+			databases = append(databases, database)
+		}
+	}
+	if len(databases) == 0 {
+		return errors.New("no database available")
+	}
+
+	var total uint64
+	for _, database := range databases {
+		var count uint64
+		if err := database.GetDB().QueryRowContext(ctx, `SELECT COUNT(*) FROM short_urls`).Scan(&count); err != nil {
+			return fmt.Errorf("failed to count short codes: %w", err)
+		}
+		total += count
+	}
+	// 预留一倍余量给重建后新增的短码
+	next := bloom.New(max(k.expected, total*2), k.fpRate)
+
+	// 先登记再扫描：登记之前提交的写入一定能被扫描到，之后的写入由 Add 记录
+	k.mu.Lock()
+	k.building = next
+	k.mu.Unlock()
+	defer func() {
+		k.mu.Lock()
+		k.building = nil
+		k.mu.Unlock()
+	}()
+
+	for _, database := range databases {
+		if err := scanShortCodes(ctx, database, next.Add); err != nil {
+			return fmt.Errorf("failed to load short codes: %w", err)
+		}
+	}
+
+	k.mu.Lock()
+	k.filter.Store(next)
+	k.mu.Unlock()
+	return nil
+}
+
+// Resync 重建 filter 并标记为已同步，需要在订阅 Invalidator 广播之后调用：
+// 订阅之前其他实例写入的短码由重建扫描到，之后的由广播记录
+func (k *KnownCodes) Resync(ctx context.Context, sources *DataSources) error {
+	if k == nil {
+		return nil
+	}
+	k.mu.Lock()
+	epoch := k.epoch
+	k.mu.Unlock()
+
+	if err := k.Rebuild(ctx, sources); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.epoch == epoch {
+		k.synced.Store(true)
+	}
+	return nil
+}
+
+// Desync 标记为未同步，Invalidator 的订阅断开时调用，之后 MayContain 总是返回 true
+func (k *KnownCodes) Desync() {
+	if k == nil {
+		return
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.epoch++
+	k.synced.Store(false)
+}
+
+// Run 立即重建一次，之后每隔 interval 重建，直到 ctx 取消；interval 为 0 时只在启动时重建
+func (k *KnownCodes) Run(ctx context.Context, sources *DataSources, interval time.Duration) {
+	for {
+		start := time.Now()
+		if err := k.Rebuild(ctx, sources); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("重建短码 Bloom filter 失败: %v", err)
+		} else {
+			log.Printf("重建短码 Bloom filter 完成，耗时 %v", time.Since(start))
+		}
+		if interval <= 0 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// scanShortCodes 按 id 分批读取所有短码
+func scanShortCodes(ctx context.Context, database db.Database, fn func(string)) error {
+	var afterID int64
+	for {
+		rows, err := database.GetDB().QueryContext(ctx, db.Rebind(database.Dialect(),
+			`SELECT id, workspace_id, short_code FROM short_urls WHERE id > ? ORDER BY id LIMIT ?`), afterID, knownCodesScanBatch)
+		if err != nil {
+			return err
+		}
+		n := 0
+		for rows.Next() {
+			var workspaceID int64
+			var code string
+			if err := rows.Scan(&afterID, &workspaceID, &code); err != nil {
+				rows.Close()
+				return err
+			}
+			fn(namespacedCode(workspaceID, code))
+			n++
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		if n < knownCodesScanBatch {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"strconv"
+
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// namespacedCode 短码在所有工作区中唯一的表示：默认工作区为短码本身，其他工作区为 "<工作区 id>:<短码>"
+// 短码不含 ':'，不会与其他工作区的短码混淆；用于缓存键、负缓存、Bloom filter、查询合并和失效广播
+func namespacedCode(workspaceID int64, shortCode string) string {
+	if workspaceID == model.DefaultWorkspaceID {
+		return shortCode
+	}
+	return strconv.FormatInt(workspaceID, 10) + ":" + shortCode
+}
+
+// cacheKey 短链接的缓存键：默认工作区沿用 "shorturl:<短码>"，其他工作区为 "shorturl:<工作区 id>:<短码>"
+func cacheKey(workspaceID int64, shortCode string) string {
+	return "shorturl:" + namespacedCode(workspaceID, shortCode)
+}
+
+// negativeKey 负缓存的键
+func negativeKey(workspaceID int64, shortCode string) string {
+	return negativeKeyPrefix + namespacedCode(workspaceID, shortCode)
+}
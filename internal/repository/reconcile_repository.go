@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// ReconcileOutcome 单条 SQLite 回退数据的对账结果
+type ReconcileOutcome int
+
+const (
+	// ReconcileInserted MySQL 中不存在，已写入 MySQL
+	ReconcileInserted ReconcileOutcome = iota
+	// ReconcileIdentical MySQL 中已有相同数据，仅删除 SQLite 副本
+	ReconcileIdentical
+	// ReconcileKeptMySQL 数据冲突，MySQL 中的数据创建更早（或同时创建），保留 MySQL 数据
+	ReconcileKeptMySQL
+	// ReconcileKeptSQLite 数据冲突，SQLite 中的数据创建更早，覆盖 MySQL 数据
+	ReconcileKeptSQLite
+)
+
+// ReconcileRepository 把 MySQL 不可用期间写入 SQLite 的数据回放到 MySQL
+// SQLite 中的短链接和点击事件都来自写 MySQL 失败后的回退，回放成功后从 SQLite 删除
+// 主数据库配置为 PostgreSQL 时，文中的 MySQL 均指 PostgreSQL
+type ReconcileRepository interface {
+	// Available MySQL 和 SQLite 都已配置时才需要对账
+	Available() bool
+
+	// PingMySQL 检查 MySQL 是否可用
+	PingMySQL(ctx context.Context) error
+
+	// PendingShortURLs 按 id 顺序读取 SQLite 中 id 大于 afterID 的短链接
+	PendingShortURLs(ctx context.Context, afterID int64, limit int) ([]model.ShortURL, error)
+
+	// ReconcileShortURL 在 MySQL 事务中写入或按创建时间解决冲突，落选的数据写入 short_url_conflicts，
+	// 然后删除 SQLite 中的这一条。可重复执行：删除 SQLite 失败时，下次对账会判定为 ReconcileIdentical
+	ReconcileShortURL(ctx context.Context, url *model.ShortURL, host string) (ReconcileOutcome, error)
+
+	// ReplayClicks 把 SQLite 中最多 limit 条点击事件写入 MySQL 并删除，返回回放的条数
+	// 写入 MySQL 与删除 SQLite 不在同一事务中，进程在两步之间退出时会产生重复点击（至少一次）
+	ReplayClicks(ctx context.Context, limit int) (int, error)
+}
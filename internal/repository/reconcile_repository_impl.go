@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/username/shorturl/internal/db"
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// reconcileRepository 实现 ReconcileRepository 接口
+type reconcileRepository struct {
+	sources *DataSources
+}
+
+// NewReconcileRepository 创建新的对账 Repository
+func NewReconcileRepository(sources *DataSources) ReconcileRepository {
+	return &reconcileRepository{
+		sources: sources,
+	}
+}
+
+func (r *reconcileRepository) Available() bool {
+	return r.sources.PrimaryDB != nil && r.sources.SQLiteDB != nil
+}
+
+func (r *reconcileRepository) PingMySQL(ctx context.Context) error {
+	return r.sources.PrimaryDB.GetDB().PingContext(ctx)
+}
+
+func (r *reconcileRepository) PendingShortURLs(ctx context.Context, afterID int64, limit int) ([]model.ShortURL, error) {
+	query := `SELECT id, short_code, long_url, redirect_code, created_at, expires_at, owner_id, workspace_id FROM short_urls WHERE id > ? ORDER BY id LIMIT ?`
+	rows, err := r.sources.SQLiteDB.GetDB().QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query SQLite short URLs: %w", err)
+	}
+	defer rows.Close()
+
+	var urls []model.ShortURL
+	for rows.Next() {
+		var u model.ShortURL
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&u.ID, &u.ShortCode, &u.LongURL, &u.RedirectCode, &u.CreatedAt, &expiresAt, &u.OwnerID, &u.WorkspaceID); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if expiresAt.Valid {
+			u.ExpiresAt = &expiresAt.Time
+		}
+		urls = append(urls, u)
+	}
+	return urls, rows.Err()
+}
+
+func (r *reconcileRepository) ReconcileShortURL(ctx context.Context, url *model.ShortURL, host string) (ReconcileOutcome, error) {
+	outcome, err := r.mergeIntoMySQL(ctx, url, host)
+	if err != nil {
+		return outcome, err
+	}
+
+	// MySQL 中的数据已确定，删除 SQLite 中的这一条（按 id 删除，SQLite 中的 id 只在本地有效）
+	if _, err := r.sources.SQLiteDB.GetDB().ExecContext(ctx, `DELETE FROM short_urls WHERE id = ?`, url.ID); err != nil {
+		return outcome, fmt.Errorf("failed to delete reconciled row from SQLite: %w", err)
+	}
+	if outcome == ReconcileInserted {
+		// 回放前只有本实例的 SQLite 中有这条数据，通知其他实例记录到 Bloom filter
+		r.sources.Invalidation.Publish(ctx, []string{namespacedCode(url.WorkspaceID, url.ShortCode)}, true)
+	}
+	return outcome, nil
+}
+
+// mergeIntoMySQL 在一个 MySQL 事务中写入短链接或解决冲突
+// 冲突规则：创建时间早的一方保留短码（与“仅插入、先到先得”的语义一致），创建时间相同时保留 MySQL
+func (r *reconcileRepository) mergeIntoMySQL(ctx context.Context, url *model.ShortURL, host string) (ReconcileOutcome, error) {
+	dialect := r.sources.PrimaryDB.Dialect()
+	tx, err := r.sources.PrimaryDB.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var current model.ShortURL
+	var expiresAt sql.NullTime
+	err = tx.QueryRowContext(ctx, db.Rebind(dialect,
+		`SELECT id, short_code, long_url, redirect_code, created_at, expires_at, owner_id, workspace_id FROM short_urls WHERE workspace_id = ? AND short_code = ? FOR UPDATE`),
+		url.WorkspaceID, url.ShortCode,
+	).Scan(&current.ID, &current.ShortCode, &current.LongURL, &current.RedirectCode, &current.CreatedAt, &expiresAt, &current.OwnerID, &current.WorkspaceID)
+	if expiresAt.Valid {
+		current.ExpiresAt = &expiresAt.Time
+	}
+
+	var outcome ReconcileOutcome
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		outcome = ReconcileInserted
+		_, err = tx.ExecContext(ctx, db.Rebind(dialect,
+			`INSERT INTO short_urls (short_code, long_url, redirect_code, created_at, expires_at, owner_id, workspace_id) VALUES (?, ?, ?, ?, ?, ?, ?)`),
+			url.ShortCode, url.LongURL, url.RedirectCode, url.CreatedAt, nullableTime(url.ExpiresAt), url.OwnerID, url.WorkspaceID,
+		)
+	case err != nil:
+		return 0, err
+	case sameShortURL(&current, url):
+		outcome = ReconcileIdentical
+	case truncateMillis(url.CreatedAt).Before(truncateMillis(current.CreatedAt)):
+		outcome = ReconcileKeptSQLite
+		if err = insertConflict(ctx, tx, dialect, &current, dialect, host); err == nil {
+			_, err = tx.ExecContext(ctx, db.Rebind(dialect,
+				`UPDATE short_urls SET long_url = ?, redirect_code = ?, created_at = ?, expires_at = ?, owner_id = ? WHERE id = ?`),
+				url.LongURL, url.RedirectCode, url.CreatedAt, nullableTime(url.ExpiresAt), url.OwnerID, current.ID,
+			)
+		}
+	default:
+		outcome = ReconcileKeptMySQL
+		err = insertConflict(ctx, tx, dialect, url, db.DialectSQLite, host)
+	}
+	if err != nil {
+		return outcome, err
+	}
+	return outcome, tx.Commit()
+}
+
+// insertConflict 记录冲突中落选的数据，source 为落选数据原来所在的数据库（方言名）
+func insertConflict(ctx context.Context, tx *sql.Tx, dialect string, url *model.ShortURL, source, host string) error {
+	_, err := tx.ExecContext(ctx, db.Rebind(dialect,
+		`INSERT INTO short_url_conflicts (short_code, long_url, redirect_code, created_at, expires_at, owner_id, workspace_id, source, host, resolved_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		url.ShortCode, url.LongURL, url.RedirectCode, url.CreatedAt, nullableTime(url.ExpiresAt), url.OwnerID, url.WorkspaceID, source, host, time.Now(),
+	)
+	return err
+}
+
+func (r *reconcileRepository) ReplayClicks(ctx context.Context, limit int) (int, error) {
+	rows, err := r.sources.SQLiteDB.GetDB().QueryContext(ctx,
+		`SELECT id, workspace_id, short_code, clicked_at, referrer, user_agent, ip_hash, ip_prefix, country FROM clicks ORDER BY id LIMIT ?`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query SQLite clicks: %w", err)
+	}
+	var clicks []model.Click
+	for rows.Next() {
+		var c model.Click
+		var clickedAt int64
+		if err := rows.Scan(&c.ID, &c.WorkspaceID, &c.ShortCode, &clickedAt, &c.Referrer, &c.UserAgent, &c.IPHash, &c.IPPrefix, &c.Country); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan row: %w", err)
+		}
+		c.ClickedAt = time.Unix(clickedAt, 0)
+		clicks = append(clicks, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(clicks) == 0 {
+		return 0, nil
+	}
+
+	clickRepo := &clickRepository{sources: r.sources}
+	if err := clickRepo.insertClicks(ctx, r.sources.PrimaryDB, clicks); err != nil {
+		return 0, fmt.Errorf("failed to replay clicks to MySQL: %w", err)
+	}
+
+	args := make([]interface{}, len(clicks))
+	for i, c := range clicks {
+		args[i] = c.ID
+	}
+	query := `DELETE FROM clicks WHERE id IN (?` + strings.Repeat(", ?", len(clicks)-1) + `)`
+	if _, err := r.sources.SQLiteDB.GetDB().ExecContext(ctx, query, args...); err != nil {
+		// 已写入 MySQL，删除失败会导致下次重复回放
+		return len(clicks), fmt.Errorf("failed to delete replayed clicks from SQLite: %w", err)
+	}
+	return len(clicks), nil
+}
+
+// sameShortURL 判断两条数据的内容是否一致（时间精度按毫秒比较，MySQL DATETIME(3) 只保存到毫秒）
+func sameShortURL(a, b *model.ShortURL) bool {
+	if a.LongURL != b.LongURL || a.RedirectCode != b.RedirectCode || a.OwnerID != b.OwnerID {
+		return false
+	}
+	if (a.ExpiresAt == nil) != (b.ExpiresAt == nil) {
+		return false
+	}
+	return a.ExpiresAt == nil || truncateMillis(*a.ExpiresAt).Equal(truncateMillis(*b.ExpiresAt))
+}
+
+func truncateMillis(t time.Time) time.Time {
+	return t.Truncate(time.Millisecond)
+}
+
+func nullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
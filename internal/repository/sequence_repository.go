@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+)
+
+// SequenceRepository 基于数据库表 code_sequences 的自增序列
+type SequenceRepository interface {
+	// NextBlock 原子地为序列分配 size 个连续值，返回区间起点 start，
+	// 调用方可使用 [start, start+size) 内的全部值
+	NextBlock(ctx context.Context, name string, size int64) (int64, error)
+}
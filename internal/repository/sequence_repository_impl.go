@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/username/shorturl/internal/db"
+)
+
+// sequenceRepository 实现 SequenceRepository 接口
+type sequenceRepository struct {
+	sources *DataSources
+}
+
+// NewSequenceRepository 创建新的序列 Repository
+func NewSequenceRepository(sources *DataSources) SequenceRepository {
+	return &sequenceRepository{
+		sources: sources,
+	}
+}
+
+// NextBlock 优先使用主数据库，不可用时使用 SQLite
+// 注意：SQLite 序列仅在单实例内唯一，跨实例的冲突由短码唯一索引兜底
+func (r *sequenceRepository) NextBlock(ctx context.Context, name string, size int64) (int64, error) {
+	if size <= 0 {
+		return 0, fmt.Errorf("invalid sequence block size: %d", size)
+	}
+
+	var err error
+	if r.sources.PrimaryDB != nil {
+		var start int64
+		if start, err = r.nextBlock(ctx, r.sources.PrimaryDB, name, size); err == nil {
+			return start, nil
+		}
+	}
+	if r.sources.SQLiteDB != nil {
+		return r.nextBlock(ctx, r.sources.SQLiteDB, name, size)
+	}
+	if err == nil {
+		err = errors.New("no database available")
+	}
+	return 0, fmt.Errorf("failed to allocate sequence %s: %w", name, err)
+}
+
+func (r *sequenceRepository) nextBlock(ctx context.Context, database db.Database, name string, size int64) (int64, error) {
+	tx, err := database.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	dialect := database.Dialect()
+	// UPDATE 会对该行加锁，直到事务提交，保证分配区间不重叠
+	res, err := tx.ExecContext(ctx, db.Rebind(dialect, `UPDATE code_sequences SET value = value + ? WHERE name = ?`), size, name)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		// 序列不存在时初始化；并发初始化时改为累加。不能先插入再处理唯一键冲突：PostgreSQL 中语句失败后整个事务不可再用
+		query := `INSERT INTO code_sequences (name, value) VALUES (?, ?) ` +
+			db.OnConflictUpdate(dialect, "name") + ` value = code_sequences.value + ` + db.Excluded(dialect, "value")
+		if _, err := tx.ExecContext(ctx, db.Rebind(dialect, query), name, size); err != nil {
+			return 0, err
+		}
+	}
+
+	var end int64
+	err = tx.QueryRowContext(ctx, db.Rebind(dialect, `SELECT value FROM code_sequences WHERE name = ?`), name).Scan(&end)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("sequence %s not initialized", name)
+		}
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	// value 记录已分配的最大值，本次分配区间为 (end-size, end]
+	return end - size + 1, nil
+}
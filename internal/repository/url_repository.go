@@ -2,13 +2,32 @@ package repository
 
 import (
 	"context"
+	"errors"
 
-	"github.com/username/shorturl/internal/model"
+	"github.com/username/shorturl/internal/db/model"
 )
 
+var (
+	// ErrNotFound 所有数据源中都不存在该短码
+	ErrNotFound = errors.New("short code not found")
+	// ErrExpired 短码存在但已过期
+	ErrExpired = errors.New("short code expired")
+	// ErrAlreadyExists 短码已被占用
+	ErrAlreadyExists = errors.New("short code already exists")
+	// ErrNotOwner 短码存在但属于其他用户
+	ErrNotOwner = errors.New("short code owned by another user")
+)
+
+// URLRepository 短链接的存储，按短码读写的方法只作用于所在的工作区
+// NewURLRepository 返回默认工作区的 Repository，其他工作区通过 InWorkspace 获取
 type URLRepository interface {
-	// Get 从多个数据源并发获取，谁先返回就用谁的
-	// 优先级：RedisCache > MemoryCache > MySQLDB > SQLiteDB
+	// InWorkspace 返回操作指定工作区的 Repository，不同工作区的短码、数据库记录和缓存互相隔离
+	InWorkspace(workspaceID int64) URLRepository
+
+	// Get 按 DataSources.Read 配置的策略查询 Memory、Redis、MySQL、SQLite，默认依次查询
+	// Bloom filter 判定一定不存在或命中负缓存时返回 ErrNotFound，不再查询数据库
+	// 过期数据在所有数据源中都视为未命中，仅存在过期数据时返回 ErrExpired
+	// 同一短码的并发查询会合并为一次，调用方 ctx 取消时只结束自己的等待
 	Get(ctx context.Context, shortCode string) (*model.ShortURL, error)
 
 	// Save 保存到多个数据源
@@ -16,7 +35,34 @@ type URLRepository interface {
 	// 数据库：优先写入 MySQL，如果失败则写入 SQLite
 	Save(ctx context.Context, url *model.ShortURL) error
 
-	GetAll(ctx context.Context, pattern string) (*[]model.ShortURL, error)
+	// Create 仅插入新短链，不会覆盖已有数据
+	// 短码已存在于任一数据源时返回 ErrAlreadyExists
+	Create(ctx context.Context, url *model.ShortURL) error
+
+	// CreateBatch 批量插入新短链，数据库使用多行 INSERT，Redis 使用 pipeline
+	// 返回与 urls 一一对应的错误，冲突的短码为 ErrAlreadyExists
+	CreateBatch(ctx context.Context, urls []*model.ShortURL) []error
+
+	// CreateCached 写后模式下创建短链：缓存或数据库中已被占用时返回 ErrAlreadyExists，否则只写入缓存
+	CreateCached(ctx context.Context, url *model.ShortURL) error
+
+	// InsertBatch 只把数据批量写入数据库（MySQL 失败时写入 SQLite），不写缓存
+	// 返回与 urls 一一对应的错误，冲突的短码为 ErrAlreadyExists
+	InsertBatch(ctx context.Context, urls []*model.ShortURL) []error
+
+	// List 按游标分页查询短链接，只读主数据库（MySQL，未配置时为 SQLite），不经过缓存
+	// 返回最多 PageSize 条数据，hasMore 表示是否还有下一页；q.OwnerID 非 0 时只返回该用户的短链接
+	List(ctx context.Context, q model.ListQuery) (urls []model.ShortURL, hasMore bool, err error)
+
+	// Update 在所有数据库中更新短链接，并清除 Redis 和 Memory 中的缓存
+	// ownerID 非 0 时只更新该用户的短链接，属于其他用户时返回 ErrNotOwner
+	// 任一数据库中都不存在该短码时返回 ErrNotFound，返回更新后的数据
+	Update(ctx context.Context, shortCode string, ownerID int64, update model.ShortURLUpdate) (*model.ShortURL, error)
+
+	// Delete 从所有数据库和缓存中删除短链接
+	// ownerID 非 0 时只删除该用户的短链接，属于其他用户时返回 ErrNotOwner
+	// 任一数据库中都不存在该短码时返回 ErrNotFound
+	Delete(ctx context.Context, shortCode string, ownerID int64) error
 
 	// 以下方法保持向后兼容
 	SaveToCache(ctx context.Context, url *model.ShortURL) error
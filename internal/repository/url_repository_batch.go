@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/username/shorturl/internal/cache"
+	"github.com/username/shorturl/internal/db"
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// batchInsertSize 单条 INSERT 语句写入的最大行数
+const batchInsertSize = 500
+
+// CreateBatch 批量插入新短链，返回与 urls 一一对应的错误，nil 表示该条写入成功
+// 批内重复或缓存中已存在的短码直接判为冲突；其余按 batchInsertSize 分组，
+// 先查出数据库中已存在的短码，剩余的用一条多行 INSERT 写入，MySQL 失败（非冲突）时该组改写入 SQLite；
+// 写入成功的数据通过 pipeline 批量写入 Redis，失败时写入 Memory
+// 多行插入无法可靠取得每一行的自增 ID，返回的数据 ID 为 0
+func (r *urlRepository) CreateBatch(ctx context.Context, urls []*model.ShortURL) []error {
+	errs := make([]error, len(urls))
+
+	// 1. 批内重复与缓存检查
+	keys := make([]string, len(urls))
+	seen := make(map[string]bool, len(urls))
+	for i, u := range urls {
+		u.WorkspaceID = r.workspaceID
+		keys[i] = cacheKey(u.WorkspaceID, u.ShortCode)
+		if seen[u.ShortCode] {
+			errs[i] = fmt.Errorf("%w: %s", ErrAlreadyExists, u.ShortCode)
+		}
+		seen[u.ShortCode] = true
+	}
+	for _, c := range []cache.Cache{r.sources.RedisCache, r.sources.MemoryCache} {
+		if c == nil {
+			continue
+		}
+		exists, err := cache.ExistsMulti(ctx, c, keys)
+		if err != nil {
+			continue
+		}
+		for i, ok := range exists {
+			if ok && errs[i] == nil {
+				errs[i] = fmt.Errorf("%w: %s", ErrAlreadyExists, urls[i].ShortCode)
+			}
+		}
+	}
+
+	// 2. 分组写入数据库
+	r.insertBatch(ctx, urls, errs)
+
+	// 3. 批量写入缓存，数据库已写入成功，缓存失败只记录日志，读取时会从数据库回填
+	created := succeeded(urls, errs)
+	r.clearNegatives(ctx, created)
+	if err := r.saveManyToCache(ctx, created); err != nil {
+		log.Printf("failed to save %d short URLs to cache: %v", len(created), err)
+	}
+	return errs
+}
+
+// InsertBatch 只把数据写入数据库，用于写后模式下落库；写入成功的短码会清除负缓存
+// 每条数据写入自己的 WorkspaceID 所在的工作区，一批中可以包含多个工作区的数据
+func (r *urlRepository) InsertBatch(ctx context.Context, urls []*model.ShortURL) []error {
+	errs := make([]error, len(urls))
+	seen := make(map[string]bool, len(urls))
+	for i, u := range urls {
+		code := namespacedCode(u.WorkspaceID, u.ShortCode)
+		if seen[code] {
+			errs[i] = fmt.Errorf("%w: %s", ErrAlreadyExists, u.ShortCode)
+		}
+		seen[code] = true
+	}
+	r.insertBatch(ctx, urls, errs)
+	r.clearNegatives(ctx, succeeded(urls, errs))
+	return errs
+}
+
+// CreateCached 写后模式下创建短链：确认缓存和数据库中都没有该短码后只写入缓存
+// 数据库由调用方稍后通过 InsertBatch 写入；检查之后并发写入的同名短码要到那时才能发现
+func (r *urlRepository) CreateCached(ctx context.Context, url *model.ShortURL) error {
+	url.WorkspaceID = r.workspaceID
+	key := cacheKey(url.WorkspaceID, url.ShortCode)
+	for _, c := range []cache.Cache{r.sources.RedisCache, r.sources.MemoryCache} {
+		if c == nil {
+			continue
+		}
+		if exists, err := c.Exists(ctx, key); err == nil && exists {
+			return fmt.Errorf("%w: %s", ErrAlreadyExists, url.ShortCode)
+		}
+	}
+	// 数据库中已存在但未被缓存的短码不能只看缓存，否则会覆盖现有短链接的跳转地址直到落库失败
+	exists, err := r.existsInDatabases(ctx, url.ShortCode)
+	if err != nil {
+		return fmt.Errorf("failed to check short code: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", ErrAlreadyExists, url.ShortCode)
+	}
+
+	// 缓存是写入成功前唯一的数据来源，Redis 和 Memory 都失败时返回错误
+	err = errors.New("no cache available")
+	if r.sources.RedisCache != nil {
+		err = r.saveToRedis(ctx, url)
+	}
+	if err != nil && r.sources.MemoryCache != nil {
+		err = r.saveToMemory(ctx, url)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save to cache: %w", err)
+	}
+	r.clearNegative(ctx, url.ShortCode)
+	return nil
+}
+
+// existsInDatabases 短码是否已存在于主数据库或 SQLite（SQLite 中可能有主数据库故障期间写入的数据）
+// Bloom filter 判定一定不存在时不查询数据库；两个数据库都无法查询时返回错误
+func (r *urlRepository) existsInDatabases(ctx context.Context, shortCode string) (bool, error) {
+	if !r.sources.KnownCodes.MayContain(namespacedCode(r.workspaceID, shortCode)) {
+		return false, nil
+	}
+	err := errors.New("no database available")
+	checked := false
+	for _, database := range []db.Database{r.sources.PrimaryDB, r.sources.SQLiteDB} {
+		if database == nil {
+			continue
+		}
+		var one int
+		queryErr := database.GetDB().QueryRowContext(ctx, db.Rebind(database.Dialect(),
+			`SELECT 1 FROM short_urls WHERE workspace_id = ? AND short_code = ?`), r.workspaceID, shortCode).Scan(&one)
+		switch {
+		case queryErr == nil:
+			return true, nil
+		case errors.Is(queryErr, sql.ErrNoRows):
+			checked = true
+		default:
+			err = queryErr
+		}
+	}
+	if !checked {
+		return false, err
+	}
+	return false, nil
+}
+
+// insertBatch 将 errs 中尚未出错的数据按 batchInsertSize 分组写入数据库，结果记录到 errs
+func (r *urlRepository) insertBatch(ctx context.Context, urls []*model.ShortURL, errs []error) {
+	var pending []int
+	for i := range urls {
+		if errs[i] == nil {
+			pending = append(pending, i)
+		}
+	}
+	for start := 0; start < len(pending); start += batchInsertSize {
+		chunk := pending[start:min(start+batchInsertSize, len(pending))]
+
+		failed, err := chunk, errors.New("no database available")
+		if r.sources.PrimaryDB != nil {
+			failed, err = r.insertChunk(ctx, r.sources.PrimaryDB, urls, chunk, errs)
+			if err != nil && r.sources.SQLiteDB != nil {
+				failed, err = r.insertChunk(ctx, r.sources.SQLiteDB, urls, failed, errs)
+			}
+		} else if r.sources.SQLiteDB != nil {
+			failed, err = r.insertChunk(ctx, r.sources.SQLiteDB, urls, chunk, errs)
+		}
+		if err != nil {
+			for _, i := range failed {
+				if errs[i] == nil {
+					errs[i] = fmt.Errorf("failed to save to database: %w", err)
+				}
+			}
+		}
+	}
+}
+
+// succeeded 返回 errs 中没有出错的数据
+func succeeded(urls []*model.ShortURL, errs []error) []*model.ShortURL {
+	var result []*model.ShortURL
+	for i, u := range urls {
+		if errs[i] == nil {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// insertChunk 将 chunk 中尚未出错的数据写入指定数据库，冲突的短码记录到 errs
+// 返回因数据库出错（而不是短码冲突）没有写入的数据和错误，调用方可以把这些数据改写其他数据库
+func (r *urlRepository) insertChunk(ctx context.Context, database db.Database, urls []*model.ShortURL, chunk []int, errs []error) ([]int, error) {
+	var todo []int
+	for _, i := range chunk {
+		if errs[i] == nil {
+			todo = append(todo, i)
+		}
+	}
+	if len(todo) == 0 {
+		return nil, nil
+	}
+
+	// 预先查出已存在的短码，避免整条 INSERT 因个别冲突失败
+	existing, err := existingShortCodes(ctx, database, urls, todo)
+	if err != nil {
+		return todo, err
+	}
+	var rows []int
+	for _, i := range todo {
+		if existing[namespacedCode(urls[i].WorkspaceID, urls[i].ShortCode)] {
+			errs[i] = fmt.Errorf("%w: %s", ErrAlreadyExists, urls[i].ShortCode)
+		} else {
+			rows = append(rows, i)
+		}
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	err = insertManyToDB(ctx, database, urls, rows)
+	if err == nil {
+		return nil, nil
+	}
+	if !db.IsDuplicateKeyError(err) {
+		return rows, err
+	}
+
+	// 查询与插入之间有并发写入，逐条插入以确定具体是哪些短码冲突
+	var failed []int
+	var failedErr error
+	for _, i := range rows {
+		if err := r.insertToDB(ctx, database, urls[i]); err != nil {
+			if db.IsDuplicateKeyError(err) {
+				errs[i] = fmt.Errorf("%w: %s", ErrAlreadyExists, urls[i].ShortCode)
+			} else {
+				failed, failedErr = append(failed, i), err
+			}
+		}
+	}
+	return failed, failedErr
+}
+
+// existingShortCodes 查询 rows 对应的短码中已存在于数据库的部分，返回 namespacedCode 的集合
+func existingShortCodes(ctx context.Context, database db.Database, urls []*model.ShortURL, rows []int) (map[string]bool, error) {
+	args := make([]interface{}, len(rows))
+	for n, i := range rows {
+		args[n] = urls[i].ShortCode
+	}
+	// 按短码查询后在内存中匹配工作区，同一批可能包含多个工作区的数据
+	query := `SELECT workspace_id, short_code FROM short_urls WHERE short_code IN (?` + strings.Repeat(", ?", len(rows)-1) + `)`
+
+	result, err := database.GetDB().QueryContext(ctx, db.Rebind(database.Dialect(), query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	existing := make(map[string]bool)
+	for result.Next() {
+		var workspaceID int64
+		var code string
+		if err := result.Scan(&workspaceID, &code); err != nil {
+			return nil, err
+		}
+		existing[namespacedCode(workspaceID, code)] = true
+	}
+	return existing, result.Err()
+}
+
+// insertManyToDB 用一条多行 INSERT 写入，任一行冲突时整条语句失败
+func insertManyToDB(ctx context.Context, database db.Database, urls []*model.ShortURL, rows []int) error {
+	query := `INSERT INTO short_urls (short_code, long_url, redirect_code, created_at, expires_at, owner_id, workspace_id) VALUES (?, ?, ?, ?, ?, ?, ?)` +
+		strings.Repeat(", (?, ?, ?, ?, ?, ?, ?)", len(rows)-1)
+
+	args := make([]interface{}, 0, len(rows)*7)
+	for _, i := range rows {
+		u := urls[i]
+		var expiresAt interface{}
+		if u.ExpiresAt != nil {
+			expiresAt = *u.ExpiresAt
+		}
+		args = append(args, u.ShortCode, u.LongURL, u.RedirectCode, u.CreatedAt, expiresAt, u.OwnerID, u.WorkspaceID)
+	}
+
+	_, err := database.GetDB().ExecContext(ctx, db.Rebind(database.Dialect(), query), args...)
+	return err
+}
+
+// saveManyToCache 批量写入缓存：Redis 优先（pipeline），失败时写入 Memory
+func (r *urlRepository) saveManyToCache(ctx context.Context, urls []*model.ShortURL) error {
+	if len(urls) == 0 {
+		return nil
+	}
+	entries := make([]cache.Entry, 0, len(urls))
+	for _, u := range urls {
+		data, err := json.Marshal(u)
+		if err != nil {
+			return err
+		}
+		var expiration time.Duration
+		if u.ExpiresAt != nil {
+			expiration = time.Until(*u.ExpiresAt)
+			if expiration <= 0 {
+				continue
+			}
+		}
+		entries = append(entries, cache.Entry{Key: cacheKey(u.WorkspaceID, u.ShortCode), Value: string(data), Expiration: expiration})
+	}
+
+	if r.sources.RedisCache != nil {
+		if err := cache.SetMulti(ctx, r.sources.RedisCache, entries); err == nil {
+			return nil
+		}
+	}
+	if r.sources.MemoryCache != nil {
+		return cache.SetMulti(ctx, r.sources.MemoryCache, entries)
+	}
+	return nil
+}
@@ -7,265 +7,60 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
-	"github.com/username/shorturl/internal/model"
+	"github.com/username/shorturl/internal/cache"
+	"github.com/username/shorturl/internal/db"
+	"github.com/username/shorturl/internal/db/model"
 )
 
 // urlRepository 实现 URLRepository 接口
 type urlRepository struct {
 	sources *DataSources
+	// workspaceID 按短码读写的方法所在的工作区
+	workspaceID int64
 }
 
-// NewURLRepository 创建新的 URL Repository
+// NewURLRepository 创建新的 URL Repository，操作默认工作区
 func NewURLRepository(sources *DataSources) URLRepository {
 	return &urlRepository{
 		sources: sources,
 	}
 }
 
-// Get 从多个数据源并发获取，谁先返回就用谁的
-// 优先级：RedisCache > MemoryCache > MySQLDB > SQLiteDB
-func (r *urlRepository) Get(ctx context.Context, shortCode string) (*model.ShortURL, error) {
-	type result struct {
-		url *model.ShortURL
-		err error
-	}
-
-	resultCh := make(chan result, 1) // 只需要第一个结果
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
-	var wg sync.WaitGroup
-	var once sync.Once
-
-	// 辅助函数：发送结果并取消其他 goroutine
-	sendResult := func(url *model.ShortURL, err error) {
-		once.Do(func() {
-			select {
-			case resultCh <- result{url: url, err: err}:
-				cancel() // 取消其他 goroutine
-			case <-ctx.Done():
-			}
-		})
-	}
-
-	// 从 Redis 获取
-	if r.sources.RedisCache != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			url, err := r.getFromRedis(ctx, shortCode)
-			if err == nil && url != nil {
-				sendResult(url, nil)
-			}
-		}()
-	}
-
-	// 从 Memory 获取
-	if r.sources.MemoryCache != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			url, err := r.getFromMemory(ctx, shortCode)
-			if err == nil && url != nil {
-				sendResult(url, nil)
-			}
-		}()
-	}
-
-	// 从 MySQL 获取
-	if r.sources.MySQLDB != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			url, err := r.getFromMySQL(ctx, shortCode)
-			if err == nil && url != nil {
-				sendResult(url, nil)
-			}
-		}()
-	}
-
-	// 从 SQLite 获取
-	if r.sources.SQLiteDB != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			url, err := r.getFromSQLite(ctx, shortCode)
-			if err == nil && url != nil {
-				sendResult(url, nil)
-			}
-		}()
-	}
-
-	// 等待第一个结果或所有 goroutine 完成
-	doneCh := make(chan struct{})
-	go func() {
-		wg.Wait()
-		close(doneCh)
-	}()
-
-	// 等待第一个结果
-	select {
-	case res := <-resultCh:
-		if res.err != nil {
-			return nil, res.err
-		}
-		if res.url != nil {
-			// 异步回写缓存（如果从数据库获取的）
-			go r.asyncWriteToCache(context.Background(), res.url)
-			return res.url, nil
-		}
-		// 如果收到 nil，继续等待其他结果
-		// 这种情况理论上不应该发生，因为只有 url != nil 时才发送
-		// 但为了安全，我们继续等待
-		select {
-		case res := <-resultCh:
-			if res.url != nil {
-				go r.asyncWriteToCache(context.Background(), res.url)
-				return res.url, nil
-			}
-			// 继续等待所有完成
-			<-doneCh
-			return nil, fmt.Errorf("short code not found: %s", shortCode)
-		case <-doneCh:
-			return nil, fmt.Errorf("short code not found: %s", shortCode)
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		}
-	case <-doneCh:
-		// 所有 goroutine 都完成了，但没有找到结果
-		return nil, fmt.Errorf("short code not found: %s", shortCode)
-	case <-ctx.Done():
-		// 外部 context 被取消
-		wg.Wait()
-		return nil, ctx.Err()
-	}
+// InWorkspace 返回操作指定工作区的 Repository
+func (r *urlRepository) InWorkspace(workspaceID int64) URLRepository {
+	return &urlRepository{sources: r.sources, workspaceID: workspaceID}
 }
 
-// 优先级：RedisCache > MemoryCache > MySQLDB > SQLiteDB
-func (r *urlRepository) GetAll(ctx context.Context, pattern string) (*[](model.ShortURL), error) {
-	type result struct {
-		allLinks *[]model.ShortURL
-		err      error
-	}
-
-	resultCh := make(chan result, 1) // 只需要第一个结果
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
-	var wg sync.WaitGroup
-	var once sync.Once
-
-	// 辅助函数：发送结果并取消其他 goroutine
-	sendResult := func(allLinks *[]model.ShortURL, err error) {
-		once.Do(func() {
-			select {
-			case resultCh <- result{allLinks: allLinks, err: err}:
-				cancel() // 取消其他 goroutine
-			case <-ctx.Done():
-			}
-		})
-	}
-
-	// 从 Redis 获取
-	if r.sources.RedisCache != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			url, err := r.getAllForRedis(ctx, pattern)
-			log.Println("redis return", url)
-			if err == nil && url != nil {
-				sendResult(url, nil)
-			}
-		}()
-	}
+// getCalls 进程内共享，合并对同一短码的并发查询（urlRepository 每次请求都会重新创建）
+var getCalls = newCoalescer()
 
-	// 从 Memory 获取
-	if r.sources.MemoryCache != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			url, err := r.getAllForMemory(ctx, pattern)
-			if err == nil && url != nil {
-				sendResult(url, nil)
-			}
-		}()
-	}
-
-	// 从 MySQL 获取
-	if r.sources.MySQLDB != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			url, err := r.getAllForMysql(ctx)
-			if err == nil && url != nil {
-				sendResult(url, nil)
-			}
-		}()
-	}
-
-	// 从 SQLite 获取
-	if r.sources.SQLiteDB != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			url, err := r.getAllForSqlite(ctx)
-			if err == nil && url != nil {
-				sendResult(url, nil)
-			}
-		}()
-	}
-
-	// 等待第一个结果或所有 goroutine 完成
-	doneCh := make(chan struct{})
-	go func() {
-		wg.Wait()
-		close(doneCh)
-	}()
-
-	// 等待第一个结果
-	select {
-	case res := <-resultCh:
-		log.Println(res)
-		if res.err != nil {
-			return nil, res.err
-		}
-		if res.allLinks != nil {
-			return res.allLinks, nil
-		}
+// Get 合并同一短码的并发查询，由其中一次 fetch 访问数据源，其余调用方共享结果
+func (r *urlRepository) Get(ctx context.Context, shortCode string) (*model.ShortURL, error) {
+	// 不同的 DataSources 各自合并
+	key := fmt.Sprintf("%p:%s", r.sources, namespacedCode(r.workspaceID, shortCode))
+	url, err, _ := getCalls.do(ctx, key, func(ctx context.Context) (*model.ShortURL, error) {
+		return r.fetch(ctx, shortCode)
+	})
+	return url, err
+}
 
-		// 如果收到 nil，继续等待其他结果
-		// 这种情况理论上不应该发生，因为只有 url != nil 时才发送
-		// 但为了安全，我们继续等待
-		select {
-		case res := <-resultCh:
-			if res.allLinks != nil {
-				return res.allLinks, nil
-			}
-			// 继续等待所有完成
-			<-doneCh
-			return nil, fmt.Errorf("short code not found: short1")
-		case <-doneCh:
-			return nil, fmt.Errorf("short code not found: short2")
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		}
-	case <-doneCh:
-		// 所有 goroutine 都完成了，但没有找到结果
-		return nil, fmt.Errorf("short code not found: short3")
-	case <-ctx.Done():
-		// 外部 context 被取消
-		wg.Wait()
-		return nil, ctx.Err()
+// missError 所有数据源都未命中时返回的错误
+func missError(shortCode string, expired bool) error {
+	if expired {
+		return fmt.Errorf("%w: %s", ErrExpired, shortCode)
 	}
+	return fmt.Errorf("%w: %s", ErrNotFound, shortCode)
 }
 
 // Save 保存到多个数据源
 // 缓存：优先写入 Redis，如果失败则写入 Memory
 // 数据库：优先写入 MySQL，如果失败则写入 SQLite
 func (r *urlRepository) Save(ctx context.Context, url *model.ShortURL) error {
+	url.WorkspaceID = r.workspaceID
 	var wg sync.WaitGroup
 	errCh := make(chan error, 4)
 
@@ -294,18 +89,18 @@ func (r *urlRepository) Save(ctx context.Context, url *model.ShortURL) error {
 	}
 
 	// 保存到数据库
-	if r.sources.MySQLDB != nil {
+	if r.sources.PrimaryDB != nil {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			if err := r.saveToMySQL(ctx, url); err != nil {
-				// MySQL 失败，尝试 SQLite
+			if err := r.upsertToDB(ctx, r.sources.PrimaryDB, url); err != nil {
+				// 主数据库失败，尝试 SQLite
 				if r.sources.SQLiteDB != nil {
-					if err := r.saveToSQLite(ctx, url); err != nil {
+					if err := r.upsertToDB(ctx, r.sources.SQLiteDB, url); err != nil {
 						errCh <- fmt.Errorf("failed to save to database: %w", err)
 					}
 				} else {
-					errCh <- fmt.Errorf("failed to save to MySQL: %w", err)
+					errCh <- fmt.Errorf("failed to save to primary database: %w", err)
 				}
 			}
 		}()
@@ -313,7 +108,7 @@ func (r *urlRepository) Save(ctx context.Context, url *model.ShortURL) error {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			if err := r.saveToSQLite(ctx, url); err != nil {
+			if err := r.upsertToDB(ctx, r.sources.SQLiteDB, url); err != nil {
 				errCh <- fmt.Errorf("failed to save to SQLite: %w", err)
 			}
 		}()
@@ -321,6 +116,7 @@ func (r *urlRepository) Save(ctx context.Context, url *model.ShortURL) error {
 
 	wg.Wait()
 	close(errCh)
+	r.clearNegative(ctx, url.ShortCode)
 
 	// 收集错误
 	var errs []error
@@ -335,152 +131,189 @@ func (r *urlRepository) Save(ctx context.Context, url *model.ShortURL) error {
 	return nil
 }
 
-// 从各个数据源获取的辅助方法
-func (r *urlRepository) getFromRedis(ctx context.Context, shortCode string) (*model.ShortURL, error) {
-	key := "shorturl:" + shortCode
-	val, err := r.sources.RedisCache.Get(ctx, key)
-	if err != nil || val == nil {
-		return nil, err
+// Create 仅插入新短链，不会覆盖已有数据
+// 先检查缓存中是否已存在，再以 INSERT 写入数据库（依赖 short_code 唯一索引判重），
+// 数据库写入成功后才写入缓存
+func (r *urlRepository) Create(ctx context.Context, url *model.ShortURL) error {
+	url.WorkspaceID = r.workspaceID
+	key := cacheKey(url.WorkspaceID, url.ShortCode)
+	for _, c := range []cache.Cache{r.sources.RedisCache, r.sources.MemoryCache} {
+		if c == nil {
+			continue
+		}
+		if exists, err := c.Exists(ctx, key); err == nil && exists {
+			return fmt.Errorf("%w: %s", ErrAlreadyExists, url.ShortCode)
+		}
 	}
 
-	var url model.ShortURL
-	data, ok := val.(string)
-	if !ok {
-		return nil, fmt.Errorf("invalid cache value type")
+	// 保存到数据库：MySQL 优先，失败（非冲突）时写入 SQLite
+	var err error
+	if r.sources.PrimaryDB != nil {
+		err = r.insertToDB(ctx, r.sources.PrimaryDB, url)
+		if err != nil && !db.IsDuplicateKeyError(err) && r.sources.SQLiteDB != nil {
+			err = r.insertToDB(ctx, r.sources.SQLiteDB, url)
+		}
+	} else if r.sources.SQLiteDB != nil {
+		err = r.insertToDB(ctx, r.sources.SQLiteDB, url)
 	}
-	if err := json.Unmarshal([]byte(data), &url); err != nil {
-		return nil, err
+	if err != nil {
+		if db.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %s", ErrAlreadyExists, url.ShortCode)
+		}
+		return fmt.Errorf("failed to save to database: %w", err)
 	}
-	return &url, nil
-}
+	r.clearNegative(ctx, url.ShortCode)
 
-func (r *urlRepository) getAllForRedis(ctx context.Context, pattern string) (*[]model.ShortURL, error) {
-	redisResult, err := r.sources.RedisCache.GetAll(ctx, pattern)
-	log.Println(err)
-	if err != nil || redisResult == nil {
-		return nil, err
+	// 保存到缓存：Redis 优先，失败时写入 Memory
+	if r.sources.RedisCache != nil {
+		if err := r.saveToRedis(ctx, url); err == nil {
+			return nil
+		}
 	}
-	log.Printf("val = %v", redisResult)
-	var result []model.ShortURL
-	bresutl := redisResult.(map[string]interface{})
-	for key, value := range bresutl {
-		result = append(result, model.ShortURL{
-			ShortCode: key,
-			LongURL:   value.(string),
-		})
+	if r.sources.MemoryCache != nil {
+		if err := r.saveToMemory(ctx, url); err != nil {
+			return fmt.Errorf("failed to save to memory cache: %w", err)
+		}
 	}
-	return &result, nil
+	return nil
 }
 
-func (r *urlRepository) getAllForMemory[T any](ctx context.Context, pattern string) (*[]model.ShortURL, error) {
-	val, err := r.sources.MemoryCache.GetAll(ctx, pattern)
-	if err != nil || val == nil {
-		return nil, err
+// Update 在 MySQL 和 SQLite 中都执行更新（SQLite 中可能有 MySQL 故障期间写入的数据），
+// 然后清除所有缓存，下次读取时从数据库回填
+func (r *urlRepository) Update(ctx context.Context, shortCode string, ownerID int64, update model.ShortURLUpdate) (*model.ShortURL, error) {
+	var sets []string
+	var args []interface{}
+	if update.LongURL != nil {
+		sets = append(sets, "long_url = ?")
+		args = append(args, *update.LongURL)
 	}
-
-	var result []model.ShortURL
-	data, ok := val.(string)
-	if !ok {
-		return nil, fmt.Errorf("invalid cache value type")
+	if update.RedirectCode != nil {
+		sets = append(sets, "redirect_code = ?")
+		args = append(args, *update.RedirectCode)
 	}
-	if err := json.Unmarshal([]byte(data), &result); err != nil {
-		return nil, err
+	if update.ClearExpiresAt {
+		sets = append(sets, "expires_at = NULL")
+	} else if update.ExpiresAt != nil {
+		sets = append(sets, "expires_at = ?")
+		args = append(args, *update.ExpiresAt)
+	}
+	if len(sets) == 0 {
+		return nil, fmt.Errorf("no fields to update")
+	}
+	query := `UPDATE short_urls SET ` + strings.Join(sets, ", ") + ` WHERE workspace_id = ? AND short_code = ?`
+	args = append(args, r.workspaceID, shortCode)
+	if ownerID != 0 {
+		query += ` AND owner_id = ?`
+		args = append(args, ownerID)
 	}
-	return &result, nil
-}
 
-func (r *urlRepository) getAllForMysql(ctx context.Context) (*[]model.ShortURL, error) {
-	db := r.sources.MySQLDB.GetDB()
+	affected, err := r.execOnAllDBs(ctx, query, args...)
 
-	query := `SELECT id, short_code, long_url, created_at, expires_at FROM short_urls`
-	var args []interface{}
-	// 添加 context 支持（如果 db 支持）
-	rows, err := db.Query(query, args...)
+	// 无论数据库是否全部成功，都清除缓存，避免继续返回旧的目标地址
+	if cacheErr := r.DeleteFromCache(ctx, shortCode); cacheErr != nil {
+		log.Printf("failed to invalidate cache for %s: %v", shortCode, cacheErr)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to query short URLs: %w", err)
-	}
-	defer rows.Close()
-
-	var urls []model.ShortURL
-	for rows.Next() {
-		var url model.ShortURL
-		var expiresAt sql.NullTime // 用于安全读取可能为 NULL 的时间字段
-
-		err := rows.Scan(
-			&url.ID,
-			&url.ShortCode,
-			&url.LongURL,
-			&url.CreatedAt,
-			&expiresAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
-		}
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, r.notFoundOrNotOwner(ctx, shortCode, ownerID)
+	}
 
-		if expiresAt.Valid {
-			url.ExpiresAt = &expiresAt.Time
-		} else {
-			url.ExpiresAt = nil
+	// 读回更新后的数据（包含已过期的数据）
+	for _, get := range []func(context.Context, string) (*model.ShortURL, error){r.getFromPrimaryIfPresent, r.getFromSQLiteIfPresent} {
+		if url, err := get(ctx, shortCode); err == nil && url != nil {
+			return url, nil
 		}
-
-		urls = append(urls, url)
 	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, shortCode)
+}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration error: %w", err)
+// Delete 从 MySQL、SQLite 以及 Redis、Memory 缓存中删除
+func (r *urlRepository) Delete(ctx context.Context, shortCode string, ownerID int64) error {
+	query := `DELETE FROM short_urls WHERE workspace_id = ? AND short_code = ?`
+	args := []interface{}{r.workspaceID, shortCode}
+	if ownerID != 0 {
+		query += ` AND owner_id = ?`
+		args = append(args, ownerID)
 	}
+	affected, err := r.execOnAllDBs(ctx, query, args...)
 
-	return &urls, nil
+	if cacheErr := r.DeleteFromCache(ctx, shortCode); cacheErr != nil {
+		log.Printf("failed to invalidate cache for %s: %v", shortCode, cacheErr)
+	}
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return r.notFoundOrNotOwner(ctx, shortCode, ownerID)
+	}
+	// Bloom filter 无法删除，由负缓存拦截对已删除短码的查询
+	if r.sources.NegativeTTL > 0 {
+		r.saveNegative(ctx, shortCode)
+	}
+	return nil
 }
 
-func (r *urlRepository) getAllForSqlite(ctx context.Context) (*[]model.ShortURL, error) {
-	db := r.sources.MySQLDB.GetDB()
-
-	query := `SELECT id, short_code, long_url, created_at, expires_at FROM short_urls`
-	var args []interface{}
+// notFoundOrNotOwner 按所有者更新或删除没有影响任何行时，区分短码不存在与属于其他用户
+func (r *urlRepository) notFoundOrNotOwner(ctx context.Context, shortCode string, ownerID int64) error {
+	if ownerID != 0 {
+		for _, get := range []func(context.Context, string) (*model.ShortURL, error){r.getFromPrimaryIfPresent, r.getFromSQLiteIfPresent} {
+			if url, err := get(ctx, shortCode); err == nil && url != nil {
+				return fmt.Errorf("%w: %s", ErrNotOwner, shortCode)
+			}
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrNotFound, shortCode)
+}
 
-	// 添加 context 支持（如果 db 支持）
-	rows, err := db.Query(query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query short URLs: %w", err)
-	}
-	defer rows.Close()
-
-	var urls []model.ShortURL
-	for rows.Next() {
-		var url model.ShortURL
-		var expiresAt sql.NullTime // 用于安全读取可能为 NULL 的时间字段
-
-		err := rows.Scan(
-			&url.ID,
-			&url.ShortCode,
-			&url.LongURL,
-			&url.CreatedAt,
-			&expiresAt,
-		)
+// execOnAllDBs 在所有可用的数据库上执行同一条语句，返回影响（匹配）的总行数
+// 已在至少一个数据库上生效时视为成功，其他数据库的失败只记录日志：数据只会存在于其中一个数据库，
+// 或者是主数据库故障期间写入 SQLite 的副本；没有生效且有数据库执行失败时无法确定数据是否存在，返回错误
+func (r *urlRepository) execOnAllDBs(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	var total int64
+	var errs []error
+	for _, database := range []db.Database{r.sources.PrimaryDB, r.sources.SQLiteDB} {
+		if database == nil {
+			continue
+		}
+		res, err := database.GetDB().ExecContext(ctx, db.Rebind(database.Dialect(), query), args...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+			errs = append(errs, fmt.Errorf("%s: %w", database.Dialect(), err))
+			continue
 		}
-
-		if expiresAt.Valid {
-			url.ExpiresAt = &expiresAt.Time
-		} else {
-			url.ExpiresAt = nil
+		if n, err := res.RowsAffected(); err == nil {
+			total += n
 		}
-
-		urls = append(urls, url)
 	}
+	if len(errs) == 0 {
+		return total, nil
+	}
+	if total > 0 {
+		log.Printf("statement applied to %d rows, but failed on other databases: %v", total, errs)
+		return total, nil
+	}
+	return 0, fmt.Errorf("failed to update database: %v", errs)
+}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration error: %w", err)
+func (r *urlRepository) getFromPrimaryIfPresent(ctx context.Context, shortCode string) (*model.ShortURL, error) {
+	if r.sources.PrimaryDB == nil {
+		return nil, nil
 	}
+	return r.getFromPrimary(ctx, shortCode)
+}
 
-	return &urls, nil
+func (r *urlRepository) getFromSQLiteIfPresent(ctx context.Context, shortCode string) (*model.ShortURL, error) {
+	if r.sources.SQLiteDB == nil {
+		return nil, nil
+	}
+	return r.getFromSQLite(ctx, shortCode)
 }
 
-func (r *urlRepository) getFromMemory(ctx context.Context, shortCode string) (*model.ShortURL, error) {
-	key := "shorturl:" + shortCode
-	val, err := r.sources.MemoryCache.Get(ctx, key)
+// 从各个数据源获取的辅助方法
+func (r *urlRepository) getFromRedis(ctx context.Context, shortCode string) (*model.ShortURL, error) {
+	key := cacheKey(r.workspaceID, shortCode)
+	val, err := r.sources.RedisCache.Get(ctx, key)
 	if err != nil || val == nil {
 		return nil, err
 	}
@@ -496,37 +329,39 @@ func (r *urlRepository) getFromMemory(ctx context.Context, shortCode string) (*m
 	return &url, nil
 }
 
-func (r *urlRepository) getFromMySQL(ctx context.Context, shortCode string) (*model.ShortURL, error) {
-	db := r.sources.MySQLDB.GetDB()
-	query := `SELECT id, short_code, long_url, created_at, expires_at FROM short_urls WHERE short_code = ?`
-
-	var url model.ShortURL
-	var expiresAt sql.NullTime
-	err := db.QueryRowContext(ctx, query, shortCode).Scan(
-		&url.ID, &url.ShortCode, &url.LongURL, &url.CreatedAt, &expiresAt,
-	)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
+func (r *urlRepository) getFromMemory(ctx context.Context, shortCode string) (*model.ShortURL, error) {
+	key := cacheKey(r.workspaceID, shortCode)
+	val, err := r.sources.MemoryCache.Get(ctx, key)
+	if err != nil || val == nil {
 		return nil, err
 	}
 
-	if expiresAt.Valid {
-		url.ExpiresAt = &expiresAt.Time
+	var url model.ShortURL
+	data, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid cache value type")
+	}
+	if err := json.Unmarshal([]byte(data), &url); err != nil {
+		return nil, err
 	}
-
 	return &url, nil
 }
 
+func (r *urlRepository) getFromPrimary(ctx context.Context, shortCode string) (*model.ShortURL, error) {
+	return r.getFromDB(ctx, r.sources.PrimaryDB, shortCode)
+}
+
 func (r *urlRepository) getFromSQLite(ctx context.Context, shortCode string) (*model.ShortURL, error) {
-	db := r.sources.SQLiteDB.GetDB()
-	query := `SELECT id, short_code, long_url, created_at, expires_at FROM short_urls WHERE short_code = ?`
+	return r.getFromDB(ctx, r.sources.SQLiteDB, shortCode)
+}
+
+func (r *urlRepository) getFromDB(ctx context.Context, database db.Database, shortCode string) (*model.ShortURL, error) {
+	query := db.Rebind(database.Dialect(), `SELECT id, short_code, long_url, redirect_code, created_at, expires_at, owner_id, workspace_id FROM short_urls WHERE workspace_id = ? AND short_code = ?`)
 
 	var url model.ShortURL
 	var expiresAt sql.NullTime
-	err := db.QueryRowContext(ctx, query, shortCode).Scan(
-		&url.ID, &url.ShortCode, &url.LongURL, &url.CreatedAt, &expiresAt,
+	err := database.GetDB().QueryRowContext(ctx, query, r.workspaceID, shortCode).Scan(
+		&url.ID, &url.ShortCode, &url.LongURL, &url.RedirectCode, &url.CreatedAt, &expiresAt, &url.OwnerID, &url.WorkspaceID,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -545,7 +380,7 @@ func (r *urlRepository) getFromSQLite(ctx context.Context, shortCode string) (*m
 // 保存到各个数据源的辅助方法
 
 func (r *urlRepository) saveToRedis(ctx context.Context, url *model.ShortURL) error {
-	key := "shorturl:" + url.ShortCode
+	key := cacheKey(url.WorkspaceID, url.ShortCode)
 	data, err := json.Marshal(url)
 	if err != nil {
 		return err
@@ -563,7 +398,7 @@ func (r *urlRepository) saveToRedis(ctx context.Context, url *model.ShortURL) er
 }
 
 func (r *urlRepository) saveToMemory(ctx context.Context, url *model.ShortURL) error {
-	key := "shorturl:" + url.ShortCode
+	key := cacheKey(url.WorkspaceID, url.ShortCode)
 	data, err := json.Marshal(url)
 	if err != nil {
 		return err
@@ -580,49 +415,49 @@ func (r *urlRepository) saveToMemory(ctx context.Context, url *model.ShortURL) e
 	return r.sources.MemoryCache.Set(ctx, key, string(data), expiration)
 }
 
-func (r *urlRepository) saveToMySQL(ctx context.Context, url *model.ShortURL) error {
-	db := r.sources.MySQLDB.GetDB()
-	query := `INSERT INTO short_urls (short_code, long_url, created_at, expires_at) 
-	          VALUES (?, ?, ?, ?) 
-	          ON DUPLICATE KEY UPDATE long_url = ?, expires_at = ?`
+// upsertToDB 插入或覆盖（short_code 已存在时更新目标地址、状态码和过期时间，所有者不变）
+func (r *urlRepository) upsertToDB(ctx context.Context, database db.Database, url *model.ShortURL) error {
+	dialect := database.Dialect()
+	query := `INSERT INTO short_urls (short_code, long_url, redirect_code, created_at, expires_at, owner_id, workspace_id) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?) ` + db.OnConflictUpdate(dialect, "workspace_id", "short_code") +
+		` long_url = ` + db.Excluded(dialect, "long_url") +
+		`, redirect_code = ` + db.Excluded(dialect, "redirect_code") +
+		`, expires_at = ` + db.Excluded(dialect, "expires_at")
 
 	var expiresAt interface{}
 	if url.ExpiresAt != nil {
 		expiresAt = *url.ExpiresAt
 	}
 
-	_, err := db.ExecContext(ctx, query,
-		url.ShortCode, url.LongURL, url.CreatedAt, expiresAt,
-		url.LongURL, expiresAt,
+	_, err := database.GetDB().ExecContext(ctx, db.Rebind(dialect, query),
+		url.ShortCode, url.LongURL, url.RedirectCode, url.CreatedAt, expiresAt, url.OwnerID, url.WorkspaceID,
 	)
 	return err
 }
 
-func (r *urlRepository) saveToSQLite(ctx context.Context, url *model.ShortURL) error {
-	db := r.sources.SQLiteDB.GetDB()
-	query := `INSERT INTO short_urls (short_code, long_url, created_at, expires_at) 
-	          VALUES (?, ?, ?, ?) 
-	          ON CONFLICT(short_code) DO UPDATE SET long_url = ?, expires_at = ?`
+// insertToDB 仅插入，short_code 冲突时返回数据库的唯一键错误
+func (r *urlRepository) insertToDB(ctx context.Context, database db.Database, url *model.ShortURL) error {
+	query := `INSERT INTO short_urls (short_code, long_url, redirect_code, created_at, expires_at, owner_id, workspace_id) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?)`
 
 	var expiresAt interface{}
 	if url.ExpiresAt != nil {
 		expiresAt = *url.ExpiresAt
 	}
+	args := []interface{}{url.ShortCode, url.LongURL, url.RedirectCode, url.CreatedAt, expiresAt, url.OwnerID, url.WorkspaceID}
 
-	_, err := db.ExecContext(ctx, query,
-		url.ShortCode, url.LongURL, url.CreatedAt, expiresAt,
-		url.LongURL, expiresAt,
-	)
-	return err
-}
-
-// 异步回写缓存
-func (r *urlRepository) asyncWriteToCache(ctx context.Context, url *model.ShortURL) {
-	if r.sources.RedisCache != nil {
-		_ = r.saveToRedis(ctx, url)
-	} else if r.sources.MemoryCache != nil {
-		_ = r.saveToMemory(ctx, url)
+	// PostgreSQL 驱动不支持 LastInsertId，通过 RETURNING 取回自增 id
+	if database.Dialect() == db.DialectPostgres {
+		return database.GetDB().QueryRowContext(ctx, db.Rebind(db.DialectPostgres, query+` RETURNING id`), args...).Scan(&url.ID)
+	}
+	res, err := database.GetDB().ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if id, err := res.LastInsertId(); err == nil {
+		url.ID = id
 	}
+	return nil
 }
 
 // 实现 URLRepository 接口的旧方法（保持兼容性）
@@ -644,7 +479,7 @@ func (r *urlRepository) GetFromDB(ctx context.Context, shortCode string) (*model
 }
 
 func (r *urlRepository) DeleteFromCache(ctx context.Context, shortCode string) error {
-	key := "shorturl:" + shortCode
+	key := cacheKey(r.workspaceID, shortCode)
 	var errs []error
 
 	if r.sources.RedisCache != nil {
@@ -658,6 +493,8 @@ func (r *urlRepository) DeleteFromCache(ctx context.Context, shortCode string) e
 			errs = append(errs, err)
 		}
 	}
+	// 其他实例的 MemoryCache 由各自订阅失效消息后清除
+	r.sources.Invalidation.Publish(ctx, []string{namespacedCode(r.workspaceID, shortCode)}, false)
 
 	if len(errs) > 0 {
 		return fmt.Errorf("failed to delete from cache: %v", errs)
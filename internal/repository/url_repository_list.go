@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/username/shorturl/internal/db"
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/pkg/utils"
+)
+
+// List 分页查询短链接，只从主数据库读取：配置了 MySQL 时使用 MySQL，否则使用 SQLite
+// 返回的结果最多 PageSize 条，hasMore 表示之后是否还有数据
+func (r *urlRepository) List(ctx context.Context, q model.ListQuery) ([]model.ShortURL, bool, error) {
+	database := r.systemOfRecord()
+	if database == nil {
+		return nil, false, errors.New("no database available")
+	}
+	if q.PageSize <= 0 {
+		return nil, false, fmt.Errorf("invalid page size: %d", q.PageSize)
+	}
+
+	var result []model.ShortURL
+	for {
+		// 域名过滤需要解析 URL，在 SQL 中用 LIKE 预筛后再精确过滤，可能需要多取几批
+		batch, err := r.listBatch(ctx, database, q, q.PageSize+1)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, u := range batch {
+			if q.HostContains == "" || hostContains(u.LongURL, q.HostContains) {
+				result = append(result, u)
+			}
+		}
+		if len(result) > q.PageSize {
+			return result[:q.PageSize], true, nil
+		}
+		if len(batch) <= q.PageSize {
+			return result, false, nil
+		}
+
+		// 继续从本批最后一条之后读取
+		last := batch[len(batch)-1]
+		q.AfterID, q.AfterCode = last.ID, last.ShortCode
+	}
+}
+
+// systemOfRecord 返回列表查询使用的数据库
+func (r *urlRepository) systemOfRecord() db.Database {
+	if r.sources.PrimaryDB != nil {
+		return r.sources.PrimaryDB
+	}
+	return r.sources.SQLiteDB
+}
+
+func (r *urlRepository) listBatch(ctx context.Context, database db.Database, q model.ListQuery, limit int) ([]model.ShortURL, error) {
+	var where []string
+	var args []interface{}
+
+	// 1. 游标
+	var orderBy string
+	switch q.Sort {
+	case model.SortCreatedAsc:
+		orderBy = "id ASC"
+		if q.AfterID > 0 {
+			where = append(where, "id > ?")
+			args = append(args, q.AfterID)
+		}
+	case model.SortShortCodeAsc:
+		orderBy = "short_code ASC"
+		if q.AfterCode != "" {
+			where = append(where, "short_code > ?")
+			args = append(args, q.AfterCode)
+		}
+	case model.SortShortCodeDesc:
+		orderBy = "short_code DESC"
+		if q.AfterCode != "" {
+			where = append(where, "short_code < ?")
+			args = append(args, q.AfterCode)
+		}
+	default:
+		// 自增 id 与创建顺序一致，按 id 排序可以得到稳定的游标
+		orderBy = "id DESC"
+		if q.AfterID > 0 {
+			where = append(where, "id < ?")
+			args = append(args, q.AfterID)
+		}
+	}
+
+	// 2. 过滤条件，只查询当前工作区
+	where = append(where, "workspace_id = ?")
+	args = append(args, r.workspaceID)
+	if q.CreatedAfter != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, *q.CreatedAfter)
+	}
+	if q.CreatedBefore != nil {
+		where = append(where, "created_at < ?")
+		args = append(args, *q.CreatedBefore)
+	}
+	switch q.Status {
+	case model.StatusActive:
+		where = append(where, "(expires_at IS NULL OR expires_at > ?)")
+		args = append(args, time.Now())
+	case model.StatusExpired:
+		where = append(where, "expires_at IS NOT NULL AND expires_at <= ?")
+		args = append(args, time.Now())
+	}
+	if q.OwnerID != 0 {
+		where = append(where, "owner_id = ?")
+		args = append(args, q.OwnerID)
+	}
+	if q.HostContains != "" && !strings.ContainsAny(q.HostContains, "%_\\") {
+		// 含有 LIKE 通配符时不做预筛，完全交给 hostContains 过滤
+		where = append(where, "LOWER(long_url) LIKE ?")
+		args = append(args, "%"+strings.ToLower(q.HostContains)+"%")
+	}
+
+	query := `SELECT id, short_code, long_url, redirect_code, created_at, expires_at, owner_id, workspace_id FROM short_urls`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY " + orderBy + " LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := database.GetDB().QueryContext(ctx, db.Rebind(database.Dialect(), query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query short URLs: %w", err)
+	}
+	defer rows.Close()
+
+	var urls []model.ShortURL
+	for rows.Next() {
+		var u model.ShortURL
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&u.ID, &u.ShortCode, &u.LongURL, &u.RedirectCode, &u.CreatedAt, &expiresAt, &u.OwnerID, &u.WorkspaceID); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if expiresAt.Valid {
+			u.ExpiresAt = &expiresAt.Time
+		}
+		urls = append(urls, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return urls, nil
+}
+
+// hostContains 判断长链接的域名是否包含子串（不区分大小写）
+func hostContains(longURL, substr string) bool {
+	u, err := url.Parse(utils.NormalizeURL(longURL))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(u.Hostname()), strings.ToLower(substr))
+}
@@ -0,0 +1,381 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/username/shorturl/internal/cache"
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// ReadStrategy 短链接读路径策略
+type ReadStrategy string
+
+const (
+	// ReadTiered 按 L1 内存 → L2 Redis → 主库 → 备用库 依次查询，命中即返回
+	ReadTiered ReadStrategy = "tiered"
+	// ReadRacing 同时查询所有数据源，使用最先命中的结果
+	ReadRacing ReadStrategy = "racing"
+	// ReadHedged 按 tiered 的顺序查询，上一层超过 HedgeDelay 未返回时提前启动下一层
+	ReadHedged ReadStrategy = "hedged"
+)
+
+// defaultHedgeDelay 未配置 HedgeDelay 时 hedged 策略使用的等待时长
+const defaultHedgeDelay = 20 * time.Millisecond
+
+// ParseReadStrategy 解析配置中的读路径策略，空字符串视为 tiered
+func ParseReadStrategy(s string) (ReadStrategy, error) {
+	switch strategy := ReadStrategy(s); strategy {
+	case "":
+		return ReadTiered, nil
+	case ReadTiered, ReadRacing, ReadHedged:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown read strategy: %s", s)
+	}
+}
+
+// Tier 读路径中的一层数据源
+type Tier string
+
+const (
+	TierMemory   Tier = "memory"
+	TierRedis    Tier = "redis"
+	TierPrimary  Tier = "primary"  // MySQL 或 PostgreSQL
+	TierFallback Tier = "fallback" // SQLite
+)
+
+// negativeTier 负缓存检查在 tiers 中的下标，位于缓存和数据库之间，不计入各层统计
+const negativeTier = -1
+
+// negativeKeyPrefix 负缓存的键前缀，与正常缓存的键区分，避免被 Exists 判为已占用
+const negativeKeyPrefix = "shorturl_miss:"
+
+// errKnownMissing 负缓存命中，短码最近确认过不存在
+var errKnownMissing = errors.New("short code known missing")
+
+// tierOrder 各层的查询顺序，同时也是 ReadStats 中的下标
+var tierOrder = [...]Tier{TierMemory, TierRedis, TierPrimary, TierFallback}
+
+// ReadOptions 读路径配置
+type ReadOptions struct {
+	Strategy ReadStrategy
+	// HedgeDelay hedged 策略下等待上一层的时长，0 使用默认值
+	HedgeDelay time.Duration
+	// Timeouts 每层的超时时间，未配置或为 0 表示不单独限制
+	Timeouts map[Tier]time.Duration
+}
+
+// TierStats 某一层的读取统计
+type TierStats struct {
+	// Served 由该层返回结果的请求数
+	Served int64
+	// Misses 未命中（包括已过期）的次数
+	Misses int64
+	// Errors 出错的次数，不包括超时和因其他层已命中而取消的查询
+	Errors int64
+	// Timeouts 超过该层超时时间的次数
+	Timeouts int64
+}
+
+// ReadStats 读路径的累计统计
+type ReadStats struct {
+	Tiers map[Tier]TierStats
+	// NotFound 所有层都未命中的请求数
+	NotFound int64
+	// FilterRejected 被 Bloom filter 判定为不存在、未查询任何数据源的请求数
+	FilterRejected int64
+	// NegativeHits 命中负缓存、未查询数据库的请求数
+	NegativeHits int64
+}
+
+type tierCounters struct {
+	served, misses, errors, timeouts atomic.Int64
+}
+
+var readMetrics struct {
+	tiers          [len(tierOrder)]tierCounters
+	notFound       atomic.Int64
+	filterRejected atomic.Int64
+	negativeHits   atomic.Int64
+}
+
+// ReadMetrics 返回进程内读路径统计的快照
+func ReadMetrics() ReadStats {
+	stats := ReadStats{
+		Tiers:          make(map[Tier]TierStats, len(tierOrder)),
+		NotFound:       readMetrics.notFound.Load(),
+		FilterRejected: readMetrics.filterRejected.Load(),
+		NegativeHits:   readMetrics.negativeHits.Load(),
+	}
+	for i, tier := range tierOrder {
+		c := &readMetrics.tiers[i]
+		stats.Tiers[tier] = TierStats{
+			Served:   c.served.Load(),
+			Misses:   c.misses.Load(),
+			Errors:   c.errors.Load(),
+			Timeouts: c.timeouts.Load(),
+		}
+	}
+	return stats
+}
+
+// tierReader 读取某一层的方法，index 为该层在 tierOrder 中的下标
+type tierReader struct {
+	index int
+	get   func(ctx context.Context, shortCode string) (*model.ShortURL, error)
+}
+
+// tierResult 某一层的读取结果，url 仅在命中且未过期时非空，known 表示负缓存命中
+type tierResult struct {
+	index   int
+	url     *model.ShortURL
+	expired bool
+	known   bool
+}
+
+// tiers 按查询顺序返回当前可用的数据源，启用负缓存时在数据库之前检查负缓存
+func (r *urlRepository) tiers() []tierReader {
+	var tiers []tierReader
+	if r.sources.MemoryCache != nil {
+		tiers = append(tiers, tierReader{0, r.getFromMemory})
+	}
+	if r.sources.RedisCache != nil {
+		tiers = append(tiers, tierReader{1, r.getFromRedis})
+	}
+	if r.sources.NegativeTTL > 0 && len(tiers) > 0 {
+		tiers = append(tiers, tierReader{negativeTier, r.getNegative})
+	}
+	if r.sources.PrimaryDB != nil {
+		tiers = append(tiers, tierReader{2, r.getFromPrimary})
+	}
+	if r.sources.SQLiteDB != nil {
+		tiers = append(tiers, tierReader{3, r.getFromSQLite})
+	}
+	return tiers
+}
+
+// fetch 按配置的读路径策略查询各数据源
+func (r *urlRepository) fetch(ctx context.Context, shortCode string) (*model.ShortURL, error) {
+	if !r.sources.KnownCodes.MayContain(namespacedCode(r.workspaceID, shortCode)) {
+		readMetrics.filterRejected.Add(1)
+		return nil, missError(shortCode, false)
+	}
+	tiers := r.tiers()
+
+	var res tierResult
+	var expired bool
+	var err error
+	switch r.sources.Read.Strategy {
+	case ReadRacing:
+		res, expired, err = r.readConcurrent(ctx, tiers, shortCode, 0)
+	case ReadHedged:
+		delay := r.sources.Read.HedgeDelay
+		if delay <= 0 {
+			delay = defaultHedgeDelay
+		}
+		res, expired, err = r.readConcurrent(ctx, tiers, shortCode, delay)
+	default:
+		res, expired, err = r.readTiered(ctx, tiers, shortCode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if res.known {
+		readMetrics.negativeHits.Add(1)
+		return nil, missError(shortCode, false)
+	}
+	if res.url == nil {
+		readMetrics.notFound.Add(1)
+		if !expired && r.sources.NegativeTTL > 0 {
+			go r.saveNegative(context.Background(), shortCode)
+		}
+		return nil, missError(shortCode, expired)
+	}
+
+	readMetrics.tiers[res.index].served.Add(1)
+	go r.backfill(context.Background(), res.url, res.index)
+	return res.url, nil
+}
+
+// readTiered 依次查询各层，命中即返回；expired 表示有数据源中存在已过期的数据
+func (r *urlRepository) readTiered(ctx context.Context, tiers []tierReader, shortCode string) (res tierResult, expired bool, err error) {
+	for _, t := range tiers {
+		res = r.readTier(ctx, t, shortCode)
+		if res.url != nil || res.known {
+			return res, expired, nil
+		}
+		expired = expired || res.expired
+		if err := ctx.Err(); err != nil {
+			return tierResult{}, false, err
+		}
+	}
+	return tierResult{}, expired, nil
+}
+
+// readConcurrent 按顺序启动各层的查询，返回最先命中的结果
+// delay 为 0 时同时启动所有层；否则上一层超过 delay 未返回，或已返回未命中时启动下一层
+func (r *urlRepository) readConcurrent(ctx context.Context, tiers []tierReader, shortCode string, delay time.Duration) (res tierResult, expired bool, err error) {
+	if len(tiers) == 0 {
+		return tierResult{}, false, nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // 命中后取消其他层的查询
+
+	results := make(chan tierResult, len(tiers))
+	next, pending := 0, 0
+	start := func() {
+		t := tiers[next]
+		next++
+		pending++
+		go func() { results <- r.readTier(ctx, t, shortCode) }()
+	}
+
+	start()
+	for delay <= 0 && next < len(tiers) {
+		start()
+	}
+	var timer *time.Timer
+	var hedge <-chan time.Time
+	if next < len(tiers) {
+		timer = time.NewTimer(delay)
+		defer timer.Stop()
+		hedge = timer.C
+	}
+
+	for pending > 0 {
+		select {
+		case res := <-results:
+			pending--
+			if res.url != nil || res.known {
+				return res, expired, nil
+			}
+			expired = expired || res.expired
+			if next < len(tiers) {
+				start()
+				timer.Reset(delay)
+			}
+		case <-hedge:
+			if next < len(tiers) {
+				start()
+				timer.Reset(delay)
+			}
+		case <-ctx.Done():
+			return tierResult{}, false, ctx.Err()
+		}
+	}
+	return tierResult{}, expired, nil
+}
+
+// readTier 在该层的超时时间内查询，并记录未命中、出错和超时
+func (r *urlRepository) readTier(ctx context.Context, t tierReader, shortCode string) tierResult {
+	if t.index == negativeTier {
+		url, err := t.get(ctx, shortCode)
+		return tierResult{index: t.index, url: url, known: errors.Is(err, errKnownMissing)}
+	}
+
+	tierCtx := ctx
+	if timeout := r.sources.Read.Timeouts[tierOrder[t.index]]; timeout > 0 {
+		var cancel context.CancelFunc
+		tierCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	counters := &readMetrics.tiers[t.index]
+	url, err := t.get(tierCtx, shortCode)
+	switch {
+	case err != nil:
+		// 调用方取消或其他层已命中时不计入该层的错误
+		if ctx.Err() != nil {
+			return tierResult{index: t.index}
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			counters.timeouts.Add(1)
+		} else {
+			counters.errors.Add(1)
+		}
+		return tierResult{index: t.index}
+	case url == nil:
+		counters.misses.Add(1)
+		return tierResult{index: t.index}
+	case url.IsExpired():
+		// 过期数据视为未命中，但需要记录下来，用于区分“已过期”和“不存在”
+		counters.misses.Add(1)
+		return tierResult{index: t.index, expired: true}
+	default:
+		return tierResult{index: t.index, url: url}
+	}
+}
+
+// backfill 把结果回写到比命中层更靠前的缓存中
+func (r *urlRepository) backfill(ctx context.Context, url *model.ShortURL, servedIndex int) {
+	if servedIndex > 0 && r.sources.MemoryCache != nil {
+		_ = r.saveToMemory(ctx, url)
+	}
+	if servedIndex > 1 && r.sources.RedisCache != nil {
+		_ = r.saveToRedis(ctx, url)
+	}
+}
+
+// getNegative 依次检查 Memory 和 Redis 中的负缓存，命中时返回 errKnownMissing
+func (r *urlRepository) getNegative(ctx context.Context, shortCode string) (*model.ShortURL, error) {
+	key := negativeKey(r.workspaceID, shortCode)
+	for _, c := range []cache.Cache{r.sources.MemoryCache, r.sources.RedisCache} {
+		if c == nil {
+			continue
+		}
+		if val, err := c.Get(ctx, key); err == nil && val != nil {
+			return nil, errKnownMissing
+		}
+	}
+	return nil, nil
+}
+
+// saveNegative 在 Memory 和 Redis 中记录短码不存在，有效期为 NegativeTTL
+func (r *urlRepository) saveNegative(ctx context.Context, shortCode string) {
+	key := negativeKey(r.workspaceID, shortCode)
+	for _, c := range []cache.Cache{r.sources.MemoryCache, r.sources.RedisCache} {
+		if c != nil {
+			_ = c.Set(ctx, key, "1", r.sources.NegativeTTL)
+		}
+	}
+}
+
+// clearNegative 短码写入后清除负缓存，记录到 Bloom filter，并通知其他实例
+func (r *urlRepository) clearNegative(ctx context.Context, shortCode string) {
+	code := namespacedCode(r.workspaceID, shortCode)
+	r.sources.KnownCodes.Add(code)
+	r.sources.Invalidation.Publish(ctx, []string{code}, true)
+	if r.sources.NegativeTTL <= 0 {
+		return
+	}
+	key := negativeKey(r.workspaceID, shortCode)
+	for _, c := range []cache.Cache{r.sources.MemoryCache, r.sources.RedisCache} {
+		if c != nil {
+			_ = c.Delete(ctx, key)
+		}
+	}
+}
+
+// clearNegatives 批量版本的 clearNegative，Redis 用一条命令删除；按每条数据自己的工作区处理
+func (r *urlRepository) clearNegatives(ctx context.Context, urls []*model.ShortURL) {
+	codes := make([]string, len(urls))
+	keys := make([]string, len(urls))
+	for i, u := range urls {
+		codes[i] = namespacedCode(u.WorkspaceID, u.ShortCode)
+		r.sources.KnownCodes.Add(codes[i])
+		keys[i] = negativeKey(u.WorkspaceID, u.ShortCode)
+	}
+	r.sources.Invalidation.Publish(ctx, codes, true)
+	if r.sources.NegativeTTL <= 0 || len(keys) == 0 {
+		return
+	}
+	for _, c := range []cache.Cache{r.sources.MemoryCache, r.sources.RedisCache} {
+		if c != nil {
+			_ = cache.DeleteMulti(ctx, c, keys)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// UserRepository 短链接的所有者
+type UserRepository interface {
+	// GetOrCreate 按认证身份查找用户，不存在时创建
+	// 用户 id 以主数据库为准并复制到 SQLite；主数据库不可用时只能查到已复制的用户，不会在 SQLite 中分配新 id
+	GetOrCreate(ctx context.Context, subject string) (*model.User, error)
+}
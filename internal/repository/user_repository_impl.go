@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/username/shorturl/internal/db"
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// userRepository 实现 UserRepository 接口
+type userRepository struct {
+	sources *DataSources
+}
+
+// NewUserRepository 创建新的用户 Repository
+func NewUserRepository(sources *DataSources) UserRepository {
+	return &userRepository{
+		sources: sources,
+	}
+}
+
+// knownUsers 进程内缓存 subject → *model.User，用户创建后不会修改或删除，每个请求不必再查询数据库
+var knownUsers sync.Map
+
+func (r *userRepository) GetOrCreate(ctx context.Context, subject string) (*model.User, error) {
+	if v, ok := knownUsers.Load(subject); ok {
+		return v.(*model.User), nil
+	}
+	user, err := r.getOrCreate(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	knownUsers.Store(subject, user)
+	return user, nil
+}
+
+func (r *userRepository) getOrCreate(ctx context.Context, subject string) (*model.User, error) {
+	if r.sources.PrimaryDB == nil {
+		if r.sources.SQLiteDB == nil {
+			return nil, errors.New("no database available")
+		}
+		return r.getOrInsert(ctx, r.sources.SQLiteDB, subject)
+	}
+
+	user, err := r.getOrInsert(ctx, r.sources.PrimaryDB, subject)
+	if err != nil {
+		if r.sources.SQLiteDB != nil {
+			if copied, sqliteErr := r.get(ctx, r.sources.SQLiteDB, subject); sqliteErr == nil && copied != nil {
+				return copied, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to get user %s: %w", subject, err)
+	}
+	if r.sources.SQLiteDB != nil {
+		if err := r.copyTo(ctx, r.sources.SQLiteDB, user); err != nil {
+			log.Printf("failed to copy user %s to SQLite: %v", subject, err)
+		}
+	}
+	return user, nil
+}
+
+// getOrInsert 查询用户，不存在时插入；并发插入同一 subject 时读取先插入的一条
+func (r *userRepository) getOrInsert(ctx context.Context, database db.Database, subject string) (*model.User, error) {
+	if user, err := r.get(ctx, database, subject); err != nil || user != nil {
+		return user, err
+	}
+
+	user := &model.User{Subject: subject, CreatedAt: time.Now()}
+	query := `INSERT INTO users (subject, created_at) VALUES (?, ?)`
+	var err error
+	if database.Dialect() == db.DialectPostgres {
+		err = database.GetDB().QueryRowContext(ctx, db.Rebind(db.DialectPostgres, query+` RETURNING id`), user.Subject, user.CreatedAt).Scan(&user.ID)
+	} else {
+		var res sql.Result
+		if res, err = database.GetDB().ExecContext(ctx, query, user.Subject, user.CreatedAt); err == nil {
+			user.ID, err = res.LastInsertId()
+		}
+	}
+	if err != nil {
+		if db.IsDuplicateKeyError(err) {
+			if user, err := r.get(ctx, database, subject); err != nil || user != nil {
+				return user, err
+			}
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// get 不存在时返回 nil, nil
+func (r *userRepository) get(ctx context.Context, database db.Database, subject string) (*model.User, error) {
+	var user model.User
+	err := database.GetDB().QueryRowContext(ctx, db.Rebind(database.Dialect(), `SELECT id, subject, created_at FROM users WHERE subject = ?`), subject).
+		Scan(&user.ID, &user.Subject, &user.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// copyTo 以主数据库分配的 id 写入副本
+func (r *userRepository) copyTo(ctx context.Context, database db.Database, user *model.User) error {
+	dialect := database.Dialect()
+	query := `INSERT INTO users (id, subject, created_at) VALUES (?, ?, ?) ` +
+		db.OnConflictUpdate(dialect, "id") + ` subject = ` + db.Excluded(dialect, "subject")
+	_, err := database.GetDB().ExecContext(ctx, db.Rebind(dialect, query), user.ID, user.Subject, user.CreatedAt)
+	return err
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/username/shorturl/internal/db/model"
+)
+
+var (
+	// ErrWorkspaceNotFound 工作区不存在
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	// ErrWorkspaceExists 工作区 slug 已被占用
+	ErrWorkspaceExists = errors.New("workspace already exists")
+)
+
+// WorkspaceRepository 工作区、成员与用量
+// 与用户一样以主数据库为准并复制到 SQLite；主数据库不可用时只能读取已复制的数据，不能创建或修改
+type WorkspaceRepository interface {
+	// Create 创建工作区并回填 ID，slug 已存在时返回 ErrWorkspaceExists
+	Create(ctx context.Context, ws *model.Workspace) error
+
+	// Update 按 ID 更新名称、配置与配额，不存在时返回 ErrWorkspaceNotFound
+	Update(ctx context.Context, ws *model.Workspace) error
+
+	// GetBySlug 查询工作区，不存在时返回 ErrWorkspaceNotFound
+	// 结果在进程内缓存一小段时间，修改在缓存过期后对其他实例生效
+	GetBySlug(ctx context.Context, slug string) (*model.Workspace, error)
+
+	// GetByID 按 ID 查询工作区，不存在时返回 ErrWorkspaceNotFound；与 GetBySlug 一样在进程内缓存
+	GetByID(ctx context.Context, id int64) (*model.Workspace, error)
+
+	// List 按 ID 顺序返回所有工作区
+	List(ctx context.Context) ([]model.Workspace, error)
+
+	// SetMember 添加成员或修改成员角色
+	SetMember(ctx context.Context, member *model.WorkspaceMember) error
+
+	// GetMember 查询成员，不是成员时返回 nil, nil；结果与 GetBySlug 一样在进程内缓存
+	GetMember(ctx context.Context, workspaceID, userID int64) (*model.WorkspaceMember, error)
+
+	// CountLinks 统计工作区内的短链接数量（主数据库与 SQLite 之和），用于数量配额
+	CountLinks(ctx context.Context, workspaceID int64) (int64, error)
+}
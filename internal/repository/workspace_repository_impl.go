@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/username/shorturl/internal/db"
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// workspaceCacheTTL 工作区与成员在进程内缓存的时长
+const workspaceCacheTTL = 30 * time.Second
+
+// workspaceRepository 实现 WorkspaceRepository 接口
+type workspaceRepository struct {
+	sources *DataSources
+}
+
+// NewWorkspaceRepository 创建新的工作区 Repository
+func NewWorkspaceRepository(sources *DataSources) WorkspaceRepository {
+	return &workspaceRepository{
+		sources: sources,
+	}
+}
+
+// workspaceCacheEntry 进程内缓存的查询结果，value 为 nil 表示不存在
+type workspaceCacheEntry struct {
+	value    any
+	loadedAt time.Time
+}
+
+// workspaceCache 进程内缓存 "slug:<slug>"、"id:<工作区 id>" → *model.Workspace、"member:<工作区 id>:<用户 id>" → *model.WorkspaceMember，
+// 以及 DomainRepository 的 "domain:<主机名>" → *model.Domain、"primary:<工作区 id>" → *model.Domain
+var workspaceCache sync.Map
+
+// cached 返回未过期的缓存，否则重新加载；加载失败时继续使用过期的缓存，避免数据库故障时所有工作区请求都失败
+func cached(key string, load func() (any, error)) (any, error) {
+	entry, ok := workspaceCache.Load(key)
+	if ok && time.Since(entry.(workspaceCacheEntry).loadedAt) < workspaceCacheTTL {
+		return entry.(workspaceCacheEntry).value, nil
+	}
+	value, err := load()
+	if err != nil {
+		if ok {
+			log.Printf("failed to reload %s, using stale value: %v", key, err)
+			return entry.(workspaceCacheEntry).value, nil
+		}
+		return nil, err
+	}
+	workspaceCache.Store(key, workspaceCacheEntry{value: value, loadedAt: time.Now()})
+	return value, nil
+}
+
+// systemOfRecord 创建和修改只写入主数据库（未配置时为 SQLite），再复制到 SQLite
+func systemOfRecord(sources *DataSources) (db.Database, error) {
+	if sources.PrimaryDB != nil {
+		return sources.PrimaryDB, nil
+	}
+	if sources.SQLiteDB != nil {
+		return sources.SQLiteDB, nil
+	}
+	return nil, errors.New("no database available")
+}
+
+// readWithFallback 优先从主数据库读取，失败时读取 SQLite 中的副本
+func readWithFallback(sources *DataSources, fn func(database db.Database) error) error {
+	var err error
+	if sources.PrimaryDB != nil {
+		if err = fn(sources.PrimaryDB); err == nil {
+			return nil
+		}
+	}
+	if sources.SQLiteDB != nil {
+		return fn(sources.SQLiteDB)
+	}
+	if err == nil {
+		err = errors.New("no database available")
+	}
+	return err
+}
+
+func (r *workspaceRepository) Create(ctx context.Context, ws *model.Workspace) error {
+	database, err := systemOfRecord(r.sources)
+	if err != nil {
+		return err
+	}
+	settings, err := json.Marshal(ws.Settings)
+	if err != nil {
+		return err
+	}
+	if ws.CreatedAt.IsZero() {
+		ws.CreatedAt = time.Now()
+	}
+
+	query := `INSERT INTO workspaces (slug, name, settings, max_links, max_monthly_clicks, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	args := []interface{}{ws.Slug, ws.Name, string(settings), ws.MaxLinks, ws.MaxMonthlyClicks, ws.CreatedAt}
+	if database.Dialect() == db.DialectPostgres {
+		err = database.GetDB().QueryRowContext(ctx, db.Rebind(db.DialectPostgres, query+` RETURNING id`), args...).Scan(&ws.ID)
+	} else {
+		var res sql.Result
+		if res, err = database.GetDB().ExecContext(ctx, query, args...); err == nil {
+			ws.ID, err = res.LastInsertId()
+		}
+	}
+	if err != nil {
+		if db.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %s", ErrWorkspaceExists, ws.Slug)
+		}
+		return err
+	}
+	r.copyWorkspace(ctx, database, ws)
+	return nil
+}
+
+func (r *workspaceRepository) Update(ctx context.Context, ws *model.Workspace) error {
+	database, err := systemOfRecord(r.sources)
+	if err != nil {
+		return err
+	}
+	settings, err := json.Marshal(ws.Settings)
+	if err != nil {
+		return err
+	}
+	res, err := database.GetDB().ExecContext(ctx, db.Rebind(database.Dialect(),
+		`UPDATE workspaces SET name = ?, settings = ?, max_links = ?, max_monthly_clicks = ? WHERE id = ?`),
+		ws.Name, string(settings), ws.MaxLinks, ws.MaxMonthlyClicks, ws.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %d", ErrWorkspaceNotFound, ws.ID)
+	}
+	r.copyWorkspace(ctx, database, ws)
+	workspaceCache.Delete("slug:" + ws.Slug)
+	workspaceCache.Delete("id:" + strconv.FormatInt(ws.ID, 10))
+	return nil
+}
+
+func (r *workspaceRepository) GetBySlug(ctx context.Context, slug string) (*model.Workspace, error) {
+	return r.getCached(ctx, "slug:"+slug, "slug", slug)
+}
+
+func (r *workspaceRepository) GetByID(ctx context.Context, id int64) (*model.Workspace, error) {
+	return r.getCached(ctx, "id:"+strconv.FormatInt(id, 10), "id", id)
+}
+
+// getCached 按 column（只能是内部常量）查询一个工作区并缓存
+func (r *workspaceRepository) getCached(ctx context.Context, key, column string, value any) (*model.Workspace, error) {
+	v, err := cached(key, func() (any, error) {
+		var ws *model.Workspace
+		err := readWithFallback(r.sources, func(database db.Database) error {
+			var err error
+			ws, err = r.get(ctx, database, column, value)
+			return err
+		})
+		if err != nil || ws == nil {
+			// 不能返回类型为 *model.Workspace 的 nil，否则缓存中的值不等于 nil
+			return nil, err
+		}
+		return ws, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace %v: %w", value, err)
+	}
+	if v == nil {
+		return nil, fmt.Errorf("%w: %v", ErrWorkspaceNotFound, value)
+	}
+	return v.(*model.Workspace), nil
+}
+
+// get 不存在时返回 nil, nil
+func (r *workspaceRepository) get(ctx context.Context, database db.Database, column string, value any) (*model.Workspace, error) {
+	var ws model.Workspace
+	var settings string
+	err := database.GetDB().QueryRowContext(ctx, db.Rebind(database.Dialect(),
+		`SELECT id, slug, name, settings, max_links, max_monthly_clicks, created_at FROM workspaces WHERE `+column+` = ?`), value,
+	).Scan(&ws.ID, &ws.Slug, &ws.Name, &settings, &ws.MaxLinks, &ws.MaxMonthlyClicks, &ws.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(settings), &ws.Settings); err != nil {
+		return nil, fmt.Errorf("invalid settings of workspace %s: %w", ws.Slug, err)
+	}
+	return &ws, nil
+}
+
+func (r *workspaceRepository) List(ctx context.Context) ([]model.Workspace, error) {
+	var list []model.Workspace
+	err := readWithFallback(r.sources, func(database db.Database) error {
+		list = nil
+		rows, err := database.GetDB().QueryContext(ctx,
+			`SELECT id, slug, name, settings, max_links, max_monthly_clicks, created_at FROM workspaces ORDER BY id`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var ws model.Workspace
+			var settings string
+			if err := rows.Scan(&ws.ID, &ws.Slug, &ws.Name, &settings, &ws.MaxLinks, &ws.MaxMonthlyClicks, &ws.CreatedAt); err != nil {
+				return fmt.Errorf("failed to scan row: %w", err)
+			}
+			if err := json.Unmarshal([]byte(settings), &ws.Settings); err != nil {
+				return fmt.Errorf("invalid settings of workspace %s: %w", ws.Slug, err)
+			}
+			list = append(list, ws)
+		}
+		return rows.Err()
+	})
+	return list, err
+}
+
+func (r *workspaceRepository) SetMember(ctx context.Context, member *model.WorkspaceMember) error {
+	database, err := systemOfRecord(r.sources)
+	if err != nil {
+		return err
+	}
+	if member.CreatedAt.IsZero() {
+		member.CreatedAt = time.Now()
+	}
+	if err := r.upsertMember(ctx, database, member); err != nil {
+		return err
+	}
+	if database != r.sources.SQLiteDB && r.sources.SQLiteDB != nil {
+		if err := r.upsertMember(ctx, r.sources.SQLiteDB, member); err != nil {
+			log.Printf("failed to copy member %d of workspace %d to SQLite: %v", member.UserID, member.WorkspaceID, err)
+		}
+	}
+	workspaceCache.Delete(memberCacheKey(member.WorkspaceID, member.UserID))
+	return nil
+}
+
+func (r *workspaceRepository) upsertMember(ctx context.Context, database db.Database, member *model.WorkspaceMember) error {
+	dialect := database.Dialect()
+	query := `INSERT INTO workspace_members (workspace_id, user_id, role, created_at) VALUES (?, ?, ?, ?) ` +
+		db.OnConflictUpdate(dialect, "workspace_id", "user_id") + ` role = ` + db.Excluded(dialect, "role")
+	_, err := database.GetDB().ExecContext(ctx, db.Rebind(dialect, query), member.WorkspaceID, member.UserID, member.Role, member.CreatedAt)
+	return err
+}
+
+func (r *workspaceRepository) GetMember(ctx context.Context, workspaceID, userID int64) (*model.WorkspaceMember, error) {
+	value, err := cached(memberCacheKey(workspaceID, userID), func() (any, error) {
+		var member *model.WorkspaceMember
+		err := readWithFallback(r.sources, func(database db.Database) error {
+			var m model.WorkspaceMember
+			err := database.GetDB().QueryRowContext(ctx, db.Rebind(database.Dialect(),
+				`SELECT workspace_id, user_id, role, created_at FROM workspace_members WHERE workspace_id = ? AND user_id = ?`),
+				workspaceID, userID,
+			).Scan(&m.WorkspaceID, &m.UserID, &m.Role, &m.CreatedAt)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				member = nil
+				return nil
+			case err != nil:
+				return err
+			}
+			member = &m
+			return nil
+		})
+		if err != nil || member == nil {
+			return nil, err
+		}
+		return member, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member %d of workspace %d: %w", userID, workspaceID, err)
+	}
+	if value == nil {
+		return nil, nil
+	}
+	return value.(*model.WorkspaceMember), nil
+}
+
+func memberCacheKey(workspaceID, userID int64) string {
+	return fmt.Sprintf("member:%d:%d", workspaceID, userID)
+}
+
+func (r *workspaceRepository) CountLinks(ctx context.Context, workspaceID int64) (int64, error) {
+	var total int64
+	for _, database := range []db.Database{r.sources.PrimaryDB, r.sources.SQLiteDB} {
+		if database == nil {
+			continue
+		}
+		var n int64
+		if err := database.GetDB().QueryRowContext(ctx, db.Rebind(database.Dialect(),
+			`SELECT COUNT(*) FROM short_urls WHERE workspace_id = ?`), workspaceID).Scan(&n); err != nil {
+			return 0, fmt.Errorf("failed to count short URLs: %w", err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// copyWorkspace 把主数据库中的工作区复制到 SQLite，失败只记录日志
+func (r *workspaceRepository) copyWorkspace(ctx context.Context, from db.Database, ws *model.Workspace) {
+	sqliteDB := r.sources.SQLiteDB
+	if sqliteDB == nil || from == sqliteDB {
+		return
+	}
+	settings, err := json.Marshal(ws.Settings)
+	if err == nil {
+		query := `INSERT INTO workspaces (id, slug, name, settings, max_links, max_monthly_clicks, created_at) VALUES (?, ?, ?, ?, ?, ?, ?) ` +
+			db.OnConflictUpdate(db.DialectSQLite, "id") +
+			` name = excluded.name, settings = excluded.settings, max_links = excluded.max_links, max_monthly_clicks = excluded.max_monthly_clicks`
+		_, err = sqliteDB.GetDB().ExecContext(ctx, query, ws.ID, ws.Slug, ws.Name, string(settings), ws.MaxLinks, ws.MaxMonthlyClicks, ws.CreatedAt)
+	}
+	if err != nil {
+		log.Printf("failed to copy workspace %s to SQLite: %v", ws.Slug, err)
+	}
+}
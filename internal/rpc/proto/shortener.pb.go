@@ -21,9 +21,121 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type LinkStatus int32
+
+const (
+	LinkStatus_LINK_STATUS_ALL     LinkStatus = 0
+	LinkStatus_LINK_STATUS_ACTIVE  LinkStatus = 1
+	LinkStatus_LINK_STATUS_EXPIRED LinkStatus = 2
+)
+
+// Enum value maps for LinkStatus.
+var (
+	LinkStatus_name = map[int32]string{
+		0: "LINK_STATUS_ALL",
+		1: "LINK_STATUS_ACTIVE",
+		2: "LINK_STATUS_EXPIRED",
+	}
+	LinkStatus_value = map[string]int32{
+		"LINK_STATUS_ALL":     0,
+		"LINK_STATUS_ACTIVE":  1,
+		"LINK_STATUS_EXPIRED": 2,
+	}
+)
+
+func (x LinkStatus) Enum() *LinkStatus {
+	p := new(LinkStatus)
+	*p = x
+	return p
+}
+
+func (x LinkStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LinkStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_shortener_proto_enumTypes[0].Descriptor()
+}
+
+func (LinkStatus) Type() protoreflect.EnumType {
+	return &file_proto_shortener_proto_enumTypes[0]
+}
+
+func (x LinkStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LinkStatus.Descriptor instead.
+func (LinkStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{0}
+}
+
+type LinkSort int32
+
+const (
+	// 按创建顺序倒序（最新的在前）
+	LinkSort_LINK_SORT_CREATED_DESC    LinkSort = 0
+	LinkSort_LINK_SORT_CREATED_ASC     LinkSort = 1
+	LinkSort_LINK_SORT_SHORT_CODE_ASC  LinkSort = 2
+	LinkSort_LINK_SORT_SHORT_CODE_DESC LinkSort = 3
+)
+
+// Enum value maps for LinkSort.
+var (
+	LinkSort_name = map[int32]string{
+		0: "LINK_SORT_CREATED_DESC",
+		1: "LINK_SORT_CREATED_ASC",
+		2: "LINK_SORT_SHORT_CODE_ASC",
+		3: "LINK_SORT_SHORT_CODE_DESC",
+	}
+	LinkSort_value = map[string]int32{
+		"LINK_SORT_CREATED_DESC":    0,
+		"LINK_SORT_CREATED_ASC":     1,
+		"LINK_SORT_SHORT_CODE_ASC":  2,
+		"LINK_SORT_SHORT_CODE_DESC": 3,
+	}
+)
+
+func (x LinkSort) Enum() *LinkSort {
+	p := new(LinkSort)
+	*p = x
+	return p
+}
+
+func (x LinkSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LinkSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_shortener_proto_enumTypes[1].Descriptor()
+}
+
+func (LinkSort) Type() protoreflect.EnumType {
+	return &file_proto_shortener_proto_enumTypes[1]
+}
+
+func (x LinkSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LinkSort.Descriptor instead.
+func (LinkSort) EnumDescriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{1}
+}
+
 type CreateShortLinkRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	LongUrl       string                 `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	LongUrl string                 `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	// 重定向状态码：301/302/307/308，不传默认 302
+	RedirectCode int32 `protobuf:"varint,2,opt,name=redirect_code,json=redirectCode,proto3" json:"redirect_code,omitempty"`
+	// 自定义短码（可选），已被占用时返回 ALREADY_EXISTS
+	CustomAlias string `protobuf:"bytes,3,opt,name=custom_alias,json=customAlias,proto3" json:"custom_alias,omitempty"`
+	// 有效期（秒），与 expires_at 二选一；都不传时使用服务端默认有效期
+	ExpiresIn int64 `protobuf:"varint,4,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	// 绝对过期时间（Unix 秒）
+	ExpiresAt int64 `protobuf:"varint,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// 永不过期，服务端配置了最长有效期时会被拒绝
+	NeverExpires  bool `protobuf:"varint,6,opt,name=never_expires,json=neverExpires,proto3" json:"never_expires,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -65,9 +177,48 @@ func (x *CreateShortLinkRequest) GetLongUrl() string {
 	return ""
 }
 
+func (x *CreateShortLinkRequest) GetRedirectCode() int32 {
+	if x != nil {
+		return x.RedirectCode
+	}
+	return 0
+}
+
+func (x *CreateShortLinkRequest) GetCustomAlias() string {
+	if x != nil {
+		return x.CustomAlias
+	}
+	return ""
+}
+
+func (x *CreateShortLinkRequest) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+func (x *CreateShortLinkRequest) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *CreateShortLinkRequest) GetNeverExpires() bool {
+	if x != nil {
+		return x.NeverExpires
+	}
+	return false
+}
+
 type CreateShortLinkResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShortKey      string                 `protobuf:"bytes,1,opt,name=short_key,json=shortKey,proto3" json:"short_key,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ShortKey string                 `protobuf:"bytes,1,opt,name=short_key,json=shortKey,proto3" json:"short_key,omitempty"`
+	// 过期时间（Unix 秒），0 表示永不过期
+	ExpiresAt int64 `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// 完整的短链接地址：工作区有已验证的主域名时使用主域名，否则使用 Domains.BaseURL
+	ShortUrl      string `protobuf:"bytes,3,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -109,27 +260,41 @@ func (x *CreateShortLinkResponse) GetShortKey() string {
 	return ""
 }
 
-type GetLongURLRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShortKey      string                 `protobuf:"bytes,1,opt,name=short_key,json=shortKey,proto3" json:"short_key,omitempty"`
+func (x *CreateShortLinkResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *CreateShortLinkResponse) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+type BatchCreateShortLinksRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Items         []*CreateShortLinkRequest `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetLongURLRequest) Reset() {
-	*x = GetLongURLRequest{}
+func (x *BatchCreateShortLinksRequest) Reset() {
+	*x = BatchCreateShortLinksRequest{}
 	mi := &file_proto_shortener_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetLongURLRequest) String() string {
+func (x *BatchCreateShortLinksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetLongURLRequest) ProtoMessage() {}
+func (*BatchCreateShortLinksRequest) ProtoMessage() {}
 
-func (x *GetLongURLRequest) ProtoReflect() protoreflect.Message {
+func (x *BatchCreateShortLinksRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortener_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -141,40 +306,42 @@ func (x *GetLongURLRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetLongURLRequest.ProtoReflect.Descriptor instead.
-func (*GetLongURLRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchCreateShortLinksRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateShortLinksRequest) Descriptor() ([]byte, []int) {
 	return file_proto_shortener_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetLongURLRequest) GetShortKey() string {
+func (x *BatchCreateShortLinksRequest) GetItems() []*CreateShortLinkRequest {
 	if x != nil {
-		return x.ShortKey
+		return x.Items
 	}
-	return ""
+	return nil
 }
 
-type GetLongURLResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	LongUrl       string                 `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
-	IsFound       bool                   `protobuf:"varint,2,opt,name=is_found,json=isFound,proto3" json:"is_found,omitempty"`
+type BatchCreateShortLinksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 与请求中的条目一一对应
+	Results       []*BatchCreateResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Succeeded     int32                `protobuf:"varint,2,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	Failed        int32                `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetLongURLResponse) Reset() {
-	*x = GetLongURLResponse{}
+func (x *BatchCreateShortLinksResponse) Reset() {
+	*x = BatchCreateShortLinksResponse{}
 	mi := &file_proto_shortener_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetLongURLResponse) String() string {
+func (x *BatchCreateShortLinksResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetLongURLResponse) ProtoMessage() {}
+func (*BatchCreateShortLinksResponse) ProtoMessage() {}
 
-func (x *GetLongURLResponse) ProtoReflect() protoreflect.Message {
+func (x *BatchCreateShortLinksResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortener_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -186,45 +353,61 @@ func (x *GetLongURLResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetLongURLResponse.ProtoReflect.Descriptor instead.
-func (*GetLongURLResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchCreateShortLinksResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateShortLinksResponse) Descriptor() ([]byte, []int) {
 	return file_proto_shortener_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *GetLongURLResponse) GetLongUrl() string {
+func (x *BatchCreateShortLinksResponse) GetResults() []*BatchCreateResult {
 	if x != nil {
-		return x.LongUrl
+		return x.Results
 	}
-	return ""
+	return nil
 }
 
-func (x *GetLongURLResponse) GetIsFound() bool {
+func (x *BatchCreateShortLinksResponse) GetSucceeded() int32 {
 	if x != nil {
-		return x.IsFound
+		return x.Succeeded
 	}
-	return false
+	return 0
 }
 
-type GetAllShortLinkRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+func (x *BatchCreateShortLinksResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+type BatchCreateResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 条目在请求中的序号（流式调用时为发送顺序），从 0 开始
+	Index     int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	ShortKey  string `protobuf:"bytes,2,opt,name=short_key,json=shortKey,proto3" json:"short_key,omitempty"`
+	ExpiresAt int64  `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// 失败时的 gRPC 状态码与错误信息，成功时 code 为 0
+	Code  int32  `protobuf:"varint,4,opt,name=code,proto3" json:"code,omitempty"`
+	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// 完整的短链接地址，同 CreateShortLinkResponse.short_url
+	ShortUrl      string `protobuf:"bytes,6,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAllShortLinkRequest) Reset() {
-	*x = GetAllShortLinkRequest{}
+func (x *BatchCreateResult) Reset() {
+	*x = BatchCreateResult{}
 	mi := &file_proto_shortener_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAllShortLinkRequest) String() string {
+func (x *BatchCreateResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAllShortLinkRequest) ProtoMessage() {}
+func (*BatchCreateResult) ProtoMessage() {}
 
-func (x *GetAllShortLinkRequest) ProtoReflect() protoreflect.Message {
+func (x *BatchCreateResult) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortener_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -236,32 +419,78 @@ func (x *GetAllShortLinkRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAllShortLinkRequest.ProtoReflect.Descriptor instead.
-func (*GetAllShortLinkRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchCreateResult.ProtoReflect.Descriptor instead.
+func (*BatchCreateResult) Descriptor() ([]byte, []int) {
 	return file_proto_shortener_proto_rawDescGZIP(), []int{4}
 }
 
-type GetAllShortLinkResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShortLinks    []*ShortLink           `protobuf:"bytes,1,rep,name=shortLinks,proto3" json:"shortLinks,omitempty"`
+func (x *BatchCreateResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchCreateResult) GetShortKey() string {
+	if x != nil {
+		return x.ShortKey
+	}
+	return ""
+}
+
+func (x *BatchCreateResult) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *BatchCreateResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BatchCreateResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BatchCreateResult) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+type GetLongURLRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ShortKey string                 `protobuf:"bytes,1,opt,name=short_key,json=shortKey,proto3" json:"short_key,omitempty"`
+	// 访问者信息，由跳转网关填写；为空时不记录点击
+	Visitor *Visitor `protobuf:"bytes,2,opt,name=visitor,proto3" json:"visitor,omitempty"`
+	// 请求的 Host（不含端口），已登记的自定义域名按所属工作区查找短码，其他 Host 使用默认工作区
+	Host          string `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAllShortLinkResponse) Reset() {
-	*x = GetAllShortLinkResponse{}
+func (x *GetLongURLRequest) Reset() {
+	*x = GetLongURLRequest{}
 	mi := &file_proto_shortener_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAllShortLinkResponse) String() string {
+func (x *GetLongURLRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAllShortLinkResponse) ProtoMessage() {}
+func (*GetLongURLRequest) ProtoMessage() {}
 
-func (x *GetAllShortLinkResponse) ProtoReflect() protoreflect.Message {
+func (x *GetLongURLRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortener_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -273,40 +502,57 @@ func (x *GetAllShortLinkResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAllShortLinkResponse.ProtoReflect.Descriptor instead.
-func (*GetAllShortLinkResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetLongURLRequest.ProtoReflect.Descriptor instead.
+func (*GetLongURLRequest) Descriptor() ([]byte, []int) {
 	return file_proto_shortener_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *GetAllShortLinkResponse) GetShortLinks() []*ShortLink {
+func (x *GetLongURLRequest) GetShortKey() string {
 	if x != nil {
-		return x.ShortLinks
+		return x.ShortKey
+	}
+	return ""
+}
+
+func (x *GetLongURLRequest) GetVisitor() *Visitor {
+	if x != nil {
+		return x.Visitor
 	}
 	return nil
 }
 
-type ShortLink struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShortLink     string                 `protobuf:"bytes,1,opt,name=ShortLink,proto3" json:"ShortLink,omitempty"`
-	LongLink      string                 `protobuf:"bytes,2,opt,name=LongLink,proto3" json:"LongLink,omitempty"`
+func (x *GetLongURLRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+type Visitor struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Referrer  string                 `protobuf:"bytes,1,opt,name=referrer,proto3" json:"referrer,omitempty"`
+	UserAgent string                 `protobuf:"bytes,2,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	Ip        string                 `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	// 国家/地区代码，由上游 CDN 提供（如 CF-IPCountry）
+	Country       string `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ShortLink) Reset() {
-	*x = ShortLink{}
+func (x *Visitor) Reset() {
+	*x = Visitor{}
 	mi := &file_proto_shortener_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ShortLink) String() string {
+func (x *Visitor) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShortLink) ProtoMessage() {}
+func (*Visitor) ProtoMessage() {}
 
-func (x *ShortLink) ProtoReflect() protoreflect.Message {
+func (x *Visitor) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortener_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -318,52 +564,1096 @@ func (x *ShortLink) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ShortLink.ProtoReflect.Descriptor instead.
-func (*ShortLink) Descriptor() ([]byte, []int) {
+// Deprecated: Use Visitor.ProtoReflect.Descriptor instead.
+func (*Visitor) Descriptor() ([]byte, []int) {
 	return file_proto_shortener_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *ShortLink) GetShortLink() string {
+func (x *Visitor) GetReferrer() string {
 	if x != nil {
-		return x.ShortLink
+		return x.Referrer
 	}
 	return ""
 }
 
-func (x *ShortLink) GetLongLink() string {
+func (x *Visitor) GetUserAgent() string {
 	if x != nil {
-		return x.LongLink
+		return x.UserAgent
 	}
 	return ""
 }
 
-var File_proto_shortener_proto protoreflect.FileDescriptor
+func (x *Visitor) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
 
-const file_proto_shortener_proto_rawDesc = "" +
-	"\n" +
-	"\x15proto/shortener.proto\x12\tshortener\"3\n" +
-	"\x16CreateShortLinkRequest\x12\x19\n" +
-	"\blong_url\x18\x01 \x01(\tR\alongUrl\"6\n" +
-	"\x17CreateShortLinkResponse\x12\x1b\n" +
-	"\tshort_key\x18\x01 \x01(\tR\bshortKey\"0\n" +
-	"\x11GetLongURLRequest\x12\x1b\n" +
-	"\tshort_key\x18\x01 \x01(\tR\bshortKey\"J\n" +
-	"\x12GetLongURLResponse\x12\x19\n" +
-	"\blong_url\x18\x01 \x01(\tR\alongUrl\x12\x19\n" +
-	"\bis_found\x18\x02 \x01(\bR\aisFound\"\x18\n" +
-	"\x16GetAllShortLinkRequest\"O\n" +
-	"\x17GetAllShortLinkResponse\x124\n" +
-	"\n" +
-	"shortLinks\x18\x01 \x03(\v2\x14.shortener.ShortLinkR\n" +
-	"shortLinks\"E\n" +
-	"\tShortLink\x12\x1c\n" +
-	"\tShortLink\x18\x01 \x01(\tR\tShortLink\x12\x1a\n" +
-	"\bLongLink\x18\x02 \x01(\tR\bLongLink2\x91\x02\n" +
+func (x *Visitor) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+type GetLongURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LongUrl       string                 `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	IsFound       bool                   `protobuf:"varint,2,opt,name=is_found,json=isFound,proto3" json:"is_found,omitempty"`
+	RedirectCode  int32                  `protobuf:"varint,3,opt,name=redirect_code,json=redirectCode,proto3" json:"redirect_code,omitempty"`
+	IsExpired     bool                   `protobuf:"varint,4,opt,name=is_expired,json=isExpired,proto3" json:"is_expired,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLongURLResponse) Reset() {
+	*x = GetLongURLResponse{}
+	mi := &file_proto_shortener_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLongURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLongURLResponse) ProtoMessage() {}
+
+func (x *GetLongURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLongURLResponse.ProtoReflect.Descriptor instead.
+func (*GetLongURLResponse) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetLongURLResponse) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *GetLongURLResponse) GetIsFound() bool {
+	if x != nil {
+		return x.IsFound
+	}
+	return false
+}
+
+func (x *GetLongURLResponse) GetRedirectCode() int32 {
+	if x != nil {
+		return x.RedirectCode
+	}
+	return 0
+}
+
+func (x *GetLongURLResponse) GetIsExpired() bool {
+	if x != nil {
+		return x.IsExpired
+	}
+	return false
+}
+
+type GetAllShortLinkRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 每页条数，默认 50，最大 1000
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// 上一页返回的 next_page_token，为空表示第一页
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// 创建时间过滤（Unix 秒），0 表示不限
+	CreatedAfter  int64      `protobuf:"varint,3,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore int64      `protobuf:"varint,4,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	Status        LinkStatus `protobuf:"varint,5,opt,name=status,proto3,enum=shortener.LinkStatus" json:"status,omitempty"`
+	// 目标地址域名包含的子串
+	HostContains string   `protobuf:"bytes,6,opt,name=host_contains,json=hostContains,proto3" json:"host_contains,omitempty"`
+	Sort         LinkSort `protobuf:"varint,7,opt,name=sort,proto3,enum=shortener.LinkSort" json:"sort,omitempty"`
+	// 返回所有用户的短链接，仅管理员可用；默认只返回调用方自己的短链接
+	AllUsers      bool `protobuf:"varint,8,opt,name=all_users,json=allUsers,proto3" json:"all_users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllShortLinkRequest) Reset() {
+	*x = GetAllShortLinkRequest{}
+	mi := &file_proto_shortener_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllShortLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllShortLinkRequest) ProtoMessage() {}
+
+func (x *GetAllShortLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllShortLinkRequest.ProtoReflect.Descriptor instead.
+func (*GetAllShortLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetAllShortLinkRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetAllShortLinkRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetAllShortLinkRequest) GetCreatedAfter() int64 {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return 0
+}
+
+func (x *GetAllShortLinkRequest) GetCreatedBefore() int64 {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return 0
+}
+
+func (x *GetAllShortLinkRequest) GetStatus() LinkStatus {
+	if x != nil {
+		return x.Status
+	}
+	return LinkStatus_LINK_STATUS_ALL
+}
+
+func (x *GetAllShortLinkRequest) GetHostContains() string {
+	if x != nil {
+		return x.HostContains
+	}
+	return ""
+}
+
+func (x *GetAllShortLinkRequest) GetSort() LinkSort {
+	if x != nil {
+		return x.Sort
+	}
+	return LinkSort_LINK_SORT_CREATED_DESC
+}
+
+func (x *GetAllShortLinkRequest) GetAllUsers() bool {
+	if x != nil {
+		return x.AllUsers
+	}
+	return false
+}
+
+type GetAllShortLinkResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ShortLinks []*ShortLink           `protobuf:"bytes,1,rep,name=shortLinks,proto3" json:"shortLinks,omitempty"`
+	// 下一页的 token，为空表示没有更多数据
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllShortLinkResponse) Reset() {
+	*x = GetAllShortLinkResponse{}
+	mi := &file_proto_shortener_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllShortLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllShortLinkResponse) ProtoMessage() {}
+
+func (x *GetAllShortLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllShortLinkResponse.ProtoReflect.Descriptor instead.
+func (*GetAllShortLinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetAllShortLinkResponse) GetShortLinks() []*ShortLink {
+	if x != nil {
+		return x.ShortLinks
+	}
+	return nil
+}
+
+func (x *GetAllShortLinkResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type StreamShortLinksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 每批从数据库读取的条数，默认 500，最大 1000
+	BatchSize int32 `protobuf:"varint,1,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	// 过滤与排序条件，含义同 GetAllShortLinkRequest
+	CreatedAfter  int64      `protobuf:"varint,2,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore int64      `protobuf:"varint,3,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	Status        LinkStatus `protobuf:"varint,4,opt,name=status,proto3,enum=shortener.LinkStatus" json:"status,omitempty"`
+	HostContains  string     `protobuf:"bytes,5,opt,name=host_contains,json=hostContains,proto3" json:"host_contains,omitempty"`
+	Sort          LinkSort   `protobuf:"varint,6,opt,name=sort,proto3,enum=shortener.LinkSort" json:"sort,omitempty"`
+	AllUsers      bool       `protobuf:"varint,7,opt,name=all_users,json=allUsers,proto3" json:"all_users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamShortLinksRequest) Reset() {
+	*x = StreamShortLinksRequest{}
+	mi := &file_proto_shortener_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamShortLinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamShortLinksRequest) ProtoMessage() {}
+
+func (x *StreamShortLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamShortLinksRequest.ProtoReflect.Descriptor instead.
+func (*StreamShortLinksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StreamShortLinksRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *StreamShortLinksRequest) GetCreatedAfter() int64 {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return 0
+}
+
+func (x *StreamShortLinksRequest) GetCreatedBefore() int64 {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return 0
+}
+
+func (x *StreamShortLinksRequest) GetStatus() LinkStatus {
+	if x != nil {
+		return x.Status
+	}
+	return LinkStatus_LINK_STATUS_ALL
+}
+
+func (x *StreamShortLinksRequest) GetHostContains() string {
+	if x != nil {
+		return x.HostContains
+	}
+	return ""
+}
+
+func (x *StreamShortLinksRequest) GetSort() LinkSort {
+	if x != nil {
+		return x.Sort
+	}
+	return LinkSort_LINK_SORT_CREATED_DESC
+}
+
+func (x *StreamShortLinksRequest) GetAllUsers() bool {
+	if x != nil {
+		return x.AllUsers
+	}
+	return false
+}
+
+type ShortLink struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 短码
+	ShortLink    string `protobuf:"bytes,1,opt,name=ShortLink,proto3" json:"ShortLink,omitempty"`
+	LongLink     string `protobuf:"bytes,2,opt,name=LongLink,proto3" json:"LongLink,omitempty"`
+	RedirectCode int32  `protobuf:"varint,3,opt,name=redirect_code,json=redirectCode,proto3" json:"redirect_code,omitempty"`
+	// Unix 秒，expires_at 为 0 表示永不过期
+	CreatedAt int64 `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt int64 `protobuf:"varint,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// 所有者的用户 id，0 表示没有所有者
+	OwnerId int64 `protobuf:"varint,6,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// 完整的短链接地址，同 CreateShortLinkResponse.short_url
+	ShortUrl      string `protobuf:"bytes,7,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShortLink) Reset() {
+	*x = ShortLink{}
+	mi := &file_proto_shortener_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShortLink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortLink) ProtoMessage() {}
+
+func (x *ShortLink) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortLink.ProtoReflect.Descriptor instead.
+func (*ShortLink) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ShortLink) GetShortLink() string {
+	if x != nil {
+		return x.ShortLink
+	}
+	return ""
+}
+
+func (x *ShortLink) GetLongLink() string {
+	if x != nil {
+		return x.LongLink
+	}
+	return ""
+}
+
+func (x *ShortLink) GetRedirectCode() int32 {
+	if x != nil {
+		return x.RedirectCode
+	}
+	return 0
+}
+
+func (x *ShortLink) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ShortLink) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *ShortLink) GetOwnerId() int64 {
+	if x != nil {
+		return x.OwnerId
+	}
+	return 0
+}
+
+func (x *ShortLink) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+type GetLinkStatsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ShortKey string                 `protobuf:"bytes,1,opt,name=short_key,json=shortKey,proto3" json:"short_key,omitempty"`
+	// 时间桶粒度：hour | day，默认 day
+	Granularity string `protobuf:"bytes,2,opt,name=granularity,proto3" json:"granularity,omitempty"`
+	// 统计区间（Unix 秒），默认最近 7 天
+	From int64 `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`
+	To   int64 `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`
+	// 排行榜条数，默认 10
+	TopN          int32 `protobuf:"varint,5,opt,name=top_n,json=topN,proto3" json:"top_n,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkStatsRequest) Reset() {
+	*x = GetLinkStatsRequest{}
+	mi := &file_proto_shortener_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkStatsRequest) ProtoMessage() {}
+
+func (x *GetLinkStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetLinkStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetLinkStatsRequest) GetShortKey() string {
+	if x != nil {
+		return x.ShortKey
+	}
+	return ""
+}
+
+func (x *GetLinkStatsRequest) GetGranularity() string {
+	if x != nil {
+		return x.Granularity
+	}
+	return ""
+}
+
+func (x *GetLinkStatsRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetLinkStatsRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetLinkStatsRequest) GetTopN() int32 {
+	if x != nil {
+		return x.TopN
+	}
+	return 0
+}
+
+type GetLinkStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TotalClicks   int64                  `protobuf:"varint,1,opt,name=total_clicks,json=totalClicks,proto3" json:"total_clicks,omitempty"`
+	Buckets       []*ClickBucket         `protobuf:"bytes,2,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	TopReferrers  []*CountEntry          `protobuf:"bytes,3,rep,name=top_referrers,json=topReferrers,proto3" json:"top_referrers,omitempty"`
+	TopUserAgents []*CountEntry          `protobuf:"bytes,4,rep,name=top_user_agents,json=topUserAgents,proto3" json:"top_user_agents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkStatsResponse) Reset() {
+	*x = GetLinkStatsResponse{}
+	mi := &file_proto_shortener_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkStatsResponse) ProtoMessage() {}
+
+func (x *GetLinkStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetLinkStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetLinkStatsResponse) GetTotalClicks() int64 {
+	if x != nil {
+		return x.TotalClicks
+	}
+	return 0
+}
+
+func (x *GetLinkStatsResponse) GetBuckets() []*ClickBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetTopReferrers() []*CountEntry {
+	if x != nil {
+		return x.TopReferrers
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetTopUserAgents() []*CountEntry {
+	if x != nil {
+		return x.TopUserAgents
+	}
+	return nil
+}
+
+type ClickBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 桶起始时间（Unix 秒，UTC）
+	Start         int64 `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	Count         int64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClickBucket) Reset() {
+	*x = ClickBucket{}
+	mi := &file_proto_shortener_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClickBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClickBucket) ProtoMessage() {}
+
+func (x *ClickBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClickBucket.ProtoReflect.Descriptor instead.
+func (*ClickBucket) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ClickBucket) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *ClickBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type CountEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountEntry) Reset() {
+	*x = CountEntry{}
+	mi := &file_proto_shortener_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountEntry) ProtoMessage() {}
+
+func (x *CountEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountEntry.ProtoReflect.Descriptor instead.
+func (*CountEntry) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CountEntry) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *CountEntry) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// 未设置的字段保持不变；expires_in / expires_at / never_expires 最多指定一个
+type UpdateShortLinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortKey      string                 `protobuf:"bytes,1,opt,name=short_key,json=shortKey,proto3" json:"short_key,omitempty"`
+	LongUrl       *string                `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3,oneof" json:"long_url,omitempty"`
+	RedirectCode  *int32                 `protobuf:"varint,3,opt,name=redirect_code,json=redirectCode,proto3,oneof" json:"redirect_code,omitempty"`
+	ExpiresIn     *int64                 `protobuf:"varint,4,opt,name=expires_in,json=expiresIn,proto3,oneof" json:"expires_in,omitempty"`
+	ExpiresAt     *int64                 `protobuf:"varint,5,opt,name=expires_at,json=expiresAt,proto3,oneof" json:"expires_at,omitempty"`
+	NeverExpires  *bool                  `protobuf:"varint,6,opt,name=never_expires,json=neverExpires,proto3,oneof" json:"never_expires,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateShortLinkRequest) Reset() {
+	*x = UpdateShortLinkRequest{}
+	mi := &file_proto_shortener_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateShortLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateShortLinkRequest) ProtoMessage() {}
+
+func (x *UpdateShortLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateShortLinkRequest.ProtoReflect.Descriptor instead.
+func (*UpdateShortLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdateShortLinkRequest) GetShortKey() string {
+	if x != nil {
+		return x.ShortKey
+	}
+	return ""
+}
+
+func (x *UpdateShortLinkRequest) GetLongUrl() string {
+	if x != nil && x.LongUrl != nil {
+		return *x.LongUrl
+	}
+	return ""
+}
+
+func (x *UpdateShortLinkRequest) GetRedirectCode() int32 {
+	if x != nil && x.RedirectCode != nil {
+		return *x.RedirectCode
+	}
+	return 0
+}
+
+func (x *UpdateShortLinkRequest) GetExpiresIn() int64 {
+	if x != nil && x.ExpiresIn != nil {
+		return *x.ExpiresIn
+	}
+	return 0
+}
+
+func (x *UpdateShortLinkRequest) GetExpiresAt() int64 {
+	if x != nil && x.ExpiresAt != nil {
+		return *x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *UpdateShortLinkRequest) GetNeverExpires() bool {
+	if x != nil && x.NeverExpires != nil {
+		return *x.NeverExpires
+	}
+	return false
+}
+
+type UpdateShortLinkResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ShortKey     string                 `protobuf:"bytes,1,opt,name=short_key,json=shortKey,proto3" json:"short_key,omitempty"`
+	LongUrl      string                 `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	RedirectCode int32                  `protobuf:"varint,3,opt,name=redirect_code,json=redirectCode,proto3" json:"redirect_code,omitempty"`
+	// 过期时间（Unix 秒），0 表示永不过期
+	ExpiresAt int64 `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// 完整的短链接地址，同 CreateShortLinkResponse.short_url
+	ShortUrl      string `protobuf:"bytes,5,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateShortLinkResponse) Reset() {
+	*x = UpdateShortLinkResponse{}
+	mi := &file_proto_shortener_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateShortLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateShortLinkResponse) ProtoMessage() {}
+
+func (x *UpdateShortLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateShortLinkResponse.ProtoReflect.Descriptor instead.
+func (*UpdateShortLinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UpdateShortLinkResponse) GetShortKey() string {
+	if x != nil {
+		return x.ShortKey
+	}
+	return ""
+}
+
+func (x *UpdateShortLinkResponse) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *UpdateShortLinkResponse) GetRedirectCode() int32 {
+	if x != nil {
+		return x.RedirectCode
+	}
+	return 0
+}
+
+func (x *UpdateShortLinkResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *UpdateShortLinkResponse) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+type DeleteShortLinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortKey      string                 `protobuf:"bytes,1,opt,name=short_key,json=shortKey,proto3" json:"short_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteShortLinkRequest) Reset() {
+	*x = DeleteShortLinkRequest{}
+	mi := &file_proto_shortener_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteShortLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteShortLinkRequest) ProtoMessage() {}
+
+func (x *DeleteShortLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteShortLinkRequest.ProtoReflect.Descriptor instead.
+func (*DeleteShortLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeleteShortLinkRequest) GetShortKey() string {
+	if x != nil {
+		return x.ShortKey
+	}
+	return ""
+}
+
+type DeleteShortLinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteShortLinkResponse) Reset() {
+	*x = DeleteShortLinkResponse{}
+	mi := &file_proto_shortener_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteShortLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteShortLinkResponse) ProtoMessage() {}
+
+func (x *DeleteShortLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteShortLinkResponse.ProtoReflect.Descriptor instead.
+func (*DeleteShortLinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{19}
+}
+
+var File_proto_shortener_proto protoreflect.FileDescriptor
+
+const file_proto_shortener_proto_rawDesc = "" +
+	"\n" +
+	"\x15proto/shortener.proto\x12\tshortener\"\xde\x01\n" +
+	"\x16CreateShortLinkRequest\x12\x19\n" +
+	"\blong_url\x18\x01 \x01(\tR\alongUrl\x12#\n" +
+	"\rredirect_code\x18\x02 \x01(\x05R\fredirectCode\x12!\n" +
+	"\fcustom_alias\x18\x03 \x01(\tR\vcustomAlias\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x04 \x01(\x03R\texpiresIn\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\x03R\texpiresAt\x12#\n" +
+	"\rnever_expires\x18\x06 \x01(\bR\fneverExpires\"r\n" +
+	"\x17CreateShortLinkResponse\x12\x1b\n" +
+	"\tshort_key\x18\x01 \x01(\tR\bshortKey\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\x12\x1b\n" +
+	"\tshort_url\x18\x03 \x01(\tR\bshortUrl\"W\n" +
+	"\x1cBatchCreateShortLinksRequest\x127\n" +
+	"\x05items\x18\x01 \x03(\v2!.shortener.CreateShortLinkRequestR\x05items\"\x8d\x01\n" +
+	"\x1dBatchCreateShortLinksResponse\x126\n" +
+	"\aresults\x18\x01 \x03(\v2\x1c.shortener.BatchCreateResultR\aresults\x12\x1c\n" +
+	"\tsucceeded\x18\x02 \x01(\x05R\tsucceeded\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x05R\x06failed\"\xac\x01\n" +
+	"\x11BatchCreateResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x1b\n" +
+	"\tshort_key\x18\x02 \x01(\tR\bshortKey\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\x03R\texpiresAt\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\x05R\x04code\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x1b\n" +
+	"\tshort_url\x18\x06 \x01(\tR\bshortUrl\"r\n" +
+	"\x11GetLongURLRequest\x12\x1b\n" +
+	"\tshort_key\x18\x01 \x01(\tR\bshortKey\x12,\n" +
+	"\avisitor\x18\x02 \x01(\v2\x12.shortener.VisitorR\avisitor\x12\x12\n" +
+	"\x04host\x18\x03 \x01(\tR\x04host\"n\n" +
+	"\aVisitor\x12\x1a\n" +
+	"\breferrer\x18\x01 \x01(\tR\breferrer\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x02 \x01(\tR\tuserAgent\x12\x0e\n" +
+	"\x02ip\x18\x03 \x01(\tR\x02ip\x12\x18\n" +
+	"\acountry\x18\x04 \x01(\tR\acountry\"\x8e\x01\n" +
+	"\x12GetLongURLResponse\x12\x19\n" +
+	"\blong_url\x18\x01 \x01(\tR\alongUrl\x12\x19\n" +
+	"\bis_found\x18\x02 \x01(\bR\aisFound\x12#\n" +
+	"\rredirect_code\x18\x03 \x01(\x05R\fredirectCode\x12\x1d\n" +
+	"\n" +
+	"is_expired\x18\x04 \x01(\bR\tisExpired\"\xba\x02\n" +
+	"\x16GetAllShortLinkRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12#\n" +
+	"\rcreated_after\x18\x03 \x01(\x03R\fcreatedAfter\x12%\n" +
+	"\x0ecreated_before\x18\x04 \x01(\x03R\rcreatedBefore\x12-\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x15.shortener.LinkStatusR\x06status\x12#\n" +
+	"\rhost_contains\x18\x06 \x01(\tR\fhostContains\x12'\n" +
+	"\x04sort\x18\a \x01(\x0e2\x13.shortener.LinkSortR\x04sort\x12\x1b\n" +
+	"\tall_users\x18\b \x01(\bR\ballUsers\"w\n" +
+	"\x17GetAllShortLinkResponse\x124\n" +
+	"\n" +
+	"shortLinks\x18\x01 \x03(\v2\x14.shortener.ShortLinkR\n" +
+	"shortLinks\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x9e\x02\n" +
+	"\x17StreamShortLinksRequest\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x01 \x01(\x05R\tbatchSize\x12#\n" +
+	"\rcreated_after\x18\x02 \x01(\x03R\fcreatedAfter\x12%\n" +
+	"\x0ecreated_before\x18\x03 \x01(\x03R\rcreatedBefore\x12-\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x15.shortener.LinkStatusR\x06status\x12#\n" +
+	"\rhost_contains\x18\x05 \x01(\tR\fhostContains\x12'\n" +
+	"\x04sort\x18\x06 \x01(\x0e2\x13.shortener.LinkSortR\x04sort\x12\x1b\n" +
+	"\tall_users\x18\a \x01(\bR\ballUsers\"\xe0\x01\n" +
+	"\tShortLink\x12\x1c\n" +
+	"\tShortLink\x18\x01 \x01(\tR\tShortLink\x12\x1a\n" +
+	"\bLongLink\x18\x02 \x01(\tR\bLongLink\x12#\n" +
+	"\rredirect_code\x18\x03 \x01(\x05R\fredirectCode\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\x03R\texpiresAt\x12\x19\n" +
+	"\bowner_id\x18\x06 \x01(\x03R\aownerId\x12\x1b\n" +
+	"\tshort_url\x18\a \x01(\tR\bshortUrl\"\x8d\x01\n" +
+	"\x13GetLinkStatsRequest\x12\x1b\n" +
+	"\tshort_key\x18\x01 \x01(\tR\bshortKey\x12 \n" +
+	"\vgranularity\x18\x02 \x01(\tR\vgranularity\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12\x13\n" +
+	"\x05top_n\x18\x05 \x01(\x05R\x04topN\"\xe6\x01\n" +
+	"\x14GetLinkStatsResponse\x12!\n" +
+	"\ftotal_clicks\x18\x01 \x01(\x03R\vtotalClicks\x120\n" +
+	"\abuckets\x18\x02 \x03(\v2\x16.shortener.ClickBucketR\abuckets\x12:\n" +
+	"\rtop_referrers\x18\x03 \x03(\v2\x15.shortener.CountEntryR\ftopReferrers\x12=\n" +
+	"\x0ftop_user_agents\x18\x04 \x03(\v2\x15.shortener.CountEntryR\rtopUserAgents\"9\n" +
+	"\vClickBucket\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x03R\x05start\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"8\n" +
+	"\n" +
+	"CountEntry\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\xc0\x02\n" +
+	"\x16UpdateShortLinkRequest\x12\x1b\n" +
+	"\tshort_key\x18\x01 \x01(\tR\bshortKey\x12\x1e\n" +
+	"\blong_url\x18\x02 \x01(\tH\x00R\alongUrl\x88\x01\x01\x12(\n" +
+	"\rredirect_code\x18\x03 \x01(\x05H\x01R\fredirectCode\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"expires_in\x18\x04 \x01(\x03H\x02R\texpiresIn\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\x03H\x03R\texpiresAt\x88\x01\x01\x12(\n" +
+	"\rnever_expires\x18\x06 \x01(\bH\x04R\fneverExpires\x88\x01\x01B\v\n" +
+	"\t_long_urlB\x10\n" +
+	"\x0e_redirect_codeB\r\n" +
+	"\v_expires_inB\r\n" +
+	"\v_expires_atB\x10\n" +
+	"\x0e_never_expires\"\xb2\x01\n" +
+	"\x17UpdateShortLinkResponse\x12\x1b\n" +
+	"\tshort_key\x18\x01 \x01(\tR\bshortKey\x12\x19\n" +
+	"\blong_url\x18\x02 \x01(\tR\alongUrl\x12#\n" +
+	"\rredirect_code\x18\x03 \x01(\x05R\fredirectCode\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\texpiresAt\x12\x1b\n" +
+	"\tshort_url\x18\x05 \x01(\tR\bshortUrl\"5\n" +
+	"\x16DeleteShortLinkRequest\x12\x1b\n" +
+	"\tshort_key\x18\x01 \x01(\tR\bshortKey\"\x19\n" +
+	"\x17DeleteShortLinkResponse*R\n" +
+	"\n" +
+	"LinkStatus\x12\x13\n" +
+	"\x0fLINK_STATUS_ALL\x10\x00\x12\x16\n" +
+	"\x12LINK_STATUS_ACTIVE\x10\x01\x12\x17\n" +
+	"\x13LINK_STATUS_EXPIRED\x10\x02*~\n" +
+	"\bLinkSort\x12\x1a\n" +
+	"\x16LINK_SORT_CREATED_DESC\x10\x00\x12\x19\n" +
+	"\x15LINK_SORT_CREATED_ASC\x10\x01\x12\x1c\n" +
+	"\x18LINK_SORT_SHORT_CODE_ASC\x10\x02\x12\x1d\n" +
+	"\x19LINK_SORT_SHORT_CODE_DESC\x10\x032\xc0\x06\n" +
 	"\x10ShortenerService\x12X\n" +
 	"\x0fCreateShortLink\x12!.shortener.CreateShortLinkRequest\x1a\".shortener.CreateShortLinkResponse\x12I\n" +
 	"\n" +
-	"GetLongURL\x12\x1c.shortener.GetLongURLRequest\x1a\x1d.shortener.GetLongURLResponse\x12X\n" +
-	"\x0fGetAllShortLink\x12!.shortener.GetAllShortLinkRequest\x1a\".shortener.GetAllShortLinkResponseB1Z/github.com/username/shorturl/internal/rpc/protob\x06proto3"
+	"GetLongURL\x12\x1c.shortener.GetLongURLRequest\x1a\x1d.shortener.GetLongURLResponse\x12j\n" +
+	"\x15BatchCreateShortLinks\x12'.shortener.BatchCreateShortLinksRequest\x1a(.shortener.BatchCreateShortLinksResponse\x12l\n" +
+	"\x1bBatchCreateShortLinksStream\x12!.shortener.CreateShortLinkRequest\x1a(.shortener.BatchCreateShortLinksResponse(\x01\x12X\n" +
+	"\x0fGetAllShortLink\x12!.shortener.GetAllShortLinkRequest\x1a\".shortener.GetAllShortLinkResponse\x12N\n" +
+	"\x10StreamShortLinks\x12\".shortener.StreamShortLinksRequest\x1a\x14.shortener.ShortLink0\x01\x12O\n" +
+	"\fGetLinkStats\x12\x1e.shortener.GetLinkStatsRequest\x1a\x1f.shortener.GetLinkStatsResponse\x12X\n" +
+	"\x0fUpdateShortLink\x12!.shortener.UpdateShortLinkRequest\x1a\".shortener.UpdateShortLinkResponse\x12X\n" +
+	"\x0fDeleteShortLink\x12!.shortener.DeleteShortLinkRequest\x1a\".shortener.DeleteShortLinkResponseB1Z/github.com/username/shorturl/internal/rpc/protob\x06proto3"
 
 var (
 	file_proto_shortener_proto_rawDescOnce sync.Once
@@ -377,29 +1667,67 @@ func file_proto_shortener_proto_rawDescGZIP() []byte {
 	return file_proto_shortener_proto_rawDescData
 }
 
-var file_proto_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_shortener_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_proto_shortener_proto_goTypes = []any{
-	(*CreateShortLinkRequest)(nil),  // 0: shortener.CreateShortLinkRequest
-	(*CreateShortLinkResponse)(nil), // 1: shortener.CreateShortLinkResponse
-	(*GetLongURLRequest)(nil),       // 2: shortener.GetLongURLRequest
-	(*GetLongURLResponse)(nil),      // 3: shortener.GetLongURLResponse
-	(*GetAllShortLinkRequest)(nil),  // 4: shortener.GetAllShortLinkRequest
-	(*GetAllShortLinkResponse)(nil), // 5: shortener.GetAllShortLinkResponse
-	(*ShortLink)(nil),               // 6: shortener.ShortLink
+	(LinkStatus)(0),                       // 0: shortener.LinkStatus
+	(LinkSort)(0),                         // 1: shortener.LinkSort
+	(*CreateShortLinkRequest)(nil),        // 2: shortener.CreateShortLinkRequest
+	(*CreateShortLinkResponse)(nil),       // 3: shortener.CreateShortLinkResponse
+	(*BatchCreateShortLinksRequest)(nil),  // 4: shortener.BatchCreateShortLinksRequest
+	(*BatchCreateShortLinksResponse)(nil), // 5: shortener.BatchCreateShortLinksResponse
+	(*BatchCreateResult)(nil),             // 6: shortener.BatchCreateResult
+	(*GetLongURLRequest)(nil),             // 7: shortener.GetLongURLRequest
+	(*Visitor)(nil),                       // 8: shortener.Visitor
+	(*GetLongURLResponse)(nil),            // 9: shortener.GetLongURLResponse
+	(*GetAllShortLinkRequest)(nil),        // 10: shortener.GetAllShortLinkRequest
+	(*GetAllShortLinkResponse)(nil),       // 11: shortener.GetAllShortLinkResponse
+	(*StreamShortLinksRequest)(nil),       // 12: shortener.StreamShortLinksRequest
+	(*ShortLink)(nil),                     // 13: shortener.ShortLink
+	(*GetLinkStatsRequest)(nil),           // 14: shortener.GetLinkStatsRequest
+	(*GetLinkStatsResponse)(nil),          // 15: shortener.GetLinkStatsResponse
+	(*ClickBucket)(nil),                   // 16: shortener.ClickBucket
+	(*CountEntry)(nil),                    // 17: shortener.CountEntry
+	(*UpdateShortLinkRequest)(nil),        // 18: shortener.UpdateShortLinkRequest
+	(*UpdateShortLinkResponse)(nil),       // 19: shortener.UpdateShortLinkResponse
+	(*DeleteShortLinkRequest)(nil),        // 20: shortener.DeleteShortLinkRequest
+	(*DeleteShortLinkResponse)(nil),       // 21: shortener.DeleteShortLinkResponse
 }
 var file_proto_shortener_proto_depIdxs = []int32{
-	6, // 0: shortener.GetAllShortLinkResponse.shortLinks:type_name -> shortener.ShortLink
-	0, // 1: shortener.ShortenerService.CreateShortLink:input_type -> shortener.CreateShortLinkRequest
-	2, // 2: shortener.ShortenerService.GetLongURL:input_type -> shortener.GetLongURLRequest
-	4, // 3: shortener.ShortenerService.GetAllShortLink:input_type -> shortener.GetAllShortLinkRequest
-	1, // 4: shortener.ShortenerService.CreateShortLink:output_type -> shortener.CreateShortLinkResponse
-	3, // 5: shortener.ShortenerService.GetLongURL:output_type -> shortener.GetLongURLResponse
-	5, // 6: shortener.ShortenerService.GetAllShortLink:output_type -> shortener.GetAllShortLinkResponse
-	4, // [4:7] is the sub-list for method output_type
-	1, // [1:4] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2,  // 0: shortener.BatchCreateShortLinksRequest.items:type_name -> shortener.CreateShortLinkRequest
+	6,  // 1: shortener.BatchCreateShortLinksResponse.results:type_name -> shortener.BatchCreateResult
+	8,  // 2: shortener.GetLongURLRequest.visitor:type_name -> shortener.Visitor
+	0,  // 3: shortener.GetAllShortLinkRequest.status:type_name -> shortener.LinkStatus
+	1,  // 4: shortener.GetAllShortLinkRequest.sort:type_name -> shortener.LinkSort
+	13, // 5: shortener.GetAllShortLinkResponse.shortLinks:type_name -> shortener.ShortLink
+	0,  // 6: shortener.StreamShortLinksRequest.status:type_name -> shortener.LinkStatus
+	1,  // 7: shortener.StreamShortLinksRequest.sort:type_name -> shortener.LinkSort
+	16, // 8: shortener.GetLinkStatsResponse.buckets:type_name -> shortener.ClickBucket
+	17, // 9: shortener.GetLinkStatsResponse.top_referrers:type_name -> shortener.CountEntry
+	17, // 10: shortener.GetLinkStatsResponse.top_user_agents:type_name -> shortener.CountEntry
+	2,  // 11: shortener.ShortenerService.CreateShortLink:input_type -> shortener.CreateShortLinkRequest
+	7,  // 12: shortener.ShortenerService.GetLongURL:input_type -> shortener.GetLongURLRequest
+	4,  // 13: shortener.ShortenerService.BatchCreateShortLinks:input_type -> shortener.BatchCreateShortLinksRequest
+	2,  // 14: shortener.ShortenerService.BatchCreateShortLinksStream:input_type -> shortener.CreateShortLinkRequest
+	10, // 15: shortener.ShortenerService.GetAllShortLink:input_type -> shortener.GetAllShortLinkRequest
+	12, // 16: shortener.ShortenerService.StreamShortLinks:input_type -> shortener.StreamShortLinksRequest
+	14, // 17: shortener.ShortenerService.GetLinkStats:input_type -> shortener.GetLinkStatsRequest
+	18, // 18: shortener.ShortenerService.UpdateShortLink:input_type -> shortener.UpdateShortLinkRequest
+	20, // 19: shortener.ShortenerService.DeleteShortLink:input_type -> shortener.DeleteShortLinkRequest
+	3,  // 20: shortener.ShortenerService.CreateShortLink:output_type -> shortener.CreateShortLinkResponse
+	9,  // 21: shortener.ShortenerService.GetLongURL:output_type -> shortener.GetLongURLResponse
+	5,  // 22: shortener.ShortenerService.BatchCreateShortLinks:output_type -> shortener.BatchCreateShortLinksResponse
+	5,  // 23: shortener.ShortenerService.BatchCreateShortLinksStream:output_type -> shortener.BatchCreateShortLinksResponse
+	11, // 24: shortener.ShortenerService.GetAllShortLink:output_type -> shortener.GetAllShortLinkResponse
+	13, // 25: shortener.ShortenerService.StreamShortLinks:output_type -> shortener.ShortLink
+	15, // 26: shortener.ShortenerService.GetLinkStats:output_type -> shortener.GetLinkStatsResponse
+	19, // 27: shortener.ShortenerService.UpdateShortLink:output_type -> shortener.UpdateShortLinkResponse
+	21, // 28: shortener.ShortenerService.DeleteShortLink:output_type -> shortener.DeleteShortLinkResponse
+	20, // [20:29] is the sub-list for method output_type
+	11, // [11:20] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_proto_shortener_proto_init() }
@@ -407,18 +1735,20 @@ func file_proto_shortener_proto_init() {
 	if File_proto_shortener_proto != nil {
 		return
 	}
+	file_proto_shortener_proto_msgTypes[16].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_shortener_proto_rawDesc), len(file_proto_shortener_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      2,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_shortener_proto_goTypes,
 		DependencyIndexes: file_proto_shortener_proto_depIdxs,
+		EnumInfos:         file_proto_shortener_proto_enumTypes,
 		MessageInfos:      file_proto_shortener_proto_msgTypes,
 	}.Build()
 	File_proto_shortener_proto = out.File
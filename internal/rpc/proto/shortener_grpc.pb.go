@@ -19,9 +19,15 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ShortenerService_CreateShortLink_FullMethodName = "/shortener.ShortenerService/CreateShortLink"
-	ShortenerService_GetLongURL_FullMethodName      = "/shortener.ShortenerService/GetLongURL"
-	ShortenerService_GetAllShortLink_FullMethodName = "/shortener.ShortenerService/GetAllShortLink"
+	ShortenerService_CreateShortLink_FullMethodName             = "/shortener.ShortenerService/CreateShortLink"
+	ShortenerService_GetLongURL_FullMethodName                  = "/shortener.ShortenerService/GetLongURL"
+	ShortenerService_BatchCreateShortLinks_FullMethodName       = "/shortener.ShortenerService/BatchCreateShortLinks"
+	ShortenerService_BatchCreateShortLinksStream_FullMethodName = "/shortener.ShortenerService/BatchCreateShortLinksStream"
+	ShortenerService_GetAllShortLink_FullMethodName             = "/shortener.ShortenerService/GetAllShortLink"
+	ShortenerService_StreamShortLinks_FullMethodName            = "/shortener.ShortenerService/StreamShortLinks"
+	ShortenerService_GetLinkStats_FullMethodName                = "/shortener.ShortenerService/GetLinkStats"
+	ShortenerService_UpdateShortLink_FullMethodName             = "/shortener.ShortenerService/UpdateShortLink"
+	ShortenerService_DeleteShortLink_FullMethodName             = "/shortener.ShortenerService/DeleteShortLink"
 )
 
 // ShortenerServiceClient is the client API for ShortenerService service.
@@ -30,7 +36,16 @@ const (
 type ShortenerServiceClient interface {
 	CreateShortLink(ctx context.Context, in *CreateShortLinkRequest, opts ...grpc.CallOption) (*CreateShortLinkResponse, error)
 	GetLongURL(ctx context.Context, in *GetLongURLRequest, opts ...grpc.CallOption) (*GetLongURLResponse, error)
+	// 批量创建，每条独立返回结果，单次最多 1000 条
+	BatchCreateShortLinks(ctx context.Context, in *BatchCreateShortLinksRequest, opts ...grpc.CallOption) (*BatchCreateShortLinksResponse, error)
+	// 客户端流式批量创建，服务端每累积 1000 条处理一批，结束时返回全部结果
+	BatchCreateShortLinksStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[CreateShortLinkRequest, BatchCreateShortLinksResponse], error)
 	GetAllShortLink(ctx context.Context, in *GetAllShortLinkRequest, opts ...grpc.CallOption) (*GetAllShortLinkResponse, error)
+	// 按批次从数据库读取并逐条推送，用于全量导出
+	StreamShortLinks(ctx context.Context, in *StreamShortLinksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ShortLink], error)
+	GetLinkStats(ctx context.Context, in *GetLinkStatsRequest, opts ...grpc.CallOption) (*GetLinkStatsResponse, error)
+	UpdateShortLink(ctx context.Context, in *UpdateShortLinkRequest, opts ...grpc.CallOption) (*UpdateShortLinkResponse, error)
+	DeleteShortLink(ctx context.Context, in *DeleteShortLinkRequest, opts ...grpc.CallOption) (*DeleteShortLinkResponse, error)
 }
 
 type shortenerServiceClient struct {
@@ -61,6 +76,29 @@ func (c *shortenerServiceClient) GetLongURL(ctx context.Context, in *GetLongURLR
 	return out, nil
 }
 
+func (c *shortenerServiceClient) BatchCreateShortLinks(ctx context.Context, in *BatchCreateShortLinksRequest, opts ...grpc.CallOption) (*BatchCreateShortLinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchCreateShortLinksResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_BatchCreateShortLinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) BatchCreateShortLinksStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[CreateShortLinkRequest, BatchCreateShortLinksResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ShortenerService_ServiceDesc.Streams[0], ShortenerService_BatchCreateShortLinksStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CreateShortLinkRequest, BatchCreateShortLinksResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ShortenerService_BatchCreateShortLinksStreamClient = grpc.ClientStreamingClient[CreateShortLinkRequest, BatchCreateShortLinksResponse]
+
 func (c *shortenerServiceClient) GetAllShortLink(ctx context.Context, in *GetAllShortLinkRequest, opts ...grpc.CallOption) (*GetAllShortLinkResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetAllShortLinkResponse)
@@ -71,13 +109,71 @@ func (c *shortenerServiceClient) GetAllShortLink(ctx context.Context, in *GetAll
 	return out, nil
 }
 
+func (c *shortenerServiceClient) StreamShortLinks(ctx context.Context, in *StreamShortLinksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ShortLink], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ShortenerService_ServiceDesc.Streams[1], ShortenerService_StreamShortLinks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamShortLinksRequest, ShortLink]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ShortenerService_StreamShortLinksClient = grpc.ServerStreamingClient[ShortLink]
+
+func (c *shortenerServiceClient) GetLinkStats(ctx context.Context, in *GetLinkStatsRequest, opts ...grpc.CallOption) (*GetLinkStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLinkStatsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetLinkStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) UpdateShortLink(ctx context.Context, in *UpdateShortLinkRequest, opts ...grpc.CallOption) (*UpdateShortLinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateShortLinkResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_UpdateShortLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) DeleteShortLink(ctx context.Context, in *DeleteShortLinkRequest, opts ...grpc.CallOption) (*DeleteShortLinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteShortLinkResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_DeleteShortLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ShortenerServiceServer is the server API for ShortenerService service.
 // All implementations must embed UnimplementedShortenerServiceServer
 // for forward compatibility.
 type ShortenerServiceServer interface {
 	CreateShortLink(context.Context, *CreateShortLinkRequest) (*CreateShortLinkResponse, error)
 	GetLongURL(context.Context, *GetLongURLRequest) (*GetLongURLResponse, error)
+	// 批量创建，每条独立返回结果，单次最多 1000 条
+	BatchCreateShortLinks(context.Context, *BatchCreateShortLinksRequest) (*BatchCreateShortLinksResponse, error)
+	// 客户端流式批量创建，服务端每累积 1000 条处理一批，结束时返回全部结果
+	BatchCreateShortLinksStream(grpc.ClientStreamingServer[CreateShortLinkRequest, BatchCreateShortLinksResponse]) error
 	GetAllShortLink(context.Context, *GetAllShortLinkRequest) (*GetAllShortLinkResponse, error)
+	// 按批次从数据库读取并逐条推送，用于全量导出
+	StreamShortLinks(*StreamShortLinksRequest, grpc.ServerStreamingServer[ShortLink]) error
+	GetLinkStats(context.Context, *GetLinkStatsRequest) (*GetLinkStatsResponse, error)
+	UpdateShortLink(context.Context, *UpdateShortLinkRequest) (*UpdateShortLinkResponse, error)
+	DeleteShortLink(context.Context, *DeleteShortLinkRequest) (*DeleteShortLinkResponse, error)
 	mustEmbedUnimplementedShortenerServiceServer()
 }
 
@@ -94,9 +190,27 @@ func (UnimplementedShortenerServiceServer) CreateShortLink(context.Context, *Cre
 func (UnimplementedShortenerServiceServer) GetLongURL(context.Context, *GetLongURLRequest) (*GetLongURLResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetLongURL not implemented")
 }
+func (UnimplementedShortenerServiceServer) BatchCreateShortLinks(context.Context, *BatchCreateShortLinksRequest) (*BatchCreateShortLinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateShortLinks not implemented")
+}
+func (UnimplementedShortenerServiceServer) BatchCreateShortLinksStream(grpc.ClientStreamingServer[CreateShortLinkRequest, BatchCreateShortLinksResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method BatchCreateShortLinksStream not implemented")
+}
 func (UnimplementedShortenerServiceServer) GetAllShortLink(context.Context, *GetAllShortLinkRequest) (*GetAllShortLinkResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllShortLink not implemented")
 }
+func (UnimplementedShortenerServiceServer) StreamShortLinks(*StreamShortLinksRequest, grpc.ServerStreamingServer[ShortLink]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamShortLinks not implemented")
+}
+func (UnimplementedShortenerServiceServer) GetLinkStats(context.Context, *GetLinkStatsRequest) (*GetLinkStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLinkStats not implemented")
+}
+func (UnimplementedShortenerServiceServer) UpdateShortLink(context.Context, *UpdateShortLinkRequest) (*UpdateShortLinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateShortLink not implemented")
+}
+func (UnimplementedShortenerServiceServer) DeleteShortLink(context.Context, *DeleteShortLinkRequest) (*DeleteShortLinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteShortLink not implemented")
+}
 func (UnimplementedShortenerServiceServer) mustEmbedUnimplementedShortenerServiceServer() {}
 func (UnimplementedShortenerServiceServer) testEmbeddedByValue()                          {}
 
@@ -154,6 +268,31 @@ func _ShortenerService_GetLongURL_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortenerService_BatchCreateShortLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateShortLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).BatchCreateShortLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_BatchCreateShortLinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).BatchCreateShortLinks(ctx, req.(*BatchCreateShortLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_BatchCreateShortLinksStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ShortenerServiceServer).BatchCreateShortLinksStream(&grpc.GenericServerStream[CreateShortLinkRequest, BatchCreateShortLinksResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ShortenerService_BatchCreateShortLinksStreamServer = grpc.ClientStreamingServer[CreateShortLinkRequest, BatchCreateShortLinksResponse]
+
 func _ShortenerService_GetAllShortLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetAllShortLinkRequest)
 	if err := dec(in); err != nil {
@@ -172,6 +311,71 @@ func _ShortenerService_GetAllShortLink_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortenerService_StreamShortLinks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamShortLinksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShortenerServiceServer).StreamShortLinks(m, &grpc.GenericServerStream[StreamShortLinksRequest, ShortLink]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ShortenerService_StreamShortLinksServer = grpc.ServerStreamingServer[ShortLink]
+
+func _ShortenerService_GetLinkStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLinkStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetLinkStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetLinkStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetLinkStats(ctx, req.(*GetLinkStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_UpdateShortLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateShortLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).UpdateShortLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_UpdateShortLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).UpdateShortLink(ctx, req.(*UpdateShortLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_DeleteShortLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteShortLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).DeleteShortLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_DeleteShortLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).DeleteShortLink(ctx, req.(*DeleteShortLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ShortenerService_ServiceDesc is the grpc.ServiceDesc for ShortenerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -187,11 +391,38 @@ var ShortenerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetLongURL",
 			Handler:    _ShortenerService_GetLongURL_Handler,
 		},
+		{
+			MethodName: "BatchCreateShortLinks",
+			Handler:    _ShortenerService_BatchCreateShortLinks_Handler,
+		},
 		{
 			MethodName: "GetAllShortLink",
 			Handler:    _ShortenerService_GetAllShortLink_Handler,
 		},
+		{
+			MethodName: "GetLinkStats",
+			Handler:    _ShortenerService_GetLinkStats_Handler,
+		},
+		{
+			MethodName: "UpdateShortLink",
+			Handler:    _ShortenerService_UpdateShortLink_Handler,
+		},
+		{
+			MethodName: "DeleteShortLink",
+			Handler:    _ShortenerService_DeleteShortLink_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchCreateShortLinksStream",
+			Handler:       _ShortenerService_BatchCreateShortLinksStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "StreamShortLinks",
+			Handler:       _ShortenerService_StreamShortLinks_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/shortener.proto",
 }
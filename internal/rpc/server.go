@@ -6,10 +6,16 @@ import (
 	"log"
 	"net"
 
+	"github.com/username/shorturl/internal/auth"
 	"github.com/username/shorturl/internal/config"
 	"github.com/username/shorturl/internal/manager"
+	"github.com/username/shorturl/internal/ratelimit"
+	"github.com/username/shorturl/internal/repository"
 	shortenerpb "github.com/username/shorturl/internal/rpc/proto"
+	colipboard "github.com/username/shorturl/internal/rpc/service/colipboard"
 	shortener "github.com/username/shorturl/internal/rpc/service/shortener"
+	"github.com/username/shorturl/internal/tenant"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
@@ -20,7 +26,7 @@ func NewGRPCServer() *grpc.Server {
 	// cui := grpc_middleware.ChainUnaryServer(TimeoutInterceptor(), DBUnaryInterceptor(), ui, middleware.RecoveredUnaryGRPCServerLog())
 	// grpc.UnaryInterceptor() 创造一个拦截器
 	// grpc.NewServer(可以传入一个具体的拦截器或者拦截器链)
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(serverOptions()...)
 	// 反向注册服务
 	shortenerpb.RegisterShortenerServiceServer(grpcServer, &shortener.Server{})
 
@@ -29,12 +35,54 @@ func NewGRPCServer() *grpc.Server {
 	return grpcServer
 }
 
+// NewClipboarderGRPCServer 创建 Clipboarder 服务的 gRPC Server，监听独立端口
+func NewClipboarderGRPCServer() *grpc.Server {
+	grpcServer := grpc.NewServer(serverOptions()...)
+	shortenerpb.RegisterClipboarderServiceServer(grpcServer, &colipboard.Server{})
+
+	reflection.Register(grpcServer)
+
+	return grpcServer
+}
+
+// serverOptions 两个 gRPC Server 共用的拦截器
+func serverOptions() []grpc.ServerOption {
+	authenticator, err := auth.FromConfig(config.GetConfig())
+	if err != nil {
+		log.Fatalf("初始化认证失败: %v", err)
+	}
+	if authenticator == nil {
+		log.Println("WARNING: gRPC authentication is disabled")
+	}
+	dataSources, _ := repository.GetDataSources()
+	limiter, err := ratelimit.FromConfig(config.GetConfig(), dataSources.RedisCache)
+	if err != nil {
+		log.Fatalf("初始化限流失败: %v", err)
+	}
+	// 限流在认证和工作区之后执行，规则可以按调用方身份和工作区计数
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(authenticator.UnaryServerInterceptor(), tenant.UnaryServerInterceptor(), limiter.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(authenticator.StreamServerInterceptor(), tenant.StreamServerInterceptor(), limiter.StreamServerInterceptor()),
+	}
+}
+
 func RunGRPCServer(ctx context.Context, clientManager *manager.ClientManager) (err error) {
-	GRPCServer := NewGRPCServer()
+	g, gCtx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		return serveGRPC(gCtx, NewGRPCServer(), config.GetConfig().RPC.Shortneer.Addr)
+	})
+	g.Go(func() error {
+		return serveGRPC(gCtx, NewClipboarderGRPCServer(), config.GetConfig().RPC.Clipboarder.Addr)
+	})
+
+	return g.Wait()
+}
 
-	// grpcLis
-	grpcLis, err := net.Listen("tcp", config.GetConfig().RPC.Shortneer.Addr)
-	log.Println("config.GetConfig().RPC.Shortneer.Addr")
+// serveGRPC 在指定地址启动 gRPC Server，ctx 取消时优雅停止
+func serveGRPC(ctx context.Context, grpcServer *grpc.Server, addr string) error {
+	grpcLis, err := net.Listen("tcp", addr)
+	log.Println("gRPC server listening on", addr)
 	if err != nil {
 		return fmt.Errorf("failed to listen: %v", err)
 	}
@@ -42,8 +90,8 @@ func RunGRPCServer(ctx context.Context, clientManager *manager.ClientManager) (e
 	go func() {
 		<-ctx.Done()
 		// 当接收到 主线程的context被取消时，则会销毁grpcServer本身，会先把当前的grpc处理完成才会取消
-		GRPCServer.GracefulStop()
+		grpcServer.GracefulStop()
 		grpcLis.Close()
 	}()
-	return GRPCServer.Serve(grpcLis)
+	return grpcServer.Serve(grpcLis)
 }
@@ -1 +1,21 @@
 package colipboard
+
+import (
+	"context"
+
+	clipboarderpb "github.com/username/shorturl/internal/rpc/proto"
+	clipboard "github.com/username/shorturl/internal/service/clipboard"
+)
+
+type Server struct {
+	clipboarderpb.UnimplementedClipboarderServiceServer
+	service *clipboard.Service
+}
+
+func (s *Server) SaveClipboard(ctx context.Context, req *clipboarderpb.SaveClipboardRequest) (*clipboarderpb.SaveClipboardResponse, error) {
+	return s.service.SaveClipboard(ctx, req)
+}
+
+func (s *Server) GetClipboard(ctx context.Context, req *clipboarderpb.GetClipboardRequest) (*clipboarderpb.GetClipboardResponse, error) {
+	return s.service.GetClipboard(ctx, req)
+}
@@ -2,11 +2,12 @@ package service
 
 import (
 	"context"
-	"log"
+	"io"
 	"time"
 
 	shorturlpb "github.com/username/shorturl/internal/rpc/proto"
 	shortener "github.com/username/shorturl/internal/service/shortener"
+	"google.golang.org/grpc/status"
 )
 
 type Server struct {
@@ -15,10 +16,7 @@ type Server struct {
 }
 
 func (s *Server) CreateShortLink(ctx context.Context, req *shorturlpb.CreateShortLinkRequest) (*shorturlpb.CreateShortLinkResponse, error) {
-	var time time.Duration = time.Second * 100
-	shortURLModel, err := s.service.CreateShortLink(ctx, req.GetLongUrl(), &time)
-
-	log.Println(shortURLModel)
+	shortURLModel, err := s.service.CreateShortLink(ctx, req.GetLongUrl(), createOptions(req))
 	if err != nil {
 		return nil, err
 	}
@@ -26,11 +24,101 @@ func (s *Server) CreateShortLink(ctx context.Context, req *shorturlpb.CreateShor
 
 	if shortURLModel != nil {
 		response.ShortKey = shortURLModel.ShortCode
+		response.ShortUrl = s.service.ShortURL(ctx, shortURLModel)
+		if shortURLModel.ExpiresAt != nil {
+			response.ExpiresAt = shortURLModel.ExpiresAt.Unix()
+		}
 	}
 
 	return response, err
 }
 
+// createOptions 将创建请求转换为 service 层的可选参数
+func createOptions(req *shorturlpb.CreateShortLinkRequest) shortener.CreateOptions {
+	opts := shortener.CreateOptions{
+		ExpiresIn:    time.Duration(req.GetExpiresIn()) * time.Second,
+		NeverExpires: req.GetNeverExpires(),
+		RedirectCode: int(req.GetRedirectCode()),
+		CustomAlias:  req.GetCustomAlias(),
+	}
+	if req.GetExpiresAt() != 0 {
+		expiresAt := time.Unix(req.GetExpiresAt(), 0)
+		opts.ExpiresAt = &expiresAt
+	}
+	return opts
+}
+
+func (s *Server) BatchCreateShortLinks(ctx context.Context, req *shorturlpb.BatchCreateShortLinksRequest) (*shorturlpb.BatchCreateShortLinksResponse, error) {
+	response := &shorturlpb.BatchCreateShortLinksResponse{}
+	if err := s.createBatch(ctx, req.GetItems(), 0, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// BatchCreateShortLinksStream 边接收边处理，每累积 MaxBatchSize 条写入一批，内存中最多保留一批请求
+func (s *Server) BatchCreateShortLinksStream(stream shorturlpb.ShortenerService_BatchCreateShortLinksStreamServer) error {
+	ctx := stream.Context()
+	response := &shorturlpb.BatchCreateShortLinksResponse{}
+
+	var batch []*shorturlpb.CreateShortLinkRequest
+	offset := 0
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		batch = append(batch, req)
+		if len(batch) == shortener.MaxBatchSize {
+			if err := s.createBatch(ctx, batch, offset, response); err != nil {
+				return err
+			}
+			offset += len(batch)
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		if err := s.createBatch(ctx, batch, offset, response); err != nil {
+			return err
+		}
+	}
+	return stream.SendAndClose(response)
+}
+
+// createBatch 处理一批请求，并把结果追加到 response，offset 为这一批第一条的序号
+func (s *Server) createBatch(ctx context.Context, reqs []*shorturlpb.CreateShortLinkRequest, offset int, response *shorturlpb.BatchCreateShortLinksResponse) error {
+	items := make([]shortener.BatchItem, len(reqs))
+	for i, req := range reqs {
+		items[i] = shortener.BatchItem{LongURL: req.GetLongUrl(), Opts: createOptions(req)}
+	}
+	results, err := s.service.BatchCreateShortLinks(ctx, items)
+	if err != nil {
+		return err
+	}
+
+	for i, res := range results {
+		result := &shorturlpb.BatchCreateResult{Index: int32(offset + i)}
+		if res.Err != nil {
+			st := status.Convert(res.Err)
+			result.Code = int32(st.Code())
+			result.Error = st.Message()
+			response.Failed++
+		} else {
+			result.ShortKey = res.URL.ShortCode
+			result.ShortUrl = s.service.ShortURL(ctx, res.URL)
+			if res.URL.ExpiresAt != nil {
+				result.ExpiresAt = res.URL.ExpiresAt.Unix()
+			}
+			response.Succeeded++
+		}
+		response.Results = append(response.Results, result)
+	}
+	return nil
+}
+
 func (s *Server) GetLongURL(ctx context.Context, req *shorturlpb.GetLongURLRequest) (*shorturlpb.GetLongURLResponse, error) {
 	return s.service.GetLongURL(ctx, req)
 }
@@ -38,3 +126,19 @@ func (s *Server) GetLongURL(ctx context.Context, req *shorturlpb.GetLongURLReque
 func (s *Server) GetAllShortLink(ctx context.Context, req *shorturlpb.GetAllShortLinkRequest) (*shorturlpb.GetAllShortLinkResponse, error) {
 	return s.service.GetAllShortLink(ctx, req)
 }
+
+func (s *Server) GetLinkStats(ctx context.Context, req *shorturlpb.GetLinkStatsRequest) (*shorturlpb.GetLinkStatsResponse, error) {
+	return s.service.GetLinkStats(ctx, req)
+}
+
+func (s *Server) UpdateShortLink(ctx context.Context, req *shorturlpb.UpdateShortLinkRequest) (*shorturlpb.UpdateShortLinkResponse, error) {
+	return s.service.UpdateShortLink(ctx, req)
+}
+
+func (s *Server) DeleteShortLink(ctx context.Context, req *shorturlpb.DeleteShortLinkRequest) (*shorturlpb.DeleteShortLinkResponse, error) {
+	return s.service.DeleteShortLink(ctx, req)
+}
+
+func (s *Server) StreamShortLinks(req *shorturlpb.StreamShortLinksRequest, stream shorturlpb.ShortenerService_StreamShortLinksServer) error {
+	return s.service.StreamShortLinks(req, stream)
+}
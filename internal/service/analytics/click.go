@@ -0,0 +1,60 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/username/shorturl/internal/db/model"
+	shorturlpb "github.com/username/shorturl/internal/rpc/proto"
+)
+
+// maxFieldLength referrer / user agent 保存的最大长度
+const maxFieldLength = 512
+
+// NewClick 根据访问者信息创建点击事件，原始 IP 只用于计算哈希和网段，不会保存
+func NewClick(workspaceID int64, shortCode string, visitor *shorturlpb.Visitor, clickedAt time.Time) model.Click {
+	ipHash, ipPrefix := anonymizeIP(visitor.GetIp())
+	return model.Click{
+		WorkspaceID: workspaceID,
+		ShortCode:   shortCode,
+		ClickedAt:   clickedAt,
+		Referrer:    truncate(visitor.GetReferrer(), maxFieldLength),
+		UserAgent:   truncate(visitor.GetUserAgent(), maxFieldLength),
+		IPHash:      ipHash,
+		IPPrefix:    ipPrefix,
+		Country:     strings.ToUpper(truncate(visitor.GetCountry(), 2)),
+	}
+}
+
+// anonymizeIP 返回 IP 的哈希以及匿名化后的网段（IPv4 /24，IPv6 /48）
+func anonymizeIP(raw string) (hash, prefix string) {
+	ip := net.ParseIP(strings.TrimSpace(raw))
+	if ip == nil {
+		return "", ""
+	}
+
+	sum := sha256.Sum256(ip)
+	hash = hex.EncodeToString(sum[:8])
+
+	if v4 := ip.To4(); v4 != nil {
+		prefix = v4.Mask(net.CIDRMask(24, 32)).String() + "/24"
+	} else {
+		prefix = ip.Mask(net.CIDRMask(48, 128)).String() + "/48"
+	}
+	return hash, prefix
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	// 避免截断在多字节字符中间
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	shorturlpb "github.com/username/shorturl/internal/rpc/proto"
+)
+
+// TestAnonymizeIP 测试 IP 只保留哈希和网段
+func TestAnonymizeIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		ip         string
+		wantPrefix string
+	}{
+		{name: "IPv4", ip: "203.0.113.45", wantPrefix: "203.0.113.0/24"},
+		{name: "IPv4 前后空白", ip: " 203.0.113.45\n", wantPrefix: "203.0.113.0/24"},
+		{name: "IPv4 映射的 IPv6", ip: "::ffff:203.0.113.45", wantPrefix: "203.0.113.0/24"},
+		{name: "IPv6", ip: "2001:db8:abcd:12::1", wantPrefix: "2001:db8:abcd::/48"},
+		{name: "空", ip: ""},
+		{name: "不是 IP", ip: "localhost"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, prefix := anonymizeIP(tt.ip)
+			if prefix != tt.wantPrefix {
+				t.Errorf("anonymizeIP(%q) prefix = %q, want %q", tt.ip, prefix, tt.wantPrefix)
+			}
+			if tt.wantPrefix == "" {
+				if hash != "" {
+					t.Errorf("anonymizeIP(%q) hash = %q, want empty", tt.ip, hash)
+				}
+				return
+			}
+			if len(hash) != 16 || strings.Contains(hash, strings.TrimSpace(tt.ip)) {
+				t.Errorf("anonymizeIP(%q) hash = %q, want 16 hex digits", tt.ip, hash)
+			}
+		})
+	}
+
+	// 同一 IP 的哈希相同，同一网段的不同 IP 哈希不同
+	a, _ := anonymizeIP("203.0.113.45")
+	b, _ := anonymizeIP("::ffff:203.0.113.45")
+	c, _ := anonymizeIP("203.0.113.46")
+	if a != b || a == c {
+		t.Errorf("hashes = %s, %s, %s, want first two equal and the third different", a, b, c)
+	}
+}
+
+// TestTruncate 测试按字节截断且不拆开多字节字符
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		n    int
+		want string
+	}{
+		{name: "未超长", s: "abc", n: 3, want: "abc"},
+		{name: "ASCII", s: "abcdef", n: 4, want: "abcd"},
+		{name: "截断点在多字节字符中间", s: "ab中文", n: 4, want: "ab"},
+		{name: "截断点在字符边界", s: "ab中文", n: 5, want: "ab中"},
+		{name: "长度为 0", s: "abc", n: 0, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncate(tt.s, tt.n); got != tt.want {
+				t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNewClick 测试点击事件不保存原始 IP，字段按长度截断
+func TestNewClick(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	click := NewClick(3, "abc", &shorturlpb.Visitor{
+		Ip:        "203.0.113.45",
+		Referrer:  strings.Repeat("r", maxFieldLength+10),
+		UserAgent: "curl/8.0",
+		Country:   "cn",
+	}, at)
+
+	if click.WorkspaceID != 3 || click.ShortCode != "abc" || !click.ClickedAt.Equal(at) {
+		t.Errorf("NewClick() = %+v, want workspace 3, code abc", click)
+	}
+	if len(click.Referrer) != maxFieldLength || click.UserAgent != "curl/8.0" || click.Country != "CN" {
+		t.Errorf("NewClick() referrer length %d, user agent %q, country %q", len(click.Referrer), click.UserAgent, click.Country)
+	}
+	if click.IPPrefix != "203.0.113.0/24" || click.IPHash == "" {
+		t.Errorf("NewClick() ip hash %q, prefix %q", click.IPHash, click.IPPrefix)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+)
+
+const (
+	// defaultBufferSize 点击事件队列长度，队列满时丢弃新事件，保证跳转不被阻塞
+	defaultBufferSize = 10000
+	// defaultBatchSize 单次批量写入的最大条数
+	defaultBatchSize = 200
+	// defaultFlushInterval 未凑满一批时的最长等待时间
+	defaultFlushInterval = time.Second
+)
+
+// Recorder 异步记录点击事件，后台批量写入数据库
+type Recorder struct {
+	repo          repository.ClickRepository
+	events        chan model.Click
+	batchSize     int
+	flushInterval time.Duration
+
+	dropped atomic.Int64
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewRecorder 创建点击记录器并启动后台写入 goroutine
+func NewRecorder(repo repository.ClickRepository, bufferSize, batchSize int, flushInterval time.Duration) *Recorder {
+	r := &Recorder{
+		repo:          repo,
+		events:        make(chan model.Click, bufferSize),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		done:          make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+var (
+	recorderOnce    sync.Once
+	defaultRecorder *Recorder
+)
+
+// GetRecorder 获取进程内共享的点击记录器
+func GetRecorder() *Recorder {
+	recorderOnce.Do(func() {
+		dataSources, _ := repository.GetDataSources()
+		defaultRecorder = NewRecorder(repository.NewClickRepository(dataSources),
+			defaultBufferSize, defaultBatchSize, defaultFlushInterval)
+	})
+	return defaultRecorder
+}
+
+// Record 提交点击事件，不会阻塞调用方；队列已满时丢弃并计数
+func (r *Recorder) Record(click model.Click) {
+	defer func() {
+		// Close 之后提交的事件直接丢弃
+		if recover() != nil {
+			r.dropped.Add(1)
+		}
+	}()
+
+	select {
+	case r.events <- click:
+	default:
+		if n := r.dropped.Add(1); n%1000 == 1 {
+			log.Printf("click queue full, %d events dropped so far", n)
+		}
+	}
+}
+
+// Dropped 返回因队列已满而丢弃的事件数
+func (r *Recorder) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Close 停止接收新事件，并把队列中剩余的事件写入数据库
+func (r *Recorder) Close() {
+	r.closeOnce.Do(func() {
+		close(r.events)
+	})
+	<-r.done
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]model.Click, 0, r.batchSize)
+	for {
+		select {
+		case click, ok := <-r.events:
+			if !ok {
+				r.flush(batch)
+				return
+			}
+			batch = append(batch, click)
+			if len(batch) >= r.batchSize {
+				r.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				r.flush(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+func (r *Recorder) flush(batch []model.Click) {
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.repo.SaveBatch(ctx, batch); err != nil {
+		log.Printf("failed to flush %d click events: %v", len(batch), err)
+	}
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+)
+
+// fakeClickRepository 记录每次 SaveBatch 的数据；release 非 nil 时 SaveBatch 阻塞到 release 关闭
+type fakeClickRepository struct {
+	repository.ClickRepository
+
+	release chan struct{}
+	saving  chan struct{}
+
+	mu      sync.Mutex
+	batches [][]model.Click
+}
+
+func (f *fakeClickRepository) SaveBatch(ctx context.Context, clicks []model.Click) error {
+	if f.release != nil {
+		f.saving <- struct{}{}
+		<-f.release
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, slices.Clone(clicks))
+	return nil
+}
+
+// sizes 每次写入的条数，以及按写入顺序排列的短码
+func (f *fakeClickRepository) sizes() ([]int, []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var sizes []int
+	var codes []string
+	for _, batch := range f.batches {
+		sizes = append(sizes, len(batch))
+		for _, c := range batch {
+			codes = append(codes, c.ShortCode)
+		}
+	}
+	return sizes, codes
+}
+
+func clicks(n int) []model.Click {
+	out := make([]model.Click, n)
+	for i := range out {
+		out[i] = model.Click{ShortCode: fmt.Sprintf("c%d", i)}
+	}
+	return out
+}
+
+// TestRecorderBatches 测试凑满一批时写入，Close 时写入剩余的事件
+func TestRecorderBatches(t *testing.T) {
+	repo := &fakeClickRepository{}
+	r := NewRecorder(repo, 100, 3, time.Hour)
+	for _, c := range clicks(7) {
+		r.Record(c)
+	}
+	r.Close()
+
+	sizes, codes := repo.sizes()
+	if !slices.Equal(sizes, []int{3, 3, 1}) {
+		t.Errorf("batch sizes = %v, want [3 3 1]", sizes)
+	}
+	if want := []string{"c0", "c1", "c2", "c3", "c4", "c5", "c6"}; !slices.Equal(codes, want) {
+		t.Errorf("saved = %v, want %v", codes, want)
+	}
+	if r.Dropped() != 0 {
+		t.Errorf("Dropped() = %d, want 0", r.Dropped())
+	}
+}
+
+// TestRecorderFlushInterval 测试未凑满一批时按间隔写入
+func TestRecorderFlushInterval(t *testing.T) {
+	repo := &fakeClickRepository{}
+	r := NewRecorder(repo, 100, 100, 10*time.Millisecond)
+	defer r.Close()
+	for _, c := range clicks(2) {
+		r.Record(c)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if sizes, _ := repo.sizes(); slices.Equal(sizes, []int{2}) {
+			return
+		}
+		if time.Now().After(deadline) {
+			sizes, _ := repo.sizes()
+			t.Fatalf("batch sizes = %v after 1s, want [2] flushed by the ticker", sizes)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestRecorderDrops 测试写入阻塞、队列已满以及 Close 之后的事件被丢弃并计数，Close 写完队列中的事件
+func TestRecorderDrops(t *testing.T) {
+	repo := &fakeClickRepository{release: make(chan struct{}), saving: make(chan struct{}, 10)}
+	r := NewRecorder(repo, 2, 1, time.Hour)
+	events := clicks(6)
+
+	// 第一条被取出后写入阻塞，队列还能容纳两条，之后的三条被丢弃
+	r.Record(events[0])
+	<-repo.saving
+	for _, c := range events[1:] {
+		r.Record(c)
+	}
+	if r.Dropped() != 3 {
+		t.Errorf("Dropped() = %d, want 3", r.Dropped())
+	}
+
+	close(repo.release)
+	r.Close()
+	if _, codes := repo.sizes(); !slices.Equal(codes, []string{"c0", "c1", "c2"}) {
+		t.Errorf("saved = %v, want [c0 c1 c2]", codes)
+	}
+
+	r.Record(events[5])
+	if r.Dropped() != 4 {
+		t.Errorf("Dropped() after Close = %d, want 4", r.Dropped())
+	}
+	r.Close()
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/username/shorturl/internal/config"
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+	clipboarderpb "github.com/username/shorturl/internal/rpc/proto"
+	"github.com/username/shorturl/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxContentSize 单个片段允许的最大字节数
+const maxContentSize = 64 << 10
+
+type Service struct{}
+
+func (s *Service) SaveClipboard(ctx context.Context, req *clipboarderpb.SaveClipboardRequest) (*clipboarderpb.SaveClipboardResponse, error) {
+	// 1. 验证内容
+	content := req.GetClipboarderContent()
+	if content == "" {
+		return nil, status.Error(codes.InvalidArgument, "剪贴板内容不能为空")
+	}
+	if len(content) > maxContentSize {
+		return nil, status.Errorf(codes.InvalidArgument, "剪贴板内容不能超过 %d 字节", maxContentSize)
+	}
+
+	// 2. 生成短码
+	shortKey, err := utils.GenerateShortCode(8)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 创建模型
+	createdAt := time.Now()
+	clip := &model.Clipboard{
+		ShortKey:  shortKey,
+		Content:   content,
+		CreatedAt: createdAt,
+	}
+	if ttl := config.GetConfig().ClipboardTTL; ttl > 0 {
+		expiresAt := createdAt.Add(ttl)
+		clip.ExpiresAt = &expiresAt
+	}
+
+	// 4. 写入缓存和数据库
+	dataSources, err := repository.GetDataSources()
+	if err != nil {
+		return nil, err
+	}
+	clipboardRepository := repository.NewClipboardRepository(dataSources)
+	if err := clipboardRepository.Save(ctx, clip); err != nil {
+		return nil, status.Errorf(codes.Internal, "保存剪贴板失败: %v", err)
+	}
+
+	// 5. 返回结果
+	return &clipboarderpb.SaveClipboardResponse{ShortKey: shortKey}, nil
+}
+
+func (s *Service) GetClipboard(ctx context.Context, req *clipboarderpb.GetClipboardRequest) (*clipboarderpb.GetClipboardResponse, error) {
+	shortKey := req.GetShortKey()
+	if shortKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_key 不能为空")
+	}
+
+	dataSources, err := repository.GetDataSources()
+	if err != nil {
+		return nil, err
+	}
+	clipboardRepository := repository.NewClipboardRepository(dataSources)
+
+	clip, err := clipboardRepository.Get(ctx, shortKey)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return &clipboarderpb.GetClipboardResponse{IsFound: false}, nil
+		}
+		return nil, err
+	}
+
+	return &clipboarderpb.GetClipboardResponse{GetClipboard: clip.Content, IsFound: true}, nil
+}
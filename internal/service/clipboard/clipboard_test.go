@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/username/shorturl/internal/cache"
+	"github.com/username/shorturl/internal/repository"
+	clipboarderpb "github.com/username/shorturl/internal/rpc/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestClipboard 测试保存后按短码读取，以及参数校验和不存在的短码
+func TestClipboard(t *testing.T) {
+	ctx := context.Background()
+	memory, err := cache.NewMemoryCache()
+	if err != nil {
+		t.Fatalf("NewMemoryCache() error = %v", err)
+	}
+	previous := repository.GloablDataSources
+	repository.GloablDataSources = &repository.DataSources{MemoryCache: memory}
+	t.Cleanup(func() { repository.GloablDataSources = previous })
+	s := &Service{}
+
+	saveTests := []struct {
+		name     string
+		content  string
+		wantCode codes.Code
+	}{
+		{name: "普通内容", content: "hello, 世界", wantCode: codes.OK},
+		{name: "最大长度", content: strings.Repeat("a", maxContentSize), wantCode: codes.OK},
+		{name: "空内容", content: "", wantCode: codes.InvalidArgument},
+		{name: "超过最大长度", content: strings.Repeat("a", maxContentSize+1), wantCode: codes.InvalidArgument},
+	}
+	for _, tt := range saveTests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved, err := s.SaveClipboard(ctx, &clipboarderpb.SaveClipboardRequest{ClipboarderContent: tt.content})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("SaveClipboard() error = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			got, err := s.GetClipboard(ctx, &clipboarderpb.GetClipboardRequest{ShortKey: saved.GetShortKey()})
+			if err != nil || !got.GetIsFound() || got.GetGetClipboard() != tt.content {
+				t.Errorf("GetClipboard(%s) = %v, %v, want the saved content", saved.GetShortKey(), got, err)
+			}
+		})
+	}
+
+	if got, err := s.GetClipboard(ctx, &clipboarderpb.GetClipboardRequest{ShortKey: "missing"}); err != nil || got.GetIsFound() {
+		t.Errorf("GetClipboard(missing) = %v, %v, want not found", got, err)
+	}
+	if _, err := s.GetClipboard(ctx, &clipboarderpb.GetClipboardRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetClipboard() without key error = %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/username/shorturl/internal/config"
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+)
+
+// hostnamePattern 合法的主机名：至少两段，每段 1-63 个字母、数字或 -，不以 - 开头或结尾
+var hostnamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Registry 自定义域名登记表：把 Host 映射到工作区，并生成短链接的完整地址
+type Registry struct {
+	domains    repository.DomainRepository
+	workspaces repository.WorkspaceRepository
+	verifier   Verifier
+	// baseURL 没有主域名的工作区使用的地址前缀，不带结尾的 /
+	baseURL string
+	// scheme 自定义域名使用的协议
+	scheme string
+}
+
+// NewRegistry 创建 Registry
+func NewRegistry(domains repository.DomainRepository, workspaces repository.WorkspaceRepository, verifier Verifier, baseURL, scheme string) *Registry {
+	if scheme == "" {
+		scheme = "https"
+	}
+	return &Registry{
+		domains:    domains,
+		workspaces: workspaces,
+		verifier:   verifier,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		scheme:     scheme,
+	}
+}
+
+var (
+	registryOnce    sync.Once
+	defaultRegistry *Registry
+)
+
+// GetRegistry 获取进程内共享的 Registry
+func GetRegistry() *Registry {
+	registryOnce.Do(func() {
+		cfg := config.GetConfig().Domains
+		verifier, err := NewVerifier(cfg.Verifier)
+		if err != nil {
+			log.Printf("%v，使用 dns", err)
+			verifier = &DNSVerifier{}
+		}
+		dataSources, _ := repository.GetDataSources()
+		defaultRegistry = NewRegistry(repository.NewDomainRepository(dataSources), repository.NewWorkspaceRepository(dataSources),
+			verifier, cfg.BaseURL, cfg.Scheme)
+	})
+	return defaultRegistry
+}
+
+// NormalizeHost 把 Host 请求头转换为登记表中的主机名：去掉端口和结尾的 .，转换为小写
+func NormalizeHost(host string) string {
+	host = strings.TrimSpace(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// Add 把域名登记到工作区，返回的 VerificationToken 需要按 Verifier 的要求发布后再调用 Verify
+func (r *Registry) Add(ctx context.Context, hostname, workspaceSlug string, primary bool) (*model.Domain, error) {
+	hostname = NormalizeHost(hostname)
+	if !hostnamePattern.MatchString(hostname) {
+		return nil, fmt.Errorf("invalid hostname: %q", hostname)
+	}
+	ws, err := r.workspaces.GetBySlug(ctx, workspaceSlug)
+	if err != nil {
+		return nil, err
+	}
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	domain := &model.Domain{
+		Hostname:          hostname,
+		WorkspaceID:       ws.ID,
+		VerificationToken: hex.EncodeToString(token),
+	}
+	if err := r.domains.Create(ctx, domain); err != nil {
+		return nil, err
+	}
+	if primary {
+		if err := r.domains.SetPrimary(ctx, hostname); err != nil {
+			return nil, err
+		}
+		domain.Primary = true
+	}
+	return domain, nil
+}
+
+// Verify 通过 Verifier 验证域名所有权，成功后域名开始用于跳转和生成短链接地址
+func (r *Registry) Verify(ctx context.Context, hostname string) error {
+	domain, err := r.domains.GetByHostname(ctx, NormalizeHost(hostname))
+	if err != nil {
+		return err
+	}
+	if err := r.verifier.Verify(ctx, domain.Hostname, domain.VerificationToken); err != nil {
+		return err
+	}
+	return r.domains.MarkVerified(ctx, domain.Hostname)
+}
+
+// Resolve 返回 Host 对应的工作区
+// 未登记的 Host（包括服务自身的域名）返回 nil, nil，由调用方使用默认工作区；已登记但未验证的域名返回 ErrNotVerified
+func (r *Registry) Resolve(ctx context.Context, host string) (*model.Workspace, error) {
+	hostname := NormalizeHost(host)
+	if hostname == "" {
+		return nil, nil
+	}
+	domain, err := r.domains.GetByHostname(ctx, hostname)
+	if err != nil {
+		if errors.Is(err, repository.ErrDomainNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !domain.Verified() {
+		return nil, fmt.Errorf("%w: %s", ErrNotVerified, hostname)
+	}
+	return r.workspaces.GetByID(ctx, domain.WorkspaceID)
+}
+
+// ShortURL 返回短链接的完整地址：工作区有已验证的主域名时使用主域名，否则使用 baseURL
+// 查询主域名失败时使用 baseURL，不影响短链接的创建
+func (r *Registry) ShortURL(ctx context.Context, workspaceID int64, shortCode string) string {
+	return r.BaseURL(ctx, workspaceID) + "/" + shortCode
+}
+
+// BaseURL 返回工作区短链接地址的前缀（不含末尾的 /），规则同 ShortURL
+// 同一工作区的多个短链接（如列表、导出）只需查询一次主域名
+func (r *Registry) BaseURL(ctx context.Context, workspaceID int64) string {
+	if workspaceID != model.DefaultWorkspaceID {
+		domain, err := r.domains.Primary(ctx, workspaceID)
+		if err != nil {
+			log.Printf("failed to get primary domain of workspace %d: %v", workspaceID, err)
+		} else if domain != nil {
+			return r.scheme + "://" + domain.Hostname
+		}
+	}
+	return r.baseURL
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/username/shorturl/internal/db"
+	"github.com/username/shorturl/internal/db/migrate"
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+)
+
+// stubVerifier 按 ok 决定验证结果，记录收到的 token
+type stubVerifier struct {
+	ok    bool
+	token string
+}
+
+func (v *stubVerifier) Verify(ctx context.Context, hostname, token string) error {
+	v.token = token
+	if !v.ok {
+		return ErrNotVerified
+	}
+	return nil
+}
+
+func newTestRegistry(t *testing.T, verifier Verifier) (*Registry, repository.WorkspaceRepository) {
+	t.Helper()
+	database, err := db.NewSQLiteDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteDB() error = %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if err := migrate.UpAll(context.Background(), database); err != nil {
+		t.Fatalf("UpAll() error = %v", err)
+	}
+	sources := &repository.DataSources{SQLiteDB: database}
+	workspaces := repository.NewWorkspaceRepository(sources)
+	return NewRegistry(repository.NewDomainRepository(sources), workspaces, verifier, "http://localhost:8080/", "https"), workspaces
+}
+
+// TestRegistry 测试域名登记、验证后才映射到工作区，以及短链接地址使用已验证的主域名
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	verifier := &stubVerifier{}
+	registry, workspaces := newTestRegistry(t, verifier)
+	ws := &model.Workspace{Slug: "registry-test", Name: "Registry Test"}
+	if err := workspaces.Create(ctx, ws); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	const host = "go.registry-test.example"
+	d, err := registry.Add(ctx, "Go.Registry-Test.example.:443", ws.Slug, true)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if d.Hostname != host || d.VerificationToken == "" {
+		t.Fatalf("Add() = %+v, want hostname %s with a token", d, host)
+	}
+	if _, err := registry.Add(ctx, host, ws.Slug, false); !errors.Is(err, repository.ErrDomainExists) {
+		t.Errorf("Add() duplicate error = %v, want ErrDomainExists", err)
+	}
+	if _, err := registry.Add(ctx, "not a host", ws.Slug, false); err == nil {
+		t.Error("Add() invalid hostname error = nil")
+	}
+
+	// 未验证：不参与跳转，也不用于生成地址
+	if _, err := registry.Resolve(ctx, host); !errors.Is(err, ErrNotVerified) {
+		t.Errorf("Resolve() before Verify error = %v, want ErrNotVerified", err)
+	}
+	if got, want := registry.ShortURL(ctx, ws.ID, "abc"), "http://localhost:8080/abc"; got != want {
+		t.Errorf("ShortURL() before Verify = %s, want %s", got, want)
+	}
+	if err := registry.Verify(ctx, host); !errors.Is(err, ErrNotVerified) {
+		t.Errorf("Verify() error = %v, want ErrNotVerified", err)
+	}
+	if verifier.token != d.VerificationToken {
+		t.Errorf("verifier got token %q, want %q", verifier.token, d.VerificationToken)
+	}
+
+	verifier.ok = true
+	if err := registry.Verify(ctx, host); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	got, err := registry.Resolve(ctx, host+":8080")
+	if err != nil || got == nil || got.ID != ws.ID {
+		t.Errorf("Resolve() = %+v, %v, want workspace %d", got, err, ws.ID)
+	}
+	if got, want := registry.ShortURL(ctx, ws.ID, "abc"), "https://"+host+"/abc"; got != want {
+		t.Errorf("ShortURL() = %s, want %s", got, want)
+	}
+	if got, want := registry.ShortURL(ctx, model.DefaultWorkspaceID, "abc"), "http://localhost:8080/abc"; got != want {
+		t.Errorf("ShortURL() default workspace = %s, want %s", got, want)
+	}
+
+	// 未登记的 Host 由调用方使用默认工作区
+	if got, err := registry.Resolve(ctx, "localhost:8080"); got != nil || err != nil {
+		t.Errorf("Resolve() unknown host = %+v, %v, want nil, nil", got, err)
+	}
+}
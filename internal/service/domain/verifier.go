@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ErrNotVerified 未能确认调用方拥有域名
+var ErrNotVerified = errors.New("domain ownership not verified")
+
+// verifyRecordPrefix、verifyValuePrefix DNS 验证时需要发布的 TXT 记录：_shorturl-verify.<域名> TXT "shorturl-verify=<token>"
+const (
+	verifyRecordPrefix = "_shorturl-verify."
+	verifyValuePrefix  = "shorturl-verify="
+)
+
+// Verifier 验证域名所有权，测试中可以替换为桩实现
+type Verifier interface {
+	// Verify 检查域名上是否发布了 token，未发布或不匹配时返回 ErrNotVerified
+	Verify(ctx context.Context, hostname, token string) error
+}
+
+// NewVerifier 按配置创建 Verifier：dns | none
+func NewVerifier(kind string) (Verifier, error) {
+	switch kind {
+	case "", "dns":
+		return &DNSVerifier{}, nil
+	case "none":
+		return NoopVerifier{}, nil
+	default:
+		return nil, fmt.Errorf("unknown domain verifier: %s", kind)
+	}
+}
+
+// DNSVerifier 通过 TXT 记录验证域名所有权
+type DNSVerifier struct {
+	// Resolver 为 nil 时使用 net.DefaultResolver
+	Resolver *net.Resolver
+}
+
+// VerificationRecord 返回需要发布的 TXT 记录名和值
+func VerificationRecord(hostname, token string) (name, value string) {
+	return verifyRecordPrefix + hostname, verifyValuePrefix + token
+}
+
+func (v *DNSVerifier) Verify(ctx context.Context, hostname, token string) error {
+	resolver := v.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	name, want := VerificationRecord(hostname, token)
+	records, err := resolver.LookupTXT(ctx, name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return fmt.Errorf("%w: TXT record %s not found", ErrNotVerified, name)
+		}
+		return fmt.Errorf("failed to look up TXT record %s: %w", name, err)
+	}
+	for _, record := range records {
+		if strings.TrimSpace(record) == want {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: TXT record %s does not contain %q", ErrNotVerified, name, want)
+}
+
+// NoopVerifier 不做验证，总是通过；仅用于开发和测试环境
+type NoopVerifier struct{}
+
+func (NoopVerifier) Verify(ctx context.Context, hostname, token string) error {
+	return nil
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/username/shorturl/internal/config"
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+)
+
+// maxConsecutiveFailures 连续失败次数达到上限时结束本轮对账（通常是 MySQL 再次不可用）
+const maxConsecutiveFailures = 10
+
+// Report 一轮对账的结果
+type Report struct {
+	Inserted       int64
+	Identical      int64
+	KeptMySQL      int64
+	KeptSQLite     int64
+	Failed         int64
+	ClicksReplayed int64
+}
+
+// Conflicts 本轮解决的冲突数
+func (r Report) Conflicts() int64 {
+	return r.KeptMySQL + r.KeptSQLite
+}
+
+func (r Report) empty() bool {
+	return r == Report{}
+}
+
+func (r *Report) add(o Report) {
+	r.Inserted += o.Inserted
+	r.Identical += o.Identical
+	r.KeptMySQL += o.KeptMySQL
+	r.KeptSQLite += o.KeptSQLite
+	r.Failed += o.Failed
+	r.ClicksReplayed += o.ClicksReplayed
+}
+
+// Stats 对账器的累计状态
+type Stats struct {
+	// MySQLUp 最近一次检查时 MySQL 是否可用
+	MySQLUp   bool
+	Runs      int64
+	LastRunAt time.Time
+	LastError string
+	// Total 启动以来的累计结果
+	Total Report
+}
+
+// Reconciler 定期检查 MySQL 是否恢复，恢复后把写入 SQLite 的回退数据回放到 MySQL
+type Reconciler struct {
+	repo      repository.ReconcileRepository
+	urlRepo   repository.URLRepository
+	interval  time.Duration
+	batchSize int
+	host      string
+
+	mu    sync.Mutex
+	stats Stats
+}
+
+// NewReconciler 创建对账器，需要调用 Run 启动
+func NewReconciler(repo repository.ReconcileRepository, urlRepo repository.URLRepository, interval time.Duration, batchSize int) *Reconciler {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = 200
+	}
+	host, _ := os.Hostname()
+	return &Reconciler{
+		repo:      repo,
+		urlRepo:   urlRepo,
+		interval:  interval,
+		batchSize: batchSize,
+		host:      host,
+	}
+}
+
+var (
+	reconcilerOnce    sync.Once
+	defaultReconciler *Reconciler
+)
+
+// GetReconciler 获取按配置创建的进程内对账器
+func GetReconciler() *Reconciler {
+	reconcilerOnce.Do(func() {
+		dataSources, _ := repository.GetDataSources()
+		cfg := config.GetConfig().Reconcile
+		defaultReconciler = NewReconciler(repository.NewReconcileRepository(dataSources),
+			repository.NewURLRepository(dataSources), cfg.Interval, cfg.BatchSize)
+	})
+	return defaultReconciler
+}
+
+// Run 按间隔检查并对账，直到 ctx 取消
+func (r *Reconciler) Run(ctx context.Context) {
+	if !r.repo.Available() {
+		log.Println("reconciler disabled: MySQL or SQLite is not configured")
+		return
+	}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stats 返回当前状态的快照
+func (r *Reconciler) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// tick 检查 MySQL 状态，可用时执行一轮对账
+func (r *Reconciler) tick(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	err := r.repo.PingMySQL(pingCtx)
+	cancel()
+
+	r.mu.Lock()
+	wasUp := r.stats.MySQLUp
+	r.stats.MySQLUp = err == nil
+	r.mu.Unlock()
+
+	if err != nil {
+		if wasUp {
+			log.Printf("reconciler: MySQL unavailable, pausing: %v", err)
+		}
+		return
+	}
+	if !wasUp {
+		log.Println("reconciler: MySQL available, replaying SQLite fallback rows")
+	}
+
+	report, err := r.RunOnce(ctx)
+	if err != nil {
+		log.Printf("reconciler: run aborted: %v", err)
+	}
+	if !report.empty() {
+		log.Printf("reconciler: inserted=%d identical=%d conflicts=%d (kept_mysql=%d kept_sqlite=%d) failed=%d clicks=%d",
+			report.Inserted, report.Identical, report.Conflicts(), report.KeptMySQL, report.KeptSQLite, report.Failed, report.ClicksReplayed)
+	}
+}
+
+// RunOnce 执行一轮对账：先回放短链接，再回放点击事件
+// 单条失败会跳过并计数，连续失败过多或 ctx 取消时提前结束
+func (r *Reconciler) RunOnce(ctx context.Context) (Report, error) {
+	var report Report
+	err := r.reconcileShortURLs(ctx, &report)
+	if err == nil {
+		err = r.replayClicks(ctx, &report)
+	}
+
+	r.mu.Lock()
+	r.stats.Runs++
+	r.stats.LastRunAt = time.Now()
+	r.stats.LastError = ""
+	if err != nil {
+		r.stats.LastError = err.Error()
+	}
+	r.stats.Total.add(report)
+	r.mu.Unlock()
+
+	return report, err
+}
+
+func (r *Reconciler) reconcileShortURLs(ctx context.Context, report *Report) error {
+	var afterID int64
+	failures := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		urls, err := r.repo.PendingShortURLs(ctx, afterID, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range urls {
+			url := &urls[i]
+			afterID = url.ID
+
+			outcome, err := r.repo.ReconcileShortURL(ctx, url, r.host)
+			if err != nil {
+				report.Failed++
+				log.Printf("reconciler: failed to reconcile %s: %v", url.ShortCode, err)
+				if failures++; failures >= maxConsecutiveFailures {
+					return err
+				}
+				continue
+			}
+			failures = 0
+
+			switch outcome {
+			case repository.ReconcileInserted:
+				report.Inserted++
+			case repository.ReconcileIdentical:
+				report.Identical++
+			case repository.ReconcileKeptMySQL:
+				report.KeptMySQL++
+				r.onConflict(ctx, url, "MySQL")
+			case repository.ReconcileKeptSQLite:
+				report.KeptSQLite++
+				r.onConflict(ctx, url, "SQLite")
+			}
+		}
+		if len(urls) < r.batchSize {
+			return nil
+		}
+	}
+}
+
+func (r *Reconciler) replayClicks(ctx context.Context, report *Report) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := r.repo.ReplayClicks(ctx, r.batchSize)
+		report.ClicksReplayed += int64(n)
+		if err != nil {
+			return err
+		}
+		if n < r.batchSize {
+			return nil
+		}
+	}
+}
+
+// onConflict 记录冲突，并清除缓存中可能是落选版本的数据，下次读取时从 MySQL 回填
+func (r *Reconciler) onConflict(ctx context.Context, url *model.ShortURL, kept string) {
+	log.Printf("reconciler: conflict on %s resolved, kept %s copy", url.ShortCode, kept)
+	if err := r.urlRepo.InWorkspace(url.WorkspaceID).DeleteFromCache(ctx, url.ShortCode); err != nil {
+		log.Printf("reconciler: failed to invalidate cache for %s: %v", url.ShortCode, err)
+	}
+}
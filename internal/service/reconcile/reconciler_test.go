@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+)
+
+// fakeReconcileRepository 按短码返回预设的对账结果
+type fakeReconcileRepository struct {
+	urls     []model.ShortURL
+	outcomes map[string]repository.ReconcileOutcome
+	failing  map[string]bool
+	clicks   int
+}
+
+func (f *fakeReconcileRepository) Available() bool                     { return true }
+func (f *fakeReconcileRepository) PingMySQL(ctx context.Context) error { return nil }
+
+func (f *fakeReconcileRepository) PendingShortURLs(ctx context.Context, afterID int64, limit int) ([]model.ShortURL, error) {
+	var result []model.ShortURL
+	for _, u := range f.urls {
+		if u.ID > afterID && len(result) < limit {
+			result = append(result, u)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeReconcileRepository) ReconcileShortURL(ctx context.Context, url *model.ShortURL, host string) (repository.ReconcileOutcome, error) {
+	if f.failing[url.ShortCode] {
+		return 0, errors.New("mysql error")
+	}
+	return f.outcomes[url.ShortCode], nil
+}
+
+func (f *fakeReconcileRepository) ReplayClicks(ctx context.Context, limit int) (int, error) {
+	n := min(limit, f.clicks)
+	f.clicks -= n
+	return n, nil
+}
+
+// fakeURLRepository 只记录被清除缓存的短码
+type fakeURLRepository struct {
+	repository.URLRepository
+	invalidated []string
+}
+
+// InWorkspace 测试数据都在默认工作区
+func (f *fakeURLRepository) InWorkspace(workspaceID int64) repository.URLRepository {
+	return f
+}
+
+func (f *fakeURLRepository) DeleteFromCache(ctx context.Context, shortCode string) error {
+	f.invalidated = append(f.invalidated, shortCode)
+	return nil
+}
+
+// TestReconcilerRunOnce 测试一轮对账的计数、跳过失败条目以及冲突时清除缓存
+func TestReconcilerRunOnce(t *testing.T) {
+	repo := &fakeReconcileRepository{
+		urls: []model.ShortURL{
+			{ID: 1, ShortCode: "new1"},
+			{ID: 2, ShortCode: "same"},
+			{ID: 3, ShortCode: "bad"},
+			{ID: 4, ShortCode: "mine"},
+			{ID: 5, ShortCode: "theirs"},
+		},
+		outcomes: map[string]repository.ReconcileOutcome{
+			"new1":   repository.ReconcileInserted,
+			"same":   repository.ReconcileIdentical,
+			"mine":   repository.ReconcileKeptSQLite,
+			"theirs": repository.ReconcileKeptMySQL,
+		},
+		failing: map[string]bool{"bad": true},
+		clicks:  5,
+	}
+	urlRepo := &fakeURLRepository{}
+	// batchSize 为 2，覆盖多批读取
+	r := NewReconciler(repo, urlRepo, time.Minute, 2)
+
+	report, err := r.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	want := Report{Inserted: 1, Identical: 1, KeptMySQL: 1, KeptSQLite: 1, Failed: 1, ClicksReplayed: 5}
+	if report != want {
+		t.Errorf("RunOnce() = %+v, want %+v", report, want)
+	}
+	if len(urlRepo.invalidated) != 2 {
+		t.Errorf("invalidated = %v, want 2 conflicting codes", urlRepo.invalidated)
+	}
+	if stats := r.Stats(); stats.Runs != 1 || stats.Total != want {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
@@ -0,0 +1,29 @@
+package service
+
+import "strings"
+
+// reservedAliases 不允许作为自定义短码的保留字
+// 包括网关已占用的路由前缀、API 的操作名以及容易引起误解的常见路径
+var reservedAliases = map[string]struct{}{
+	"shortener":   {},
+	"clipboard":   {},
+	"export":      {},
+	"batch":       {},
+	"api":         {},
+	"admin":       {},
+	"login":       {},
+	"logout":      {},
+	"health":      {},
+	"healthz":     {},
+	"metrics":     {},
+	"static":      {},
+	"assets":      {},
+	"favicon.ico": {},
+	"robots.txt":  {},
+}
+
+// isReservedAlias 检查自定义短码是否为保留字（不区分大小写）
+func isReservedAlias(alias string) bool {
+	_, ok := reservedAliases[strings.ToLower(alias)]
+	return ok
+}
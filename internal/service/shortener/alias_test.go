@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	shorturlpb "github.com/username/shorturl/internal/rpc/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestIsReservedAlias 测试保留字不区分大小写，普通短码不受影响
+func TestIsReservedAlias(t *testing.T) {
+	tests := []struct {
+		alias string
+		want  bool
+	}{
+		{alias: "export", want: true},
+		{alias: "batch", want: true},
+		{alias: "Export", want: true},
+		{alias: "BATCH", want: true},
+		{alias: "shortener", want: true},
+		{alias: "favicon.ico", want: true},
+		{alias: "exports", want: false},
+		{alias: "my-batch", want: false},
+		{alias: "abc123", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			if got := isReservedAlias(tt.alias); got != tt.want {
+				t.Errorf("isReservedAlias(%q) = %v, want %v", tt.alias, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBuildShortURLAlias 测试自定义短码的格式和保留字校验
+func TestBuildShortURLAlias(t *testing.T) {
+	tests := []struct {
+		name     string
+		alias    string
+		wantCode codes.Code
+	}{
+		{name: "字母数字", alias: "promo2024", wantCode: codes.OK},
+		{name: "连字符和下划线", alias: "my-link_1", wantCode: codes.OK},
+		{name: "最短 4 位", alias: "abcd", wantCode: codes.OK},
+		{name: "最长 20 位", alias: strings.Repeat("a", 20), wantCode: codes.OK},
+		{name: "太短", alias: "abc", wantCode: codes.InvalidArgument},
+		{name: "太长", alias: strings.Repeat("a", 21), wantCode: codes.InvalidArgument},
+		{name: "非法字符", alias: "a/b.c", wantCode: codes.InvalidArgument},
+		{name: "中文", alias: "短链接别名", wantCode: codes.InvalidArgument},
+		{name: "保留字", alias: "admin", wantCode: codes.InvalidArgument},
+		{name: "大写的保留字", alias: "Export", wantCode: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, err := buildShortURL("https://example.com", CreateOptions{CustomAlias: tt.alias}, time.Now(), nil)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("buildShortURL(%q) error = %v, want %v", tt.alias, err, tt.wantCode)
+			}
+			if err == nil && url.ShortCode != tt.alias {
+				t.Errorf("ShortCode = %q, want %q", url.ShortCode, tt.alias)
+			}
+		})
+	}
+}
+
+// TestCreateShortLinkDuplicateAlias 测试自定义短码已被占用时返回 AlreadyExists，不覆盖原来的目标
+func TestCreateShortLinkDuplicateAlias(t *testing.T) {
+	useTestSQLite(t)
+	ctx := context.Background()
+	s := &Service{}
+
+	if _, err := s.CreateShortLink(ctx, "https://example.com/first", CreateOptions{CustomAlias: "taken"}); err != nil {
+		t.Fatalf("first CreateShortLink() error = %v", err)
+	}
+	if _, err := s.CreateShortLink(ctx, "https://example.com/second", CreateOptions{CustomAlias: "taken"}); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("second CreateShortLink() error = %v, want AlreadyExists", err)
+	}
+	resp, err := s.GetLongURL(ctx, &shorturlpb.GetLongURLRequest{ShortKey: "taken"})
+	if err != nil || resp.GetLongUrl() != "https://example.com/first" {
+		t.Errorf("GetLongURL() = %v, %v, want the first target", resp, err)
+	}
+}
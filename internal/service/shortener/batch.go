@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/username/shorturl/internal/codegen"
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxBatchSize 单次批量创建的最大条数，客户端流式调用按此大小分批处理
+const MaxBatchSize = 1000
+
+// BatchItem 批量创建中的一条
+type BatchItem struct {
+	LongURL string
+	Opts    CreateOptions
+}
+
+// BatchResult 批量创建中一条的结果，Err 为 gRPC status 错误
+type BatchResult struct {
+	URL *model.ShortURL
+	Err error
+}
+
+// BatchCreateShortLinks 批量创建短链接，每条独立返回成功或失败
+// 整批共用一个 Repository，数据库与缓存按批写入；自动生成的短码冲突时只对冲突的条目重新生成
+// 仅在整批无法处理时（条数超限、数据源不可用）返回 error
+func (s *Service) BatchCreateShortLinks(ctx context.Context, items []BatchItem) ([]BatchResult, error) {
+	if len(items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "items 不能为空")
+	}
+	if len(items) > MaxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "单次最多创建 %d 条", MaxBatchSize)
+	}
+
+	type pendingItem struct {
+		index     int
+		generated bool
+		attempt   int
+	}
+
+	sc, err := resolveScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remaining, err := sc.remainingLinks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 逐条校验参数，超出工作区数量配额的条目直接失败
+	results := make([]BatchResult, len(items))
+	var pending []pendingItem
+	needGenerator := false
+	createdAt := time.Now()
+	for i, item := range items {
+		url, err := buildShortURL(item.LongURL, item.Opts, createdAt, sc.workspace)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		if remaining == 0 {
+			results[i].Err = linkQuotaError(sc.workspace)
+			continue
+		}
+		if remaining > 0 {
+			remaining--
+		}
+		url.OwnerID = sc.userID
+		results[i].URL = url
+		pending = append(pending, pendingItem{index: i, generated: url.ShortCode == ""})
+		needGenerator = needGenerator || url.ShortCode == ""
+	}
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	var gen codegen.CodeGenerator
+	if needGenerator {
+		var err error
+		if gen, err = getCodeGenerator(); err != nil {
+			return nil, status.Errorf(codes.Internal, "短码生成器初始化失败: %v", err)
+		}
+	}
+	dataSources, err := repository.GetDataSources()
+	if err != nil {
+		return nil, err
+	}
+	urlRepository := repository.NewURLRepository(dataSources).InWorkspace(sc.workspaceID())
+
+	// 2. 按轮次写入，每轮只重试自动生成且冲突的条目
+	maxAttempts := maxGenerateAttempts()
+	for len(pending) > 0 {
+		var round []pendingItem
+		var urls []*model.ShortURL
+		for _, p := range pending {
+			url := results[p.index].URL
+			if p.generated {
+				code, err := gen.Generate(ctx, url.LongURL, p.attempt)
+				if err != nil {
+					results[p.index] = BatchResult{Err: status.Errorf(codes.Internal, "生成短码失败: %v", err)}
+					continue
+				}
+				url.ShortCode = code
+			}
+			round = append(round, p)
+			urls = append(urls, url)
+		}
+
+		errs := urlRepository.CreateBatch(ctx, urls)
+		pending = nil
+		for n, p := range round {
+			err := errs[n]
+			switch {
+			case err == nil:
+			case errors.Is(err, repository.ErrAlreadyExists) && p.generated && p.attempt+1 < maxAttempts:
+				p.attempt++
+				pending = append(pending, p)
+			case errors.Is(err, repository.ErrAlreadyExists) && p.generated:
+				results[p.index] = BatchResult{Err: status.Errorf(codes.ResourceExhausted, "连续 %d 次生成的短码均已被占用", maxAttempts)}
+			case errors.Is(err, repository.ErrAlreadyExists):
+				results[p.index] = BatchResult{Err: status.Errorf(codes.AlreadyExists, "短码 %s 已被占用", urls[n].ShortCode)}
+			default:
+				results[p.index] = BatchResult{Err: status.Errorf(codes.Internal, "保存短链接失败: %v", err)}
+			}
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/username/shorturl/internal/codegen"
+	"github.com/username/shorturl/internal/config"
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	generatorOnce sync.Once
+	generator     codegen.CodeGenerator
+	generatorErr  error
+)
+
+// getCodeGenerator 按配置创建短码生成器（进程内单例，保证计数器和长度状态共享）
+func getCodeGenerator() (codegen.CodeGenerator, error) {
+	generatorOnce.Do(func() {
+		dataSources, err := repository.GetDataSources()
+		if err != nil {
+			generatorErr = err
+			return
+		}
+		cfg := config.GetConfig().CodeGenerator
+		generator, generatorErr = codegen.New(codegen.Options{
+			Strategy:  cfg.Strategy,
+			Length:    cfg.Length,
+			MaxLength: cfg.MaxLength,
+			NodeID:    cfg.NodeID,
+			Salt:      cfg.Salt,
+		}, repository.NewSequenceRepository(dataSources))
+	})
+	return generator, generatorErr
+}
+
+// createWithGeneratedCode 生成短码并通过 create 写入，短码冲突时重新生成，直到成功或达到最大尝试次数
+func createWithGeneratedCode(ctx context.Context, create createFunc, url *model.ShortURL) error {
+	gen, err := getCodeGenerator()
+	if err != nil {
+		return status.Errorf(codes.Internal, "短码生成器初始化失败: %v", err)
+	}
+
+	maxAttempts := maxGenerateAttempts()
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		code, err := gen.Generate(ctx, url.LongURL, attempt)
+		if err != nil {
+			return status.Errorf(codes.Internal, "生成短码失败: %v", err)
+		}
+		url.ShortCode = code
+
+		err = create(ctx, url)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, repository.ErrAlreadyExists) {
+			return status.Errorf(codes.Internal, "保存短链接失败: %v", err)
+		}
+	}
+	return status.Errorf(codes.ResourceExhausted, "连续 %d 次生成的短码均已被占用", maxAttempts)
+}
+
+// maxGenerateAttempts 自动生成短码的最大尝试次数
+func maxGenerateAttempts() int {
+	maxAttempts := config.GetConfig().CodeGenerator.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	return maxAttempts
+}
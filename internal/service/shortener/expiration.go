@@ -0,0 +1,53 @@
+package service
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// resolveExpiresAt 根据调用方参数和服务端限制计算过期时间
+// 返回 nil 表示永不过期
+func resolveExpiresAt(now time.Time, opts CreateOptions, defaultTTL, maxTTL time.Duration) (*time.Time, error) {
+	if opts.ExpiresIn < 0 {
+		return nil, status.Error(codes.InvalidArgument, "expires_in 不能为负数")
+	}
+	if opts.ExpiresIn > 0 && opts.ExpiresAt != nil {
+		return nil, status.Error(codes.InvalidArgument, "expires_in 与 expires_at 只能指定一个")
+	}
+
+	var ttl time.Duration
+	switch {
+	case opts.NeverExpires:
+		if opts.ExpiresIn > 0 || opts.ExpiresAt != nil {
+			return nil, status.Error(codes.InvalidArgument, "never_expires 不能与 expires_in/expires_at 同时指定")
+		}
+		if maxTTL > 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "服务端限制最长有效期为 %s，不允许永不过期", maxTTL)
+		}
+		return nil, nil
+	case opts.ExpiresAt != nil:
+		if !opts.ExpiresAt.After(now) {
+			return nil, status.Error(codes.InvalidArgument, "expires_at 必须晚于当前时间")
+		}
+		ttl = opts.ExpiresAt.Sub(now)
+	case opts.ExpiresIn > 0:
+		ttl = opts.ExpiresIn
+	default:
+		ttl = defaultTTL
+		if ttl <= 0 && maxTTL > 0 {
+			// 默认永不过期但服务端有上限时，使用最长有效期
+			ttl = maxTTL
+		}
+	}
+
+	if ttl <= 0 {
+		return nil, nil
+	}
+	if maxTTL > 0 && ttl > maxTTL {
+		return nil, status.Errorf(codes.InvalidArgument, "有效期不能超过 %s", maxTTL)
+	}
+	expiresAt := now.Add(ttl)
+	return &expiresAt, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+// TestResolveExpiresAt 测试过期时间计算
+func TestResolveExpiresAt(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	future := now.Add(2 * time.Hour)
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		opts       CreateOptions
+		defaultTTL time.Duration
+		maxTTL     time.Duration
+		want       time.Duration // 相对 now 的有效期，0 表示永不过期
+		wantErr    bool
+	}{
+		{
+			name: "未指定且无默认值，永不过期",
+			want: 0,
+		},
+		{
+			name:       "未指定时使用默认有效期",
+			defaultTTL: time.Hour,
+			want:       time.Hour,
+		},
+		{
+			name:   "默认永不过期但有上限时使用上限",
+			maxTTL: 24 * time.Hour,
+			want:   24 * time.Hour,
+		},
+		{
+			name: "指定 expires_in",
+			opts: CreateOptions{ExpiresIn: 30 * time.Minute},
+			want: 30 * time.Minute,
+		},
+		{
+			name: "指定 expires_at",
+			opts: CreateOptions{ExpiresAt: &future},
+			want: 2 * time.Hour,
+		},
+		{
+			name:    "expires_at 早于当前时间",
+			opts:    CreateOptions{ExpiresAt: &past},
+			wantErr: true,
+		},
+		{
+			name:    "同时指定 expires_in 和 expires_at",
+			opts:    CreateOptions{ExpiresIn: time.Minute, ExpiresAt: &future},
+			wantErr: true,
+		},
+		{
+			name:    "超过最长有效期",
+			opts:    CreateOptions{ExpiresIn: 48 * time.Hour},
+			maxTTL:  24 * time.Hour,
+			wantErr: true,
+		},
+		{
+			name:       "永不过期覆盖默认有效期",
+			opts:       CreateOptions{NeverExpires: true},
+			defaultTTL: time.Hour,
+			want:       0,
+		},
+		{
+			name:    "有最长有效期时不允许永不过期",
+			opts:    CreateOptions{NeverExpires: true},
+			maxTTL:  24 * time.Hour,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveExpiresAt(now, tt.opts, tt.defaultTTL, tt.maxTTL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveExpiresAt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.want == 0 {
+				if got != nil {
+					t.Errorf("resolveExpiresAt() = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || !got.Equal(now.Add(tt.want)) {
+				t.Errorf("resolveExpiresAt() = %v, want %v", got, now.Add(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"log"
+
+	"github.com/username/shorturl/internal/repository"
+	shorturlpb "github.com/username/shorturl/internal/rpc/proto"
+	domain "github.com/username/shorturl/internal/service/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultStreamBatchSize = 500
+
+// StreamShortLinks 按游标分批读取数据库，逐条推送给调用方
+// stream.Send 在对端消费变慢时会阻塞（HTTP/2 流控），读完当前批次之前不会再查询数据库，
+// 因此内存中最多只保留一批数据；调用方断开或 ctx 取消时立即结束
+func (s *Service) StreamShortLinks(req *shorturlpb.StreamShortLinksRequest, stream shorturlpb.ShortenerService_StreamShortLinksServer) error {
+	ctx := stream.Context()
+
+	batchSize := req.GetBatchSize()
+	if batchSize == 0 {
+		batchSize = defaultStreamBatchSize
+	}
+	// 复用列表接口的参数校验
+	q, err := buildListQuery(&shorturlpb.GetAllShortLinkRequest{
+		PageSize:      batchSize,
+		CreatedAfter:  req.GetCreatedAfter(),
+		CreatedBefore: req.GetCreatedBefore(),
+		Status:        req.GetStatus(),
+		HostContains:  req.GetHostContains(),
+		Sort:          req.GetSort(),
+		AllUsers:      req.GetAllUsers(),
+	})
+	if err != nil {
+		return err
+	}
+	sc, err := resolveScope(ctx)
+	if err != nil {
+		return err
+	}
+	if q.OwnerID, err = sc.listScope(req.GetAllUsers()); err != nil {
+		return err
+	}
+
+	dataSources, err := repository.GetDataSources()
+	if err != nil {
+		return err
+	}
+	urlRepository := repository.NewURLRepository(dataSources).InWorkspace(sc.workspaceID())
+	baseURL := domain.GetRegistry().BaseURL(ctx, sc.workspaceID())
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+
+		batch, hasMore, err := urlRepository.List(ctx, q)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			log.Println(err)
+			return status.Errorf(codes.Internal, "查询短链接失败: %v", err)
+		}
+		for _, v := range batch {
+			if err := stream.Send(toShortLinkPB(v, baseURL)); err != nil {
+				return err
+			}
+		}
+		if !hasMore || len(batch) == 0 {
+			return nil
+		}
+
+		last := batch[len(batch)-1]
+		q.AfterID, q.AfterCode = last.ID, last.ShortCode
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/username/shorturl/internal/db"
+	"github.com/username/shorturl/internal/db/migrate"
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+	shorturlpb "github.com/username/shorturl/internal/rpc/proto"
+	"google.golang.org/grpc"
+)
+
+// recordingStream 记录 StreamShortLinks 推送的每一条数据
+type recordingStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*shorturlpb.ShortLink
+}
+
+func (s *recordingStream) Context() context.Context { return s.ctx }
+
+func (s *recordingStream) Send(link *shorturlpb.ShortLink) error {
+	s.sent = append(s.sent, link)
+	return nil
+}
+
+// TestStreamShortLinks 测试跨越多个批次导出时每条短链接恰好推送一次
+func TestStreamShortLinks(t *testing.T) {
+	ctx := context.Background()
+	database, err := db.NewSQLiteDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteDB() error = %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if err := migrate.UpAll(ctx, database); err != nil {
+		t.Fatalf("UpAll() error = %v", err)
+	}
+	previous := repository.GloablDataSources
+	repository.GloablDataSources = &repository.DataSources{SQLiteDB: database}
+	t.Cleanup(func() { repository.GloablDataSources = previous })
+
+	const total = 7
+	urls := make([]*model.ShortURL, total)
+	for i := range urls {
+		urls[i] = &model.ShortURL{
+			ShortCode:    fmt.Sprintf("code%d", i),
+			LongURL:      fmt.Sprintf("https://example.com/%d", i),
+			RedirectCode: 302,
+			CreatedAt:    time.Unix(1700000000+int64(i), 0),
+		}
+	}
+	for i, err := range repository.NewURLRepository(repository.GloablDataSources).InsertBatch(ctx, urls) {
+		if err != nil {
+			t.Fatalf("InsertBatch() #%d error = %v", i, err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		batchSize int32
+		sort      shorturlpb.LinkSort
+	}{
+		{name: "批次不能整除总数", batchSize: 3},
+		{name: "批次等于总数", batchSize: total},
+		{name: "每批一条", batchSize: 1},
+		{name: "按短码排序", batchSize: 2, sort: shorturlpb.LinkSort_LINK_SORT_SHORT_CODE_ASC},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &recordingStream{ctx: ctx}
+			err := (&Service{}).StreamShortLinks(&shorturlpb.StreamShortLinksRequest{BatchSize: tt.batchSize, Sort: tt.sort}, stream)
+			if err != nil {
+				t.Fatalf("StreamShortLinks() error = %v", err)
+			}
+			if len(stream.sent) != total {
+				t.Fatalf("StreamShortLinks() sent %d links, want %d", len(stream.sent), total)
+			}
+			seen := make(map[string]bool)
+			for _, link := range stream.sent {
+				code := link.GetShortLink()
+				if seen[code] {
+					t.Errorf("%s sent more than once", code)
+				}
+				seen[code] = true
+				if want := "http://localhost:8080/" + code; link.GetShortUrl() != want {
+					t.Errorf("%s ShortUrl = %s, want %s", code, link.GetShortUrl(), want)
+				}
+			}
+		})
+	}
+}
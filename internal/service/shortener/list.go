@@ -0,0 +1,138 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/username/shorturl/internal/db/model"
+	shorturlpb "github.com/username/shorturl/internal/rpc/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// pageToken 分页游标，序列化后以 base64url 形式返回给调用方
+type pageToken struct {
+	Sort string `json:"s"`
+	ID   int64  `json:"i,omitempty"`
+	Code string `json:"c,omitempty"`
+	// Filter 过滤条件的摘要，防止同一游标被用于不同的查询
+	Filter uint64 `json:"f"`
+}
+
+// buildListQuery 将请求转换为仓储层查询条件
+func buildListQuery(req *shorturlpb.GetAllShortLinkRequest) (model.ListQuery, error) {
+	q := model.ListQuery{
+		PageSize:     int(req.GetPageSize()),
+		HostContains: req.GetHostContains(),
+	}
+	if q.PageSize < 0 {
+		return q, status.Error(codes.InvalidArgument, "page_size 不能为负数")
+	}
+	if q.PageSize == 0 {
+		q.PageSize = defaultPageSize
+	}
+	if q.PageSize > maxPageSize {
+		q.PageSize = maxPageSize
+	}
+
+	switch req.GetSort() {
+	case shorturlpb.LinkSort_LINK_SORT_CREATED_DESC:
+		q.Sort = model.SortCreatedDesc
+	case shorturlpb.LinkSort_LINK_SORT_CREATED_ASC:
+		q.Sort = model.SortCreatedAsc
+	case shorturlpb.LinkSort_LINK_SORT_SHORT_CODE_ASC:
+		q.Sort = model.SortShortCodeAsc
+	case shorturlpb.LinkSort_LINK_SORT_SHORT_CODE_DESC:
+		q.Sort = model.SortShortCodeDesc
+	default:
+		return q, status.Errorf(codes.InvalidArgument, "不支持的排序方式: %v", req.GetSort())
+	}
+
+	switch req.GetStatus() {
+	case shorturlpb.LinkStatus_LINK_STATUS_ALL:
+		q.Status = model.StatusAll
+	case shorturlpb.LinkStatus_LINK_STATUS_ACTIVE:
+		q.Status = model.StatusActive
+	case shorturlpb.LinkStatus_LINK_STATUS_EXPIRED:
+		q.Status = model.StatusExpired
+	default:
+		return q, status.Errorf(codes.InvalidArgument, "不支持的状态过滤: %v", req.GetStatus())
+	}
+
+	if req.GetCreatedAfter() > 0 {
+		t := time.Unix(req.GetCreatedAfter(), 0)
+		q.CreatedAfter = &t
+	}
+	if req.GetCreatedBefore() > 0 {
+		t := time.Unix(req.GetCreatedBefore(), 0)
+		q.CreatedBefore = &t
+	}
+	if q.CreatedAfter != nil && q.CreatedBefore != nil && !q.CreatedAfter.Before(*q.CreatedBefore) {
+		return q, status.Error(codes.InvalidArgument, "created_after 必须早于 created_before")
+	}
+
+	if req.GetPageToken() != "" {
+		token, err := decodePageToken(req.GetPageToken())
+		if err != nil || token.Sort != q.Sort || token.Filter != filterDigest(req) {
+			return q, status.Error(codes.InvalidArgument, "无效的 page_token")
+		}
+		q.AfterID, q.AfterCode = token.ID, token.Code
+	}
+	return q, nil
+}
+
+// nextPageToken 根据本页最后一条数据生成下一页游标
+func nextPageToken(req *shorturlpb.GetAllShortLinkRequest, q model.ListQuery, last model.ShortURL) string {
+	token := pageToken{Sort: q.Sort, Filter: filterDigest(req)}
+	switch q.Sort {
+	case model.SortShortCodeAsc, model.SortShortCodeDesc:
+		token.Code = last.ShortCode
+	default:
+		token.ID = last.ID
+	}
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(s string) (*pageToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var token pageToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// filterDigest 计算过滤条件的摘要（不包含 page_size，允许翻页时调整每页条数）
+func filterDigest(req *shorturlpb.GetAllShortLinkRequest) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d|%d|%d|%s|%t", req.GetCreatedAfter(), req.GetCreatedBefore(), req.GetStatus(), req.GetHostContains(), req.GetAllUsers())
+	return h.Sum64()
+}
+
+// toShortLinkPB 将模型转换为列表返回的结构，baseURL 为短链接所在工作区的地址前缀（见 domain.Registry.BaseURL）
+func toShortLinkPB(u model.ShortURL, baseURL string) *shorturlpb.ShortLink {
+	link := &shorturlpb.ShortLink{
+		ShortLink:    u.ShortCode,
+		ShortUrl:     baseURL + "/" + u.ShortCode,
+		LongLink:     u.LongURL,
+		RedirectCode: int32(u.RedirectCode),
+		CreatedAt:    u.CreatedAt.Unix(),
+		OwnerId:      u.OwnerID,
+	}
+	if u.ExpiresAt != nil {
+		link.ExpiresAt = u.ExpiresAt.Unix()
+	}
+	return link
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/username/shorturl/internal/db/model"
+	shorturlpb "github.com/username/shorturl/internal/rpc/proto"
+)
+
+// TestBuildListQueryPageToken 测试分页游标的生成与校验
+func TestBuildListQueryPageToken(t *testing.T) {
+	first := &shorturlpb.GetAllShortLinkRequest{
+		Sort:         shorturlpb.LinkSort_LINK_SORT_SHORT_CODE_ASC,
+		HostContains: "example",
+	}
+	q, err := buildListQuery(first)
+	if err != nil {
+		t.Fatalf("buildListQuery() error = %v", err)
+	}
+	if q.PageSize != defaultPageSize {
+		t.Errorf("PageSize = %d, want %d", q.PageSize, defaultPageSize)
+	}
+	token := nextPageToken(first, q, model.ShortURL{ID: 7, ShortCode: "abc123"})
+
+	tests := []struct {
+		name     string
+		req      *shorturlpb.GetAllShortLinkRequest
+		wantCode string
+		wantErr  bool
+	}{
+		{
+			name: "相同条件翻页",
+			req: &shorturlpb.GetAllShortLinkRequest{
+				Sort: shorturlpb.LinkSort_LINK_SORT_SHORT_CODE_ASC, HostContains: "example", PageToken: token, PageSize: 10,
+			},
+			wantCode: "abc123",
+		},
+		{
+			name: "过滤条件变化",
+			req: &shorturlpb.GetAllShortLinkRequest{
+				Sort: shorturlpb.LinkSort_LINK_SORT_SHORT_CODE_ASC, HostContains: "other", PageToken: token,
+			},
+			wantErr: true,
+		},
+		{
+			name: "排序方式变化",
+			req: &shorturlpb.GetAllShortLinkRequest{
+				Sort: shorturlpb.LinkSort_LINK_SORT_CREATED_DESC, HostContains: "example", PageToken: token,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "无法解析的游标",
+			req:     &shorturlpb.GetAllShortLinkRequest{PageToken: "!!"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := buildListQuery(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildListQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && q.AfterCode != tt.wantCode {
+				t.Errorf("AfterCode = %q, want %q", q.AfterCode, tt.wantCode)
+			}
+		})
+	}
+}
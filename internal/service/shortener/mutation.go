@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+	shorturlpb "github.com/username/shorturl/internal/rpc/proto"
+	"github.com/username/shorturl/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) UpdateShortLink(ctx context.Context, req *shorturlpb.UpdateShortLinkRequest) (*shorturlpb.UpdateShortLinkResponse, error) {
+	// 1. 校验参数，组装需要更新的字段
+	if req.GetShortKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_key 不能为空")
+	}
+	sc, err := resolveScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var update model.ShortURLUpdate
+	if req.LongUrl != nil {
+		if !utils.ValidateURL(req.GetLongUrl()) {
+			return nil, status.Error(codes.InvalidArgument, "不是合法的 LonURL")
+		}
+		longURL := req.GetLongUrl()
+		update.LongURL = &longURL
+	}
+	if req.RedirectCode != nil {
+		redirectCode := int(req.GetRedirectCode())
+		if !model.IsValidRedirectCode(redirectCode) {
+			return nil, status.Errorf(codes.InvalidArgument, "不支持的重定向状态码: %d", redirectCode)
+		}
+		update.RedirectCode = &redirectCode
+	}
+	if req.ExpiresIn != nil || req.ExpiresAt != nil || req.NeverExpires != nil {
+		opts := CreateOptions{
+			ExpiresIn:    time.Duration(req.GetExpiresIn()) * time.Second,
+			NeverExpires: req.GetNeverExpires(),
+		}
+		if req.ExpiresAt != nil {
+			expiresAt := time.Unix(req.GetExpiresAt(), 0)
+			opts.ExpiresAt = &expiresAt
+		}
+		if opts.ExpiresIn == 0 && opts.ExpiresAt == nil && !opts.NeverExpires {
+			return nil, status.Error(codes.InvalidArgument, "未指定有效的过期时间")
+		}
+		// 更新时只做显式修改，不使用默认有效期
+		_, _, maxTTL := linkDefaults(sc.workspace)
+		expiresAt, err := resolveExpiresAt(time.Now(), opts, 0, maxTTL)
+		if err != nil {
+			return nil, err
+		}
+		if expiresAt == nil {
+			update.ClearExpiresAt = true
+		} else {
+			update.ExpiresAt = expiresAt
+		}
+	}
+	if update.IsEmpty() {
+		return nil, status.Error(codes.InvalidArgument, "没有需要更新的字段")
+	}
+
+	// 2. 更新所有数据源，非管理员只能更新自己的短链接
+	dataSources, err := repository.GetDataSources()
+	if err != nil {
+		return nil, err
+	}
+	urlRepository := repository.NewURLRepository(dataSources).InWorkspace(sc.workspaceID())
+	shortURLModel, err := urlRepository.Update(ctx, req.GetShortKey(), sc.mutationScope(), update)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "短码 %s 不存在", req.GetShortKey())
+		}
+		if errors.Is(err, repository.ErrNotOwner) {
+			return nil, status.Errorf(codes.PermissionDenied, "短码 %s 属于其他用户", req.GetShortKey())
+		}
+		return nil, status.Errorf(codes.Internal, "更新短链接失败: %v", err)
+	}
+
+	// 3. 返回更新后的数据
+	resp := &shorturlpb.UpdateShortLinkResponse{
+		ShortKey:     shortURLModel.ShortCode,
+		ShortUrl:     s.ShortURL(ctx, shortURLModel),
+		LongUrl:      shortURLModel.LongURL,
+		RedirectCode: int32(shortURLModel.RedirectCode),
+	}
+	if shortURLModel.ExpiresAt != nil {
+		resp.ExpiresAt = shortURLModel.ExpiresAt.Unix()
+	}
+	return resp, nil
+}
+
+func (s *Service) DeleteShortLink(ctx context.Context, req *shorturlpb.DeleteShortLinkRequest) (*shorturlpb.DeleteShortLinkResponse, error) {
+	if req.GetShortKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_key 不能为空")
+	}
+
+	sc, err := resolveScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dataSources, err := repository.GetDataSources()
+	if err != nil {
+		return nil, err
+	}
+	urlRepository := repository.NewURLRepository(dataSources).InWorkspace(sc.workspaceID())
+	if err := urlRepository.Delete(ctx, req.GetShortKey(), sc.mutationScope()); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "短码 %s 不存在", req.GetShortKey())
+		}
+		if errors.Is(err, repository.ErrNotOwner) {
+			return nil, status.Errorf(codes.PermissionDenied, "短码 %s 属于其他用户", req.GetShortKey())
+		}
+		return nil, status.Errorf(codes.Internal, "删除短链接失败: %v", err)
+	}
+
+	return &shorturlpb.DeleteShortLinkResponse{}, nil
+}
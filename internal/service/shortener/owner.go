@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+
+	"github.com/username/shorturl/internal/auth"
+	"github.com/username/shorturl/internal/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// owner 调用方对短链接的所有权
+type owner struct {
+	// userID 调用方对应的 users.id，未启用认证时为 0
+	userID int64
+	// admin 管理员可以修改所有用户的短链接，列表查询需要显式指定 all_users
+	admin bool
+}
+
+// resolveOwner 根据 ctx 中的认证身份取得对应的用户，首次访问时创建
+// 未启用认证（ctx 中没有身份）时返回零值，不限制所有权
+func resolveOwner(ctx context.Context) (owner, error) {
+	id, ok := auth.FromContext(ctx)
+	if !ok {
+		return owner{}, nil
+	}
+	dataSources, err := repository.GetDataSources()
+	if err != nil {
+		return owner{}, err
+	}
+	user, err := repository.NewUserRepository(dataSources).GetOrCreate(ctx, id.Subject)
+	if err != nil {
+		return owner{}, status.Errorf(codes.Unavailable, "获取用户失败: %v", err)
+	}
+	return owner{userID: user.ID, admin: id.HasRole(auth.RoleAdmin)}, nil
+}
+
+// mutationScope 更新、删除时限定的所有者，0 表示不限制
+func (o owner) mutationScope() int64 {
+	if o.admin {
+		return 0
+	}
+	return o.userID
+}
+
+// listScope 列表查询限定的所有者，0 表示不限制；非管理员不能查询所有用户的短链接
+func (o owner) listScope(allUsers bool) (int64, error) {
+	if !allUsers {
+		return o.userID, nil
+	}
+	if o.userID != 0 && !o.admin {
+		return 0, status.Error(codes.PermissionDenied, "只有管理员可以查询所有用户的短链接")
+	}
+	return 0, nil
+}
@@ -2,12 +2,16 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log"
 	"time"
 
-	"github.com/username/shorturl/internal/model"
+	"github.com/username/shorturl/internal/db/model"
 	"github.com/username/shorturl/internal/repository"
 	shorturlpb "github.com/username/shorturl/internal/rpc/proto"
+	analytics "github.com/username/shorturl/internal/service/analytics"
+	domain "github.com/username/shorturl/internal/service/domain"
+	writebehind "github.com/username/shorturl/internal/service/writebehind"
 	"github.com/username/shorturl/pkg/utils"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -15,94 +19,213 @@ import (
 
 type Service struct {
 	repo repository.URLRepository
+}
 
-	asyncQueue chan *model.ShortURL
+// createFunc 写入一条新短链接，短码已被占用时返回 repository.ErrAlreadyExists
+type createFunc func(ctx context.Context, url *model.ShortURL) error
+
+// CreateOptions 创建短链接的可选参数
+type CreateOptions struct {
+	// ExpiresIn 有效期，与 ExpiresAt 二选一；都为空时使用服务端默认有效期
+	ExpiresIn time.Duration
+	// ExpiresAt 绝对过期时间
+	ExpiresAt *time.Time
+	// NeverExpires 永不过期
+	NeverExpires bool
+	// RedirectCode 重定向状态码，0 表示使用默认值
+	RedirectCode int
+	// CustomAlias 自定义短码，为空时自动生成
+	CustomAlias string
 }
 
-func (s *Service) CreateShortLink(ctx context.Context, longURL string, expiresIn *time.Duration) (*model.ShortURL, error) {
-	// 1. 验证URL
-	isValide := utils.ValidateURL(longURL)
-	if !isValide {
-		return nil, status.Error(codes.InvalidArgument, "不是合法的 LonURL")
+func (s *Service) CreateShortLink(ctx context.Context, longURL string, opts CreateOptions) (*model.ShortURL, error) {
+	sc, err := resolveScope(ctx)
+	if err != nil {
+		return nil, err
 	}
-
-	// 2. 生成短码
-	shortCode, err := utils.GenerateShortCode(6)
+	// 1-3. 校验参数并创建模型，默认值使用工作区的配置
+	shortURLModel, err := buildShortURL(longURL, opts, time.Now(), sc.workspace)
 	if err != nil {
 		return nil, err
 	}
-	// 3. 创建模型
-	createdAt := time.Now()
-	var expiresAt time.Time
-	if expiresIn != nil && *expiresIn != 0 {
-		expiresAt = createdAt.Add(*expiresIn)
+	shortCode := shortURLModel.ShortCode
+	shortURLModel.OwnerID = sc.userID
+	if remaining, err := sc.remainingLinks(ctx); err != nil {
+		return nil, err
+	} else if remaining == 0 {
+		return nil, linkQuotaError(sc.workspace)
 	}
 
-	shortURLModel := &model.ShortURL{
-		ShortCode: shortCode,
-		LongURL:   longURL,
-		CreatedAt: createdAt,
-		ExpiresAt: &expiresAt,
-	}
-	// 4. 写入缓存（同步，必须成功）
-	// 5. 异步写入数据库
+	// 4. 写入数据库和缓存（仅插入，不覆盖已有短码）
 	dataSources, err := repository.GetDataSources()
 	if err != nil {
 		return nil, err
 	}
-	urlRepository := repository.NewURLRepository(dataSources)
-	urlRepository.Save(ctx, shortURLModel)
-
-	log.Println(shortURLModel)
+	urlRepository := repository.NewURLRepository(dataSources).InWorkspace(sc.workspaceID())
+	create := createFunc(urlRepository.Create)
+	if writer := writebehind.GetWriter(); writer != nil {
+		// 写后模式：同步写入缓存，数据库由后台批量写入
+		create = func(ctx context.Context, url *model.ShortURL) error {
+			if err := urlRepository.CreateCached(ctx, url); err != nil {
+				return err
+			}
+			return writer.Submit(ctx, url)
+		}
+	}
+	if shortCode == "" {
+		// 自动生成的短码冲突时重新生成
+		if err := createWithGeneratedCode(ctx, create, shortURLModel); err != nil {
+			return nil, err
+		}
+	} else if err := create(ctx, shortURLModel); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "短码 %s 已被占用", shortCode)
+		}
+		return nil, status.Errorf(codes.Internal, "保存短链接失败: %v", err)
+	}
 
 	// 6. 返回结果
 	return shortURLModel, nil
 }
 
+// ShortURL 返回短链接的完整地址，使用所在工作区已验证的主域名
+func (s *Service) ShortURL(ctx context.Context, url *model.ShortURL) string {
+	return domain.GetRegistry().ShortURL(ctx, url.WorkspaceID, url.ShortCode)
+}
+
+// buildShortURL 校验创建参数并构造模型，未指定自定义短码时 ShortCode 为空，在写入阶段生成
+// ws 为短链接所在的工作区，默认工作区为 nil
+func buildShortURL(longURL string, opts CreateOptions, createdAt time.Time, ws *model.Workspace) (*model.ShortURL, error) {
+	// 1. 验证URL
+	isValide := utils.ValidateURL(longURL)
+	if !isValide {
+		return nil, status.Error(codes.InvalidArgument, "不是合法的 LonURL")
+	}
+	defaultRedirectCode, defaultTTL, maxTTL := linkDefaults(ws)
+	redirectCode := opts.RedirectCode
+	if redirectCode == 0 {
+		redirectCode = defaultRedirectCode
+	}
+	if !model.IsValidRedirectCode(redirectCode) {
+		return nil, status.Errorf(codes.InvalidArgument, "不支持的重定向状态码: %d", redirectCode)
+	}
+
+	// 2. 校验自定义短码
+	shortCode := opts.CustomAlias
+	if shortCode != "" {
+		if !utils.IsValidShortCode(shortCode) {
+			return nil, status.Error(codes.InvalidArgument, "自定义短码只能包含字母、数字、- 和 _，长度 4-20")
+		}
+		if isReservedAlias(shortCode) {
+			return nil, status.Errorf(codes.InvalidArgument, "自定义短码 %s 为保留字", shortCode)
+		}
+	}
+	// 3. 创建模型
+	expiresAt, err := resolveExpiresAt(createdAt, opts, defaultTTL, maxTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ShortURL{
+		ShortCode:    shortCode,
+		LongURL:      longURL,
+		RedirectCode: redirectCode,
+		CreatedAt:    createdAt,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
 func (s *Service) GetLongURL(ctx context.Context, req *shorturlpb.GetLongURLRequest) (*shorturlpb.GetLongURLResponse, error) {
 	shortKey := req.ShortKey
 
-	// 4. 写入缓存（同步，必须成功）
-	// 5. 异步写入数据库
+	// 跳转不需要认证，按域名或 slug 选择工作区，不校验成员；未验证的域名不提供跳转
+	ws, err := resolveRedirectWorkspace(ctx, req.GetHost())
+	if errors.Is(err, domain.ErrNotVerified) {
+		return &shorturlpb.GetLongURLResponse{IsFound: false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTenantLimit(ctx, ws); err != nil {
+		return nil, err
+	}
 	dataSources, err := repository.GetDataSources()
 	if err != nil {
 		return nil, err
 	}
-	urlRepository := repository.NewURLRepository(dataSources)
+	urlRepository := repository.NewURLRepository(dataSources).InWorkspace(scope{workspace: ws}.workspaceID())
 
 	shortUrLModel, err := urlRepository.Get(ctx, shortKey)
 	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return &shorturlpb.GetLongURLResponse{IsFound: false}, nil
+		}
+		if errors.Is(err, repository.ErrExpired) {
+			return &shorturlpb.GetLongURLResponse{IsFound: true, IsExpired: true}, nil
+		}
 		return nil, err
 	}
-	resp := &shorturlpb.GetLongURLResponse{LongUrl: shortUrLModel.LongURL}
+
+	// 本月点击数超出工作区配额时停止跳转
+	if err := checkClickQuota(ctx, ws); err != nil {
+		return nil, err
+	}
+
+	redirectCode := shortUrLModel.RedirectCode
+	if !model.IsValidRedirectCode(redirectCode) {
+		// 兼容没有记录重定向状态码的旧数据
+		redirectCode = model.DefaultRedirectCode
+	}
+	resp := &shorturlpb.GetLongURLResponse{
+		LongUrl:      shortUrLModel.LongURL,
+		IsFound:      true,
+		RedirectCode: int32(redirectCode),
+	}
+
+	// 异步记录点击，不阻塞跳转
+	if req.GetVisitor() != nil {
+		analytics.GetRecorder().Record(analytics.NewClick(shortUrLModel.WorkspaceID, shortKey, req.GetVisitor(), time.Now()))
+	}
 
 	// 6. 返回结果
 	return resp, nil
 }
 
+// GetAllShortLink 按游标分页查询短链接，支持按创建时间、过期状态、目标域名过滤和排序
+// 启用认证时只返回调用方自己的短链接，管理员指定 all_users 时返回所有用户的短链接
 func (s *Service) GetAllShortLink(ctx context.Context, req *shorturlpb.GetAllShortLinkRequest) (*shorturlpb.GetAllShortLinkResponse, error) {
+	q, err := buildListQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := resolveScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if q.OwnerID, err = sc.listScope(req.GetAllUsers()); err != nil {
+		return nil, err
+	}
 
 	dataSources, err := repository.GetDataSources()
 	if err != nil {
 		return nil, err
 	}
-	urlRepository := repository.NewURLRepository(dataSources)
+	urlRepository := repository.NewURLRepository(dataSources).InWorkspace(sc.workspaceID())
 
-	shortURLModels, err := urlRepository.GetAll(ctx, "short")
+	shortURLModels, hasMore, err := urlRepository.List(ctx, q)
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return nil, status.Errorf(codes.Internal, "查询短链接失败: %v", err)
 	}
 
-	var result []*shorturlpb.ShortLink
-	for _, v := range *shortURLModels {
-		result = append(result, &shorturlpb.ShortLink{
-			ShortLink: v.ShortCode,
-			LongLink:  v.LongURL,
-		})
+	resp := &shorturlpb.GetAllShortLinkResponse{}
+	baseURL := domain.GetRegistry().BaseURL(ctx, sc.workspaceID())
+	for _, v := range shortURLModels {
+		resp.ShortLinks = append(resp.ShortLinks, toShortLinkPB(v, baseURL))
+	}
+	if hasMore && len(shortURLModels) > 0 {
+		resp.NextPageToken = nextPageToken(req, q, shortURLModels[len(shortURLModels)-1])
 	}
-
-	resp := &shorturlpb.GetAllShortLinkResponse{ShortLinks: result}
 
 	// 6. 返回结果
 	return resp, nil
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/username/shorturl/internal/repository"
+	shorturlpb "github.com/username/shorturl/internal/rpc/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultStatsRange 未指定区间时统计最近 7 天
+	defaultStatsRange = 7 * 24 * time.Hour
+	// maxHourlyBuckets 按小时统计时允许的最大桶数（约 31 天）
+	maxHourlyBuckets = 31 * 24
+	defaultTopN      = 10
+	maxTopN          = 100
+)
+
+func (s *Service) GetLinkStats(ctx context.Context, req *shorturlpb.GetLinkStatsRequest) (*shorturlpb.GetLinkStatsResponse, error) {
+	// 1. 校验参数
+	if req.GetShortKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_key 不能为空")
+	}
+
+	var bucket time.Duration
+	switch req.GetGranularity() {
+	case "", "day":
+		bucket = 24 * time.Hour
+	case "hour":
+		bucket = time.Hour
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "不支持的统计粒度: %s", req.GetGranularity())
+	}
+
+	to := time.Now()
+	if req.GetTo() != 0 {
+		to = time.Unix(req.GetTo(), 0)
+	}
+	from := to.Add(-defaultStatsRange)
+	if req.GetFrom() != 0 {
+		from = time.Unix(req.GetFrom(), 0)
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from 必须早于 to")
+	}
+	if bucket == time.Hour && to.Sub(from) > maxHourlyBuckets*time.Hour {
+		return nil, status.Error(codes.InvalidArgument, "按小时统计的区间不能超过 31 天")
+	}
+
+	topN := int(req.GetTopN())
+	if topN <= 0 {
+		topN = defaultTopN
+	}
+	if topN > maxTopN {
+		topN = maxTopN
+	}
+
+	// 2. 查询统计，只统计调用方所在工作区的点击
+	sc, err := resolveScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dataSources, err := repository.GetDataSources()
+	if err != nil {
+		return nil, err
+	}
+	clickRepository := repository.NewClickRepository(dataSources)
+	stats, err := clickRepository.GetStats(ctx, sc.workspaceID(), req.GetShortKey(), from, to, bucket, topN)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "查询点击统计失败: %v", err)
+	}
+
+	// 3. 转换结果
+	resp := &shorturlpb.GetLinkStatsResponse{TotalClicks: stats.TotalClicks}
+	for _, b := range stats.Buckets {
+		resp.Buckets = append(resp.Buckets, &shorturlpb.ClickBucket{Start: b.Start.Unix(), Count: b.Count})
+	}
+	for _, e := range stats.TopReferrers {
+		resp.TopReferrers = append(resp.TopReferrers, &shorturlpb.CountEntry{Value: e.Value, Count: e.Count})
+	}
+	for _, e := range stats.TopUserAgents {
+		resp.TopUserAgents = append(resp.TopUserAgents, &shorturlpb.CountEntry{Value: e.Value, Count: e.Count})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/username/shorturl/internal/config"
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/ratelimit"
+	"github.com/username/shorturl/internal/repository"
+	domain "github.com/username/shorturl/internal/service/domain"
+	"github.com/username/shorturl/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// clickUsageTTL 工作区本月点击数的缓存时长，点击配额允许在这段时间内少量超出
+const clickUsageTTL = time.Minute
+
+// scope 调用方所在的工作区以及在其中的所有权
+type scope struct {
+	owner
+	// workspace 调用方选择的工作区，默认工作区为 nil
+	workspace *model.Workspace
+}
+
+// workspaceID 调用方所在的工作区 id
+func (s scope) workspaceID() int64 {
+	if s.workspace == nil {
+		return model.DefaultWorkspaceID
+	}
+	return s.workspace.ID
+}
+
+// resolveScope 取得调用方的用户与工作区
+// 启用认证时只有工作区成员和全局管理员可以访问工作区，工作区管理员在工作区内视为管理员
+func resolveScope(ctx context.Context) (scope, error) {
+	o, err := resolveOwner(ctx)
+	if err != nil {
+		return scope{}, err
+	}
+	ws, err := resolveWorkspace(ctx)
+	if err != nil {
+		return scope{}, err
+	}
+	if ws == nil {
+		return scope{owner: o}, checkTenantLimit(ctx, nil)
+	}
+	if o.userID != 0 && !o.admin {
+		dataSources, err := repository.GetDataSources()
+		if err != nil {
+			return scope{}, err
+		}
+		member, err := repository.NewWorkspaceRepository(dataSources).GetMember(ctx, ws.ID, o.userID)
+		if err != nil {
+			return scope{}, status.Errorf(codes.Unavailable, "查询工作区成员失败: %v", err)
+		}
+		if member == nil {
+			return scope{}, status.Errorf(codes.PermissionDenied, "不是工作区 %s 的成员", ws.Slug)
+		}
+		o.admin = member.Role == model.WorkspaceRoleAdmin
+	}
+	if err := checkTenantLimit(ctx, ws); err != nil {
+		return scope{}, err
+	}
+	return scope{owner: o, workspace: ws}, nil
+}
+
+// checkTenantLimit 按工作区限流，只能在确认调用方可以使用 ws 之后调用，ws 为 nil 时计入默认工作区
+func checkTenantLimit(ctx context.Context, ws *model.Workspace) error {
+	slug := ""
+	if ws != nil {
+		slug = ws.Slug
+	}
+	return ratelimit.CheckTenant(ctx, slug)
+}
+
+// resolveWorkspace 按 ctx 中的 slug 查找工作区，未指定时返回 nil（默认工作区）
+func resolveWorkspace(ctx context.Context) (*model.Workspace, error) {
+	slug := tenant.FromContext(ctx)
+	if slug == "" {
+		return nil, nil
+	}
+	dataSources, err := repository.GetDataSources()
+	if err != nil {
+		return nil, err
+	}
+	ws, err := repository.NewWorkspaceRepository(dataSources).GetBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrWorkspaceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "工作区 %s 不存在", slug)
+		}
+		return nil, status.Errorf(codes.Unavailable, "查询工作区失败: %v", err)
+	}
+	return ws, nil
+}
+
+// resolveRedirectWorkspace 跳转时选择工作区：已登记的自定义域名使用所属的工作区，其他 Host 按 slug 选择
+// 域名已登记但未验证时返回 domain.ErrNotVerified
+func resolveRedirectWorkspace(ctx context.Context, host string) (*model.Workspace, error) {
+	ws, err := domain.GetRegistry().Resolve(ctx, host)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotVerified) {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Unavailable, "查询域名失败: %v", err)
+	}
+	if ws != nil {
+		return ws, nil
+	}
+	return resolveWorkspace(ctx)
+}
+
+// linkDefaults 创建短链接时使用的默认重定向状态码、默认有效期和最长有效期，工作区未设置的项使用全局配置
+func linkDefaults(ws *model.Workspace) (redirectCode int, defaultTTL, maxTTL time.Duration) {
+	cfg := config.GetConfig()
+	redirectCode, defaultTTL, maxTTL = model.DefaultRedirectCode, cfg.LinkDefaultTTL, cfg.LinkMaxTTL
+	if ws == nil {
+		return
+	}
+	if ws.Settings.DefaultRedirectCode != 0 {
+		redirectCode = ws.Settings.DefaultRedirectCode
+	}
+	if ws.Settings.LinkDefaultTTL != 0 {
+		defaultTTL = ws.Settings.LinkDefaultTTL
+	}
+	if ws.Settings.LinkMaxTTL != 0 {
+		maxTTL = ws.Settings.LinkMaxTTL
+	}
+	return
+}
+
+// remainingLinks 工作区还能创建的短链接数量，-1 表示不限制
+// 按数据库中的数量计算，写后队列中尚未落库的短链接不计入，并发创建时可能少量超出
+func (s scope) remainingLinks(ctx context.Context) (int64, error) {
+	if s.workspace == nil || s.workspace.MaxLinks <= 0 {
+		return -1, nil
+	}
+	dataSources, err := repository.GetDataSources()
+	if err != nil {
+		return 0, err
+	}
+	count, err := repository.NewWorkspaceRepository(dataSources).CountLinks(ctx, s.workspace.ID)
+	if err != nil {
+		return 0, status.Errorf(codes.Unavailable, "查询工作区用量失败: %v", err)
+	}
+	return max(s.workspace.MaxLinks-count, 0), nil
+}
+
+// linkQuotaError 超出短链接数量配额
+func linkQuotaError(ws *model.Workspace) error {
+	return status.Errorf(codes.ResourceExhausted, "工作区 %s 的短链接数量已达上限 %d", ws.Slug, ws.MaxLinks)
+}
+
+// clickUsage 工作区本月点击数的缓存
+type clickUsage struct {
+	month     time.Time
+	count     int64
+	checkedAt time.Time
+}
+
+// clickUsages 工作区 id → clickUsage
+var clickUsages sync.Map
+
+// checkClickQuota 工作区本月（UTC 自然月）的点击数达到上限时返回 ResourceExhausted
+// 点击数缓存 clickUsageTTL，统计失败时放行，不因统计故障中断跳转
+func checkClickQuota(ctx context.Context, ws *model.Workspace) error {
+	if ws == nil || ws.MaxMonthlyClicks <= 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var usage clickUsage
+	if v, ok := clickUsages.Load(ws.ID); ok {
+		usage = v.(clickUsage)
+	}
+	if !usage.month.Equal(month) || now.Sub(usage.checkedAt) >= clickUsageTTL {
+		dataSources, err := repository.GetDataSources()
+		if err != nil {
+			return nil
+		}
+		count, err := repository.NewClickRepository(dataSources).CountSince(ctx, ws.ID, month)
+		if err != nil {
+			log.Printf("failed to count clicks of workspace %s: %v", ws.Slug, err)
+			return nil
+		}
+		usage = clickUsage{month: month, count: count, checkedAt: now}
+		clickUsages.Store(ws.ID, usage)
+	}
+	if usage.count >= ws.MaxMonthlyClicks {
+		return status.Errorf(codes.ResourceExhausted, "工作区 %s 本月的点击数已达上限 %d", ws.Slug, ws.MaxMonthlyClicks)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/username/shorturl/internal/auth"
+	"github.com/username/shorturl/internal/db"
+	"github.com/username/shorturl/internal/db/migrate"
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+	shorturlpb "github.com/username/shorturl/internal/rpc/proto"
+	"github.com/username/shorturl/internal/tenant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// useTestSQLite 把全局数据源替换为只有内存 SQLite 的数据源，测试结束后恢复
+func useTestSQLite(t *testing.T) *repository.DataSources {
+	t.Helper()
+	ctx := context.Background()
+	database, err := db.NewSQLiteDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteDB() error = %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if err := migrate.UpAll(ctx, database); err != nil {
+		t.Fatalf("UpAll() error = %v", err)
+	}
+	previous := repository.GloablDataSources
+	repository.GloablDataSources = &repository.DataSources{SQLiteDB: database}
+	t.Cleanup(func() { repository.GloablDataSources = previous })
+	return repository.GloablDataSources
+}
+
+// createTestWorkspace 创建工作区；工作区按 slug 在进程内缓存，每个测试使用不同的 slug
+func createTestWorkspace(t *testing.T, sources *repository.DataSources, ws *model.Workspace) *model.Workspace {
+	t.Helper()
+	if err := repository.NewWorkspaceRepository(sources).Create(context.Background(), ws); err != nil {
+		t.Fatalf("Create(%s) error = %v", ws.Slug, err)
+	}
+	t.Cleanup(func() { clickUsages.Delete(ws.ID) })
+	return ws
+}
+
+// TestWorkspaceQuotas 测试短链接数量和本月点击数达到上限时返回 ResourceExhausted
+func TestWorkspaceQuotas(t *testing.T) {
+	sources := useTestSQLite(t)
+	ws := createTestWorkspace(t, sources, &model.Workspace{Slug: "quota-ws", Name: "Quota", MaxLinks: 1, MaxMonthlyClicks: 1})
+	ctx := tenant.WithWorkspace(context.Background(), ws.Slug)
+	s := &Service{}
+
+	if _, err := s.CreateShortLink(ctx, "https://example.com/1", CreateOptions{CustomAlias: "quota1"}); err != nil {
+		t.Fatalf("first CreateShortLink() error = %v", err)
+	}
+	if _, err := s.CreateShortLink(ctx, "https://example.com/2", CreateOptions{CustomAlias: "quota2"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("CreateShortLink() over MaxLinks error = %v, want ResourceExhausted", err)
+	}
+	if remaining, err := (scope{workspace: ws}).remainingLinks(ctx); err != nil || remaining != 0 {
+		t.Errorf("remainingLinks() = %d, %v, want 0", remaining, err)
+	}
+
+	// 本月还没有点击时可以跳转，点击数达到上限后停止跳转
+	if resp, err := s.GetLongURL(ctx, &shorturlpb.GetLongURLRequest{ShortKey: "quota1"}); err != nil || !resp.GetIsFound() {
+		t.Fatalf("GetLongURL() = %v, %v, want found", resp, err)
+	}
+	if err := repository.NewClickRepository(sources).SaveBatch(ctx, []model.Click{{WorkspaceID: ws.ID, ShortCode: "quota1", ClickedAt: time.Now()}}); err != nil {
+		t.Fatalf("SaveBatch() error = %v", err)
+	}
+	clickUsages.Delete(ws.ID)
+	if _, err := s.GetLongURL(ctx, &shorturlpb.GetLongURLRequest{ShortKey: "quota1"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("GetLongURL() over MaxMonthlyClicks error = %v, want ResourceExhausted", err)
+	}
+	if err := checkClickQuota(ctx, nil); err != nil {
+		t.Errorf("checkClickQuota() for the default workspace error = %v, want nil", err)
+	}
+}
+
+// TestWorkspaceMembership 测试启用认证时只有工作区成员和全局管理员可以访问工作区
+func TestWorkspaceMembership(t *testing.T) {
+	sources := useTestSQLite(t)
+	ws := createTestWorkspace(t, sources, &model.Workspace{Slug: "members-ws", Name: "Members"})
+	ctx := tenant.WithWorkspace(context.Background(), ws.Slug)
+	as := func(subject string, roles ...string) context.Context {
+		return auth.WithIdentity(ctx, &auth.Identity{Subject: subject, Roles: roles, Method: auth.MethodJWT})
+	}
+	s := &Service{}
+
+	if _, err := s.CreateShortLink(as("outsider"), "https://example.com", CreateOptions{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("CreateShortLink() by a non-member error = %v, want PermissionDenied", err)
+	}
+	if _, err := s.CreateShortLink(as("root", auth.RoleAdmin), "https://example.com", CreateOptions{}); err != nil {
+		t.Errorf("CreateShortLink() by a global admin error = %v", err)
+	}
+
+	user, err := repository.NewUserRepository(sources).GetOrCreate(ctx, "outsider")
+	if err != nil {
+		t.Fatalf("GetOrCreate() error = %v", err)
+	}
+	if err := repository.NewWorkspaceRepository(sources).SetMember(ctx, &model.WorkspaceMember{WorkspaceID: ws.ID, UserID: user.ID, Role: model.WorkspaceRoleMember}); err != nil {
+		t.Fatalf("SetMember() error = %v", err)
+	}
+	if _, err := s.CreateShortLink(as("outsider"), "https://example.com", CreateOptions{}); err != nil {
+		t.Errorf("CreateShortLink() by a member error = %v", err)
+	}
+
+	if _, err := s.CreateShortLink(tenant.WithWorkspace(context.Background(), "no-such-ws"), "https://example.com", CreateOptions{}); status.Code(err) != codes.NotFound {
+		t.Errorf("CreateShortLink() in a missing workspace error = %v, want NotFound", err)
+	}
+}
+
+// TestWorkspaceShortCodeIsolation 测试同一短码可以分别在两个工作区创建，跳转到各自的目标
+func TestWorkspaceShortCodeIsolation(t *testing.T) {
+	sources := useTestSQLite(t)
+	a := createTestWorkspace(t, sources, &model.Workspace{Slug: "iso-a", Name: "A"})
+	b := createTestWorkspace(t, sources, &model.Workspace{Slug: "iso-b", Name: "B"})
+	s := &Service{}
+
+	targets := map[string]string{"": "https://example.com/default", a.Slug: "https://example.com/a", b.Slug: "https://example.com/b"}
+	for slug, target := range targets {
+		ctx := tenant.WithWorkspace(context.Background(), slug)
+		if _, err := s.CreateShortLink(ctx, target, CreateOptions{CustomAlias: "shared"}); err != nil {
+			t.Fatalf("CreateShortLink(%q) error = %v", slug, err)
+		}
+	}
+	for slug, target := range targets {
+		ctx := tenant.WithWorkspace(context.Background(), slug)
+		resp, err := s.GetLongURL(ctx, &shorturlpb.GetLongURLRequest{ShortKey: "shared"})
+		if err != nil || resp.GetLongUrl() != target {
+			t.Errorf("GetLongURL(%q) = %v, %v, want %s", slug, resp, err, target)
+		}
+		if _, err := s.CreateShortLink(ctx, target, CreateOptions{CustomAlias: "shared"}); status.Code(err) != codes.AlreadyExists {
+			t.Errorf("CreateShortLink(%q) again error = %v, want AlreadyExists", slug, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/username/shorturl/internal/db/model"
+)
+
+// DeadLetter 无法写入数据库的短链接
+type DeadLetter struct {
+	URL      model.ShortURL `json:"url"`
+	Reason   string         `json:"reason"`
+	Attempts int            `json:"attempts"`
+	FailedAt time.Time      `json:"failed_at"`
+}
+
+func newDeadLetter(url *model.ShortURL, err error, attempts int) DeadLetter {
+	return DeadLetter{URL: *url, Reason: err.Error(), Attempts: attempts, FailedAt: time.Now()}
+}
+
+// DeadLetterStore 保存死信，数据库不可用时死信不能再依赖数据库
+type DeadLetterStore interface {
+	Put(letters []DeadLetter) error
+}
+
+// FileDeadLetterStore 以 JSON Lines 格式追加写入本地文件
+type FileDeadLetterStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileDeadLetterStore 创建写入 path 的死信存储，目录不存在时自动创建
+func NewFileDeadLetterStore(path string) *FileDeadLetterStore {
+	return &FileDeadLetterStore{path: path}
+}
+
+// Put 追加写入并同步到磁盘
+func (s *FileDeadLetterStore) Put(letters []DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, letter := range letters {
+		if err := enc.Encode(letter); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
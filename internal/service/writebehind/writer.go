@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/username/shorturl/internal/config"
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+)
+
+const (
+	defaultBatchSize     = 200
+	defaultFlushInterval = 100 * time.Millisecond
+	defaultRetryBackoff  = 200 * time.Millisecond
+	// flushTimeout 单次批量写入数据库的超时时间
+	flushTimeout = 10 * time.Second
+	// maxRetryBackoff 重试等待时间的上限
+	maxRetryBackoff = 30 * time.Second
+)
+
+// Options 写后队列的参数
+type Options struct {
+	// QueueSize 队列长度，队列满时改为同步写入数据库
+	QueueSize int
+	// BatchSize 单次批量写入的最大条数
+	BatchSize int
+	// FlushInterval 未凑满一批时的最长等待时间
+	FlushInterval time.Duration
+	// MaxRetries 数据库写入失败（非冲突）时的最大重试次数，之后写入死信
+	MaxRetries int
+	// RetryBackoff 第一次重试前的等待时间，之后每次翻倍
+	RetryBackoff time.Duration
+}
+
+// Stats 写后队列的状态
+type Stats struct {
+	// Depth 已写入缓存、尚未写入数据库的条数（包括正在写入的一批）
+	Depth int
+	// Lag 最早一条尚未写入数据库的数据已等待的时长
+	Lag time.Duration
+	// Persisted 已写入数据库的条数
+	Persisted int64
+	// Retries 重试写入的条数（同一条重试多次时重复计数）
+	Retries int64
+	// DeadLettered 写入死信的条数
+	DeadLettered int64
+	// SyncWrites 队列已满或已关闭时同步写入的条数
+	SyncWrites int64
+}
+
+// pendingWrite 队列中的一条待写入数据
+type pendingWrite struct {
+	url *model.ShortURL
+}
+
+// Writer 写后（write-behind）持久化：短链接先同步写入缓存，数据库写入进入队列后由后台批量完成
+// 数据库写入失败时按指数退避重试，短码冲突或重试耗尽的数据写入死信，并清除缓存中的数据
+type Writer struct {
+	repo        repository.URLRepository
+	deadLetters DeadLetterStore
+	opts        Options
+
+	mu     sync.RWMutex // 保护 closed，避免向已关闭的队列写入
+	closed bool
+	queue  chan pendingWrite
+	done   chan struct{}
+
+	// enqueuedAt 按入队顺序记录尚未写入数据库（包括正在写入）的数据的入队时间，用于计算深度和延迟
+	pendingMu  sync.Mutex
+	enqueuedAt []time.Time
+
+	persisted    atomic.Int64
+	retries      atomic.Int64
+	deadLettered atomic.Int64
+	syncWrites   atomic.Int64
+}
+
+// NewWriter 创建写后队列并启动后台写入 goroutine，未设置的参数使用默认值
+func NewWriter(repo repository.URLRepository, deadLetters DeadLetterStore, opts Options) *Writer {
+	if opts.QueueSize < 0 {
+		opts.QueueSize = 0
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultFlushInterval
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = defaultRetryBackoff
+	}
+	w := &Writer{
+		repo:        repo,
+		deadLetters: deadLetters,
+		opts:        opts,
+		queue:       make(chan pendingWrite, opts.QueueSize),
+		done:        make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+var (
+	writerOnce    sync.Once
+	defaultWriter *Writer
+)
+
+// GetWriter 获取进程内共享的写后队列，未启用时返回 nil
+func GetWriter() *Writer {
+	writerOnce.Do(func() {
+		cfg := config.GetConfig().WriteBehind
+		if !cfg.Enabled {
+			return
+		}
+		dataSources, _ := repository.GetDataSources()
+		defaultWriter = NewWriter(repository.NewURLRepository(dataSources), NewFileDeadLetterStore(cfg.DeadLetterPath), Options{
+			QueueSize:     cfg.QueueSize,
+			BatchSize:     cfg.BatchSize,
+			FlushInterval: cfg.FlushInterval,
+			MaxRetries:    cfg.MaxRetries,
+			RetryBackoff:  cfg.RetryBackoff,
+		})
+	})
+	return defaultWriter
+}
+
+// Submit 提交已写入缓存的短链接，由后台写入数据库
+// 队列已满或已关闭时同步写入，短码在数据库中已存在时清除缓存并返回 repository.ErrAlreadyExists
+func (w *Writer) Submit(ctx context.Context, url *model.ShortURL) error {
+	w.mu.RLock()
+	if !w.closed {
+		// 先记录入队时间再入队，保证写入完成时一定能找到对应的记录
+		w.pendingMu.Lock()
+		w.enqueuedAt = append(w.enqueuedAt, time.Now())
+		select {
+		case w.queue <- pendingWrite{url: url}:
+			w.pendingMu.Unlock()
+			w.mu.RUnlock()
+			return nil
+		default:
+			w.enqueuedAt = w.enqueuedAt[:len(w.enqueuedAt)-1]
+		}
+		w.pendingMu.Unlock()
+	}
+	w.mu.RUnlock()
+
+	w.syncWrites.Add(1)
+	err := w.repo.InsertBatch(ctx, []*model.ShortURL{url})[0]
+	if err == nil {
+		w.persisted.Add(1)
+	} else if errors.Is(err, repository.ErrAlreadyExists) {
+		w.evict(ctx, url)
+	}
+	return err
+}
+
+// Stats 返回当前状态的快照
+func (w *Writer) Stats() Stats {
+	stats := Stats{
+		Persisted:    w.persisted.Load(),
+		Retries:      w.retries.Load(),
+		DeadLettered: w.deadLettered.Load(),
+		SyncWrites:   w.syncWrites.Load(),
+	}
+	w.pendingMu.Lock()
+	stats.Depth = len(w.enqueuedAt)
+	if stats.Depth > 0 {
+		stats.Lag = time.Since(w.enqueuedAt[0])
+	}
+	w.pendingMu.Unlock()
+	return stats
+}
+
+// Close 停止接收新数据，并把队列中剩余的数据写入数据库；未启用（nil）时直接返回
+func (w *Writer) Close() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+	<-w.done
+
+	stats := w.Stats()
+	log.Printf("write-behind drained: persisted %d, dead-lettered %d", stats.Persisted, stats.DeadLettered)
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]pendingWrite, 0, w.opts.BatchSize)
+	for {
+		select {
+		case p, ok := <-w.queue:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+			batch = append(batch, p)
+			if len(batch) >= w.opts.BatchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// flush 批量写入数据库，失败的数据按指数退避重试
+func (w *Writer) flush(batch []pendingWrite) {
+	if len(batch) == 0 {
+		return
+	}
+	// 队列先进先出，这一批就是最早入队的 len(batch) 条
+	defer w.release(len(batch))
+
+	var dead []DeadLetter
+	todo := batch
+	backoff := w.opts.RetryBackoff
+	for attempt := 1; len(todo) > 0; attempt++ {
+		urls := make([]*model.ShortURL, len(todo))
+		for i, p := range todo {
+			urls[i] = p.url
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+		errs := w.repo.InsertBatch(ctx, urls)
+		cancel()
+
+		var retry []pendingWrite
+		var lastErr error
+		for i, err := range errs {
+			switch {
+			case err == nil:
+				w.persisted.Add(1)
+			case errors.Is(err, repository.ErrAlreadyExists):
+				// 短码已被其他数据占用，缓存中的是落选的数据
+				dead = append(dead, newDeadLetter(todo[i].url, err, attempt))
+				w.evict(context.Background(), todo[i].url)
+			default:
+				retry = append(retry, todo[i])
+				lastErr = err
+			}
+		}
+		if len(retry) == 0 {
+			break
+		}
+		if attempt > w.opts.MaxRetries {
+			log.Printf("write-behind: giving up on %d short URLs after %d attempts: %v", len(retry), attempt, lastErr)
+			for _, p := range retry {
+				dead = append(dead, newDeadLetter(p.url, lastErr, attempt))
+				w.evict(context.Background(), p.url)
+			}
+			break
+		}
+
+		log.Printf("write-behind: failed to persist %d short URLs (attempt %d), retrying in %v: %v", len(retry), attempt, backoff, lastErr)
+		w.retries.Add(int64(len(retry)))
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxRetryBackoff)
+		todo = retry
+	}
+
+	if len(dead) == 0 {
+		return
+	}
+	w.deadLettered.Add(int64(len(dead)))
+	if err := w.deadLetters.Put(dead); err != nil {
+		// 死信也无法保存时只能记录到日志
+		log.Printf("write-behind: failed to store %d dead letters: %v", len(dead), err)
+		for _, d := range dead {
+			log.Printf("write-behind dead letter: %+v", d)
+		}
+	}
+}
+
+// release 一批数据处理完成（写入数据库或死信）后移除对应的入队记录
+func (w *Writer) release(n int) {
+	w.pendingMu.Lock()
+	w.enqueuedAt = w.enqueuedAt[min(n, len(w.enqueuedAt)):]
+	w.pendingMu.Unlock()
+}
+
+// evict 数据无法写入数据库时清除缓存，避免缓存中继续提供未落库的数据
+func (w *Writer) evict(ctx context.Context, url *model.ShortURL) {
+	if err := w.repo.InWorkspace(url.WorkspaceID).DeleteFromCache(ctx, url.ShortCode); err != nil {
+		log.Printf("write-behind: failed to invalidate cache for %s: %v", url.ShortCode, err)
+	}
+}
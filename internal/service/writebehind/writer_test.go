@@ -0,0 +1,146 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/username/shorturl/internal/db/model"
+	"github.com/username/shorturl/internal/repository"
+)
+
+// fakeURLRepository 记录写入数据库和被清除缓存的短码
+type fakeURLRepository struct {
+	repository.URLRepository
+
+	mu          sync.Mutex
+	conflicts   map[string]bool
+	failures    map[string]int // 短码写入失败的剩余次数，-1 表示一直失败
+	inserted    []string
+	batches     []int
+	invalidated []string
+}
+
+func (f *fakeURLRepository) InsertBatch(ctx context.Context, urls []*model.ShortURL) []error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, len(urls))
+	errs := make([]error, len(urls))
+	for i, u := range urls {
+		switch {
+		case f.conflicts[u.ShortCode]:
+			errs[i] = fmt.Errorf("%w: %s", repository.ErrAlreadyExists, u.ShortCode)
+		case f.failures[u.ShortCode] != 0:
+			f.failures[u.ShortCode]--
+			errs[i] = errors.New("database unavailable")
+		default:
+			f.inserted = append(f.inserted, u.ShortCode)
+		}
+	}
+	return errs
+}
+
+// InWorkspace 测试数据都在默认工作区
+func (f *fakeURLRepository) InWorkspace(workspaceID int64) repository.URLRepository {
+	return f
+}
+
+func (f *fakeURLRepository) DeleteFromCache(ctx context.Context, shortCode string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.invalidated = append(f.invalidated, shortCode)
+	return nil
+}
+
+// TestWriterBatchesAndDrainsOnClose 测试按批写入，以及关闭时写完队列中剩余的数据
+func TestWriterBatchesAndDrainsOnClose(t *testing.T) {
+	repo := &fakeURLRepository{}
+	w := NewWriter(repo, NewFileDeadLetterStore(filepath.Join(t.TempDir(), "dead.jsonl")), Options{
+		QueueSize:     10,
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	})
+	for _, code := range []string{"a", "b", "c", "d", "e"} {
+		if err := w.Submit(context.Background(), &model.ShortURL{ShortCode: code}); err != nil {
+			t.Fatalf("Submit(%s) error = %v", code, err)
+		}
+	}
+	w.Close()
+
+	if len(repo.inserted) != 5 {
+		t.Errorf("inserted = %v, want 5 codes", repo.inserted)
+	}
+	if fmt.Sprint(repo.batches) != "[2 2 1]" {
+		t.Errorf("batches = %v, want [2 2 1]", repo.batches)
+	}
+	stats := w.Stats()
+	if stats.Depth != 0 || stats.Lag != 0 || stats.Persisted != 5 {
+		t.Errorf("Stats() = %+v, want depth 0, lag 0, persisted 5", stats)
+	}
+
+	// 关闭后提交的数据同步写入
+	if err := w.Submit(context.Background(), &model.ShortURL{ShortCode: "late"}); err != nil {
+		t.Fatalf("Submit after Close error = %v", err)
+	}
+	if got := w.Stats().SyncWrites; got != 1 {
+		t.Errorf("SyncWrites = %d, want 1", got)
+	}
+}
+
+// TestWriterRetryAndDeadLetter 测试失败重试、冲突和重试耗尽时写入死信并清除缓存
+func TestWriterRetryAndDeadLetter(t *testing.T) {
+	repo := &fakeURLRepository{
+		conflicts: map[string]bool{"taken": true},
+		failures:  map[string]int{"flaky": 2, "broken": -1},
+	}
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+	w := NewWriter(repo, NewFileDeadLetterStore(path), Options{
+		QueueSize:     10,
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+		MaxRetries:    3,
+		RetryBackoff:  time.Millisecond,
+	})
+	for _, code := range []string{"ok", "taken", "flaky", "broken"} {
+		if err := w.Submit(context.Background(), &model.ShortURL{ShortCode: code}); err != nil {
+			t.Fatalf("Submit(%s) error = %v", code, err)
+		}
+	}
+	w.Close()
+
+	if fmt.Sprint(repo.inserted) != "[ok flaky]" {
+		t.Errorf("inserted = %v, want [ok flaky]", repo.inserted)
+	}
+	if fmt.Sprint(repo.invalidated) != "[taken broken]" {
+		t.Errorf("invalidated = %v, want [taken broken]", repo.invalidated)
+	}
+	stats := w.Stats()
+	if stats.Persisted != 2 || stats.DeadLettered != 2 || stats.Retries != 5 {
+		t.Errorf("Stats() = %+v, want persisted 2, dead-lettered 2, retries 5", stats)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open dead letters: %v", err)
+	}
+	defer f.Close()
+	var codes []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var letter DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &letter); err != nil {
+			t.Fatalf("invalid dead letter %q: %v", scanner.Text(), err)
+		}
+		codes = append(codes, fmt.Sprintf("%s/%d", letter.URL.ShortCode, letter.Attempts))
+	}
+	if fmt.Sprint(codes) != "[taken/1 broken/4]" {
+		t.Errorf("dead letters = %v, want [taken/1 broken/4]", codes)
+	}
+}
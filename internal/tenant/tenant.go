@@ -0,0 +1,86 @@
+// Package tenant 在请求链路中传递调用方选择的工作区
+// HTTP 网关从 X-Workspace 请求头读取工作区 slug，通过 gRPC metadata 转发给后端；工作区是否存在、调用方是否为成员由服务端校验
+package tenant
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// Header HTTP 请求中指定工作区 slug 的请求头
+	Header = "X-Workspace"
+	// MetadataWorkspace gRPC metadata 中的工作区 slug
+	MetadataWorkspace = "x-workspace"
+)
+
+type contextKey struct{}
+
+// WithWorkspace 把工作区 slug 写入 ctx，空字符串表示默认工作区
+func WithWorkspace(ctx context.Context, slug string) context.Context {
+	if slug == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, slug)
+}
+
+// FromContext 返回 ctx 中的工作区 slug，未指定时返回空字符串（默认工作区）
+func FromContext(ctx context.Context) string {
+	slug, _ := ctx.Value(contextKey{}).(string)
+	return slug
+}
+
+// UnaryClientInterceptor 把 ctx 中的工作区写入 metadata
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor 同 UnaryClientInterceptor，用于流式调用
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+func outgoingContext(ctx context.Context) context.Context {
+	if slug := FromContext(ctx); slug != "" {
+		return metadata.AppendToOutgoingContext(ctx, MetadataWorkspace, slug)
+	}
+	return ctx
+}
+
+// UnaryServerInterceptor 从 metadata 读取工作区写入 ctx
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(incomingContext(ctx), req)
+	}
+}
+
+// StreamServerInterceptor 同 UnaryServerInterceptor，用于流式调用
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &workspaceStream{ServerStream: ss, ctx: incomingContext(ss.Context())})
+	}
+}
+
+// workspaceStream 替换 ServerStream 的 context
+type workspaceStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *workspaceStream) Context() context.Context {
+	return s.ctx
+}
+
+func incomingContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(MetadataWorkspace); len(v) > 0 {
+		return WithWorkspace(ctx, v[0])
+	}
+	return ctx
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+)
+
+// Base62Alphabet base62 编码使用的字符集（URL 安全）
+const Base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// EncodeBase62 将非负整数编码为 base62 字符串
+func EncodeBase62(n uint64) string {
+	return EncodeWithAlphabet(n, Base62Alphabet)
+}
+
+// EncodeWithAlphabet 使用指定字符集对整数进行进制编码
+func EncodeWithAlphabet(n uint64, alphabet string) string {
+	base := uint64(len(alphabet))
+	if n == 0 {
+		return alphabet[:1]
+	}
+
+	var buf [64]byte
+	i := len(buf)
+	for n > 0 {
+		i--
+		buf[i] = alphabet[n%base]
+		n /= base
+	}
+	return string(buf[i:])
+}
+
+// PadBase62 将 base62 字符串左侧补 0 到指定长度
+func PadBase62(code string, length int) string {
+	if len(code) >= length {
+		return code
+	}
+	return strings.Repeat(Base62Alphabet[:1], length-len(code)) + code
+}
+
+// GenerateBase62Code 使用加密随机数生成指定长度的 base62 短码
+// 与 GenerateShortCode 不同，结果不包含 - 和 _，且每个字符均匀分布
+func GenerateBase62Code(length int) (string, error) {
+	if length <= 0 {
+		return "", &InvalidLengthError{Length: length}
+	}
+
+	max := big.NewInt(int64(len(Base62Alphabet)))
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = Base62Alphabet[n.Int64()]
+	}
+	return string(code), nil
+}
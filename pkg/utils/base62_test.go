@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestEncodeBase62 测试 base62 编码
+func TestEncodeBase62(t *testing.T) {
+	tests := []struct {
+		name string
+		n    uint64
+		want string
+	}{
+		{name: "零", n: 0, want: "0"},
+		{name: "个位", n: 61, want: "z"},
+		{name: "进位", n: 62, want: "10"},
+		{name: "两位最大值", n: 62*62 - 1, want: "zz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EncodeBase62(tt.n)
+			if got != tt.want {
+				t.Errorf("EncodeBase62(%d) = %q, want %q", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPadBase62 测试补齐长度
+func TestPadBase62(t *testing.T) {
+	if got := PadBase62("z", 4); got != "000z" {
+		t.Errorf("PadBase62() = %q, want %q", got, "000z")
+	}
+	if got := PadBase62("abcdef", 4); got != "abcdef" {
+		t.Errorf("PadBase62() = %q, want %q", got, "abcdef")
+	}
+}
+
+// TestGenerateBase62Code 测试随机 base62 短码
+func TestGenerateBase62Code(t *testing.T) {
+	code, err := GenerateBase62Code(8)
+	if err != nil {
+		t.Fatalf("GenerateBase62Code failed: %v", err)
+	}
+	if len(code) != 8 {
+		t.Errorf("Expected length 8, got %d", len(code))
+	}
+	for _, c := range code {
+		if !strings.ContainsRune(Base62Alphabet, c) {
+			t.Errorf("unexpected character %q in %q", c, code)
+		}
+	}
+
+	if _, err := GenerateBase62Code(0); err == nil {
+		t.Error("Expected error for zero length, got nil")
+	}
+}
+
+// BenchmarkGenerateBase62Code 基准测试随机 base62 短码
+func BenchmarkGenerateBase62Code(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = GenerateBase62Code(8)
+	}
+}
@@ -24,6 +24,14 @@ func ValidateURL(rawURL string) bool {
 	return u.Scheme != "" && u.Host != ""
 }
 
+// NormalizeURL 为缺少协议的 URL 补全 http://，用于生成可跳转的 Location
+func NormalizeURL(rawURL string) string {
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
+		return "http://" + rawURL
+	}
+	return rawURL
+}
+
 // IsValidShortCode 验证短码是否有效（只包含字母数字和连字符）
 func IsValidShortCode(code string) bool {
 	if len(code) < 4 || len(code) > 20 {
@@ -55,6 +55,40 @@ func TestValidateURL(t *testing.T) {
 	}
 }
 
+// TestNormalizeURL 测试 URL 协议补全
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "没有协议的 URL",
+			url:  "www.google.com",
+			want: "http://www.google.com",
+		},
+		{
+			name: "已有 HTTP 协议",
+			url:  "http://example.com/a",
+			want: "http://example.com/a",
+		},
+		{
+			name: "已有 HTTPS 协议",
+			url:  "https://example.com",
+			want: "https://example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeURL(tt.url)
+			if got != tt.want {
+				t.Errorf("NormalizeURL(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
 // TestIsValidShortCode 测试短码验证
 func TestIsValidShortCode(t *testing.T) {
 	tests := []struct {
@@ -13,3 +13,11 @@ grpcurl -plaintext ip:port list|xargs -I {} grpcurl -plaintext ip:port describe
 
 grpcurl -plaintext localhost:9090 list|xargs -I {} grpcurl -plaintext localhost:9090 describe {}  > all.txt
 
+
+grpcurl -plaintext -d '{"clipboarder_content":"hello clipboard"}' localhost:9091 clipboarder.ClipboarderService/SaveClipboard
+
+# 指定有效期（秒）/ 永不过期
+grpcurl -plaintext -d '{"long_url":"www.google.com","expires_in":3600}' localhost:9090 shortener.ShortenerService/CreateShortLink
+grpcurl -plaintext -d '{"long_url":"www.google.com","never_expires":true}' localhost:9090 shortener.ShortenerService/CreateShortLink
+grpcurl -plaintext -d '{"status":"LINK_STATUS_ACTIVE","batch_size":200}' localhost:9090 shortener.ShortenerService/StreamShortLinks
+grpcurl -plaintext -d '{"items":[{"long_url":"www.google.com"},{"long_url":"www.baidu.com","custom_alias":"baidu1"}]}' localhost:9090 shortener.ShortenerService/BatchCreateShortLinks
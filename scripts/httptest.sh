@@ -3,4 +3,38 @@ curl -X POST http://localhost:8080/shortener/v1/c \
      -d '{"long_url":"www.baidu.com"}'
 
 
-curl http://localhost:8080/shortener/v1/all
\ No newline at end of file
+curl http://localhost:8080/shortener/v1/all
+# 分页、过滤与排序（next_page_token 作为下一次请求的 page_token）
+curl "http://localhost:8080/shortener/v1/all?page_size=20&status=active&host=example.com&sort=created_asc&created_after=2025-01-01T00:00:00Z"
+# 流式导出（format=ndjson|csv，过滤参数同 /all）
+curl -o links.csv "http://localhost:8080/shortener/v1/export?format=csv&status=active"
+
+# 短链跳转（返回 301/302/307/308 及 Location）
+curl -i http://localhost:8080/{short_key}
+
+# 剪贴板
+curl -X POST http://localhost:8080/clipboard/v1/c \
+     -H "Content-Type: application/json" \
+     -d '{"content":"hello clipboard"}'
+
+curl http://localhost:8080/clipboard/v1/{short_key}
+
+# 自定义短码
+curl -X POST http://localhost:8080/shortener/v1/c \
+     -H "Content-Type: application/json" \
+     -d '{"long_url":"www.baidu.com","custom_alias":"q3-report"}'
+
+# 点击统计
+curl "http://localhost:8080/shortener/v1/stats/{short_key}?granularity=hour&top=5"
+
+# 修改 / 删除短链接
+curl -X PATCH http://localhost:8080/shortener/v1/{short_key} \
+     -H "Content-Type: application/json" \
+     -d '{"long_url":"www.bing.com","redirect_code":301}'
+
+curl -X DELETE http://localhost:8080/shortener/v1/{short_key}
+
+# 批量创建（每一条的字段同 /c，按顺序返回每一条的结果）
+curl -X POST http://localhost:8080/shortener/v1/batch \
+     -H "Content-Type: application/json" \
+     -d '{"items":[{"long_url":"https://example.com/a"},{"long_url":"https://example.com/b","custom_alias":"promo-b"}]}'